@@ -2,23 +2,74 @@ package main
 
 import (
 	"errors"
+	"github.com/vaberof/hezzl-backend/internal/app/entrypoint/blobdeletion"
+	"github.com/vaberof/hezzl-backend/internal/app/entrypoint/http"
+	"github.com/vaberof/hezzl-backend/internal/app/entrypoint/retention"
+	"github.com/vaberof/hezzl-backend/internal/app/goodimport"
 	"github.com/vaberof/hezzl-backend/internal/infra/messagebroker/nats/publisher"
 	"github.com/vaberof/hezzl-backend/internal/infra/messagebroker/nats/subscriber"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage/blob"
 	"github.com/vaberof/hezzl-backend/pkg/config"
 	"github.com/vaberof/hezzl-backend/pkg/database/clickhouse"
 	"github.com/vaberof/hezzl-backend/pkg/database/postgres"
 	"github.com/vaberof/hezzl-backend/pkg/database/redis"
+	"github.com/vaberof/hezzl-backend/pkg/health"
 	"github.com/vaberof/hezzl-backend/pkg/http/httpserver"
+	"github.com/vaberof/hezzl-backend/pkg/lifecycle"
+	"github.com/vaberof/hezzl-backend/pkg/logging"
+	"github.com/vaberof/hezzl-backend/pkg/ratelimit"
+	"github.com/vaberof/hezzl-backend/pkg/tracing"
+	"log/slog"
 	"os"
 )
 
 type AppConfig struct {
+	Logger         logging.Config
 	Server         httpserver.ServerConfig
 	Postgres       postgres.Config
 	Redis          redis.Config
 	ClickHouse     clickhouse.Config
 	NatsPublisher  publisher.Config
 	NatsSubscriber subscriber.Config
+	Tracing        tracing.Config
+	Health         health.Config
+	Lifecycle      lifecycle.Config
+	RateLimit      ratelimit.Config
+	Idempotency    http.IdempotencyConfig
+	Auth           http.AuthConfig
+	Batch          http.BatchConfig
+	Retention      retention.Config
+	BlobDeletion   blobdeletion.Config
+	Import         goodimport.Config
+	Export         http.ExportConfig
+	Attachment     http.AttachmentConfig
+	BlobStore      blob.Config
+}
+
+// LogValue lets the config be logged as a structured group; secrets are redacted by the nested configs.
+func (appConfig AppConfig) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Any("logger", appConfig.Logger),
+		slog.Any("server", appConfig.Server),
+		slog.Any("postgres", appConfig.Postgres),
+		slog.Any("redis", appConfig.Redis),
+		slog.Any("clickhouse", appConfig.ClickHouse),
+		slog.Any("natsPublisher", appConfig.NatsPublisher),
+		slog.Any("natsSubscriber", appConfig.NatsSubscriber),
+		slog.Any("tracing", appConfig.Tracing),
+		slog.Any("health", appConfig.Health),
+		slog.Any("lifecycle", appConfig.Lifecycle),
+		slog.Any("rateLimit", appConfig.RateLimit),
+		slog.Any("idempotency", appConfig.Idempotency),
+		slog.Any("auth", appConfig.Auth),
+		slog.Any("batch", appConfig.Batch),
+		slog.Any("retention", appConfig.Retention),
+		slog.Any("blobDeletion", appConfig.BlobDeletion),
+		slog.Any("import", appConfig.Import),
+		slog.Any("export", appConfig.Export),
+		slog.Any("attachment", appConfig.Attachment),
+		slog.Any("blobStore", appConfig.BlobStore),
+	)
 }
 
 func mustGetAppConfig(sources ...string) AppConfig {
@@ -41,8 +92,14 @@ func tryGetAppConfig(sources ...string) (*AppConfig, error) {
 
 	provider := config.MergeConfigs(sources)
 
+	var loggerConfig logging.Config
+	err := config.ParseConfig(provider, "app.logger", &loggerConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	var serverConfig httpserver.ServerConfig
-	err := config.ParseConfig(provider, "app.http.server", &serverConfig)
+	err = config.ParseConfig(provider, "app.http.server", &serverConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -81,13 +138,107 @@ func tryGetAppConfig(sources ...string) (*AppConfig, error) {
 		return nil, err
 	}
 
+	var tracingConfig tracing.Config
+	err = config.ParseConfig(provider, "app.tracing", &tracingConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var healthConfig health.Config
+	err = config.ParseConfig(provider, "app.health", &healthConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var lifecycleConfig lifecycle.Config
+	err = config.ParseConfig(provider, "app.lifecycle", &lifecycleConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var rateLimitConfig ratelimit.Config
+	err = config.ParseConfig(provider, "app.http.rate_limit", &rateLimitConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var idempotencyConfig http.IdempotencyConfig
+	err = config.ParseConfig(provider, "app.http.idempotency", &idempotencyConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var authConfig http.AuthConfig
+	authConfig.ApiKeys, err = http.ParseApiKeys(os.Getenv("API_KEYS"))
+	if err != nil {
+		return nil, err
+	}
+
+	var batchConfig http.BatchConfig
+	err = config.ParseConfig(provider, "app.http.batch", &batchConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var importConfig goodimport.Config
+	err = config.ParseConfig(provider, "app.http.import", &importConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var exportConfig http.ExportConfig
+	err = config.ParseConfig(provider, "app.http.export", &exportConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var attachmentConfig http.AttachmentConfig
+	err = config.ParseConfig(provider, "app.http.attachment", &attachmentConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var blobStoreConfig blob.Config
+	err = config.ParseConfig(provider, "app.blob_store", &blobStoreConfig)
+	if err != nil {
+		return nil, err
+	}
+	blobStoreConfig.S3.AccessKeyId = os.Getenv("S3_ACCESS_KEY_ID")
+	blobStoreConfig.S3.SecretAccessKey = os.Getenv("S3_SECRET_ACCESS_KEY")
+
+	var retentionConfig retention.Config
+	err = config.ParseConfig(provider, "app.retention", &retentionConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var blobDeletionConfig blobdeletion.Config
+	err = config.ParseConfig(provider, "app.blob_deletion", &blobDeletionConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	appConfig := AppConfig{
+		Logger:         loggerConfig,
 		Server:         serverConfig,
 		Postgres:       postgresConfig,
 		Redis:          redisConfig,
 		ClickHouse:     clickHouseConfig,
 		NatsPublisher:  natsPublisher,
 		NatsSubscriber: natsSubscriber,
+		Tracing:        tracingConfig,
+		Health:         healthConfig,
+		Lifecycle:      lifecycleConfig,
+		RateLimit:      rateLimitConfig,
+		Idempotency:    idempotencyConfig,
+		Auth:           authConfig,
+		Batch:          batchConfig,
+		Retention:      retentionConfig,
+		BlobDeletion:   blobDeletionConfig,
+		Import:         importConfig,
+		Export:         exportConfig,
+		Attachment:     attachmentConfig,
+		BlobStore:      blobStoreConfig,
 	}
 
 	return &appConfig, nil
@@ -3,25 +3,40 @@ package main
 import (
 	"context"
 	"flag"
-	"fmt"
 	"github.com/joho/godotenv"
+	"github.com/vaberof/hezzl-backend/internal/app/entrypoint/blobdeletion"
 	"github.com/vaberof/hezzl-backend/internal/app/entrypoint/http"
+	"github.com/vaberof/hezzl-backend/internal/app/entrypoint/retention"
+	"github.com/vaberof/hezzl-backend/internal/app/goodimport"
 	"github.com/vaberof/hezzl-backend/internal/domain/good"
 	"github.com/vaberof/hezzl-backend/internal/infra/messagebroker/nats/publisher"
 	"github.com/vaberof/hezzl-backend/internal/infra/messagebroker/nats/subscriber"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage/blob"
 	"github.com/vaberof/hezzl-backend/internal/infra/storage/clickhouse/chgoodlog"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage/clickhouse/chstockmovement"
 	"github.com/vaberof/hezzl-backend/internal/infra/storage/postgres/pggood"
 	redisstorage "github.com/vaberof/hezzl-backend/internal/infra/storage/redis"
 	"github.com/vaberof/hezzl-backend/pkg/database/clickhouse"
 	"github.com/vaberof/hezzl-backend/pkg/database/postgres"
 	"github.com/vaberof/hezzl-backend/pkg/database/redis"
+	"github.com/vaberof/hezzl-backend/pkg/health"
 	"github.com/vaberof/hezzl-backend/pkg/http/httpserver"
-	"log"
+	"github.com/vaberof/hezzl-backend/pkg/lifecycle"
+	"github.com/vaberof/hezzl-backend/pkg/logging"
+	"github.com/vaberof/hezzl-backend/pkg/metrics"
+	"github.com/vaberof/hezzl-backend/pkg/ratelimit"
+	"github.com/vaberof/hezzl-backend/pkg/tracing"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
 )
 
+const (
+	exitCodeOk      = 0
+	exitCodeFailure = 1
+)
+
 var appConfigPaths = flag.String("config.files", "not-found.yaml", "List of application config files separated by comma")
 var environmentVariablesPath = flag.String("env.vars.file", "not-found.env", "Path to environment variables file")
 
@@ -33,82 +48,269 @@ func main() {
 
 	appConfig := mustGetAppConfig(*appConfigPaths)
 
-	fmt.Printf("%+v\n", appConfig)
-
-	postgresManagedDb, err := postgres.New(&appConfig.Postgres)
+	logger, err := logging.New(&appConfig.Logger, os.Stdout)
 	if err != nil {
 		panic(err)
 	}
+	slog.SetDefault(logger)
 
-	redisManagedDb, err := redis.New(&appConfig.Redis)
-	if err != nil {
-		panic(err)
-	}
+	slog.Info("loaded application config", "config", appConfig)
 
-	clickHouseManagedDb, err := clickhouse.New(&appConfig.ClickHouse)
-	if err != nil {
-		panic(err)
-	}
+	os.Exit(run(&appConfig))
+}
 
-	goodLogPublisher, err := publisher.New(&appConfig.NatsPublisher)
-	if err != nil {
-		panic(err)
-	}
+// run starts the application components in dependency order, blocks until a stop signal
+// or a server failure and then stops the components in reverse order.
+// It returns a non-zero exit code if startup failed, the server crashed or the shutdown was not clean.
+func run(appConfig *AppConfig) int {
+	lifecycleManager := lifecycle.NewManager(&appConfig.Lifecycle)
 
-	goodLogSubscriber, err := subscriber.New(&appConfig.NatsSubscriber)
-	if err != nil {
-		panic(err)
-	}
+	// Connection retries are aborted if the application is stopped while still starting up.
+	startupCtx, stopStartup := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stopStartup()
 
-	pgGoodStorage := pggood.NewPgGoodStorage(postgresManagedDb.PostgresDb, goodLogPublisher)
-	redisStorage := redisstorage.NewRedisStorage(redisManagedDb.RedisDb)
-	chGoodStorage := chgoodlog.NewCHGoodLogStorage(clickHouseManagedDb.ClickHouseDb)
+	var (
+		tracingProvider     *tracing.Provider
+		postgresManagedDb   *postgres.ManagedDatabase
+		redisManagedDb      *redis.ManagedDatabase
+		clickHouseManagedDb *clickhouse.ManagedDatabase
+		goodLogPublisher    publisher.Publisher
+		goodLogSubscriber   subscriber.Subscriber
+		blobStore           blob.Store
+		pgGoodStorage       *pggood.PgGoodStorage
+		redisStorage        *redisstorage.RedisStorage
+		domainGoodService   good.GoodService
+		retentionJob        *retention.Job
+		blobDeletionJob     *blobdeletion.Job
+		goodImportRunner    *goodimport.Runner
+		appServer           *httpserver.AppServer
+		serverExitChannel   <-chan error
+	)
 
-	goodLogSubscriber.SubscribeOnGoodLogsSubject(chGoodStorage)
+	components := []lifecycle.Component{
+		{
+			Name: "tracing",
+			Start: func(ctx context.Context) (err error) {
+				tracingProvider, err = tracing.New(ctx, &appConfig.Tracing)
+				return err
+			},
+			Stop: func(ctx context.Context) error {
+				return tracingProvider.Shutdown(ctx)
+			},
+		},
+		{
+			Name: "postgres",
+			Start: func(ctx context.Context) (err error) {
+				postgresManagedDb, err = postgres.New(ctx, &appConfig.Postgres)
+				return err
+			},
+			Stop: func(ctx context.Context) error {
+				return postgresManagedDb.Disconnect()
+			},
+		},
+		{
+			Name: "redis",
+			Start: func(ctx context.Context) (err error) {
+				redisManagedDb, err = redis.New(ctx, &appConfig.Redis)
+				return err
+			},
+			Stop: func(ctx context.Context) error {
+				return redisManagedDb.Disconnect()
+			},
+		},
+		{
+			Name: "clickhouse",
+			Start: func(ctx context.Context) (err error) {
+				clickHouseManagedDb, err = clickhouse.New(ctx, &appConfig.ClickHouse)
+				return err
+			},
+			Stop: func(ctx context.Context) error {
+				return clickHouseManagedDb.Disconnect()
+			},
+		},
+		{
+			Name: "natsPublisher",
+			Start: func(ctx context.Context) (err error) {
+				goodLogPublisher, err = publisher.New(ctx, &appConfig.NatsPublisher)
+				return err
+			},
+			Stop: func(ctx context.Context) error {
+				return goodLogPublisher.Drain(ctx)
+			},
+		},
+		{
+			Name: "natsSubscriber",
+			Start: func(ctx context.Context) (err error) {
+				goodLogSubscriber, err = subscriber.New(ctx, &appConfig.NatsSubscriber)
+				if err != nil {
+					return err
+				}
+				chGoodStorage := chgoodlog.NewCHGoodLogStorage(clickHouseManagedDb.ClickHouseDb)
+				if err = goodLogSubscriber.SubscribeOnGoodLogsSubject(context.Background(), chGoodStorage); err != nil {
+					return err
+				}
+				chStockMovementStorage := chstockmovement.NewCHStockMovementStorage(clickHouseManagedDb.ClickHouseDb)
+				return goodLogSubscriber.SubscribeOnStockMovementsSubject(context.Background(), chStockMovementStorage)
+			},
+			Stop: func(ctx context.Context) error {
+				return goodLogSubscriber.Drain(ctx)
+			},
+		},
+		{
+			Name: "blobStore",
+			Start: func(ctx context.Context) (err error) {
+				blobStore, err = blob.New(&appConfig.BlobStore)
+				return err
+			},
+		},
+		{
+			Name: "goodStorage",
+			Start: func(ctx context.Context) error {
+				pgGoodStorage = pggood.NewPgGoodStorage(postgresManagedDb.PostgresDb, goodLogPublisher)
+				redisStorage = redisstorage.NewRedisStorage(redisManagedDb.RedisDb)
+				domainGoodService = good.NewGoodService(pgGoodStorage, redisStorage, blobStore)
+				return nil
+			},
+			Stop: func(ctx context.Context) error {
+				return pgGoodStorage.Wait(ctx)
+			},
+		},
+		{
+			Name: "goodRetention",
+			Start: func(ctx context.Context) (err error) {
+				if !appConfig.Retention.Enabled {
+					return nil
+				}
+				retentionJob, err = retention.New(&appConfig.Retention, domainGoodService)
+				if err != nil {
+					return err
+				}
+				retentionJob.Start()
+				return nil
+			},
+			Stop: func(ctx context.Context) error {
+				if retentionJob == nil {
+					return nil
+				}
+				return retentionJob.Stop(ctx)
+			},
+		},
+		{
+			Name: "blobDeletion",
+			Start: func(ctx context.Context) error {
+				blobDeletionJob = blobdeletion.New(&appConfig.BlobDeletion, domainGoodService)
+				blobDeletionJob.Start()
+				return nil
+			},
+			Stop: func(ctx context.Context) error {
+				return blobDeletionJob.Stop(ctx)
+			},
+		},
+		{
+			Name: "goodImport",
+			Start: func(ctx context.Context) error {
+				goodImportRunner = goodimport.NewRunner(&appConfig.Import, domainGoodService, redisStorage)
+				return nil
+			},
+			Stop: func(ctx context.Context) error {
+				return goodImportRunner.Stop(ctx)
+			},
+		},
+		{
+			Name: "httpServer",
+			Start: func(ctx context.Context) (err error) {
+				appServer, err = newAppServer(appConfig, domainGoodService, goodImportRunner, redisStorage, redisManagedDb, clickHouseManagedDb, postgresManagedDb, goodLogPublisher, goodLogSubscriber, blobStore)
+				if err != nil {
+					return err
+				}
+				serverExitChannel = appServer.StartAsync()
+				return nil
+			},
+			Stop: func(ctx context.Context) error {
+				return appServer.Shutdown(ctx)
+			},
+		},
+	}
 
-	domainGoodService := good.NewGoodService(pgGoodStorage, redisStorage)
+	for _, component := range components {
+		if err := lifecycleManager.Start(startupCtx, component); err != nil {
+			slog.Error("failed to start application", "error", err)
 
-	httpHandler := http.NewHandler(domainGoodService)
+			if err = lifecycleManager.Shutdown(); err != nil {
+				slog.Error("application shutdown was not clean", "error", err)
+			}
 
-	appServer := httpserver.New(&appConfig.Server)
+			return exitCodeFailure
+		}
+	}
 
-	httpHandler.InitRoutes(appServer.ChiRouter)
+	stopStartup()
 
-	serverExitChannel := appServer.StartAsync()
+	exitCode := exitCodeOk
 
 	quitCh := make(chan os.Signal, 1)
 	signal.Notify(quitCh, syscall.SIGTERM, syscall.SIGINT)
 
 	select {
 	case signalValue := <-quitCh:
-		log.Println("stopping application", "signal", signalValue.String())
-
-		gracefulShutdown(appServer, postgresManagedDb, redisManagedDb, clickHouseManagedDb)
+		slog.Info("stopping application", "signal", signalValue.String())
 	case err := <-serverExitChannel:
-		log.Println("stopping application", "err", err.Error())
+		slog.Error("stopping application", "error", err)
+		exitCode = exitCodeFailure
+	}
+
+	if err := lifecycleManager.Shutdown(); err != nil {
+		slog.Error("application shutdown was not clean", "error", err)
 
-		gracefulShutdown(appServer, postgresManagedDb, redisManagedDb, clickHouseManagedDb)
+		return exitCodeFailure
 	}
+
+	slog.Info("server successfully shutdown")
+
+	return exitCode
 }
 
-func gracefulShutdown(server *httpserver.AppServer, postgresManagedDb *postgres.ManagedDatabase, redisManagedDb *redis.ManagedDatabase, clickHouseManagedDb *clickhouse.ManagedDatabase) {
-	if err := server.Server.Shutdown(context.Background()); err != nil {
-		log.Printf("HTTP server Shutdown: %v\n", err)
-	}
+func newAppServer(
+	appConfig *AppConfig,
+	domainGoodService good.GoodService,
+	goodImportRunner *goodimport.Runner,
+	redisStorage *redisstorage.RedisStorage,
+	redisManagedDb *redis.ManagedDatabase,
+	clickHouseManagedDb *clickhouse.ManagedDatabase,
+	postgresManagedDb *postgres.ManagedDatabase,
+	goodLogPublisher publisher.Publisher,
+	goodLogSubscriber subscriber.Subscriber,
+	blobStore blob.Store,
+) (*httpserver.AppServer, error) {
+	healthChecker := health.NewChecker(&appConfig.Health,
+		health.Check{Name: "postgres", Critical: true, Ping: postgresManagedDb.Ping},
+		health.Check{Name: "redis", Critical: true, Ping: redisManagedDb.Ping},
+		health.Check{Name: "clickhouse", Critical: false, Ping: clickHouseManagedDb.Ping},
+		health.Check{Name: "natsPublisher", Critical: false, Ping: goodLogPublisher.Ping},
+		health.Check{Name: "natsSubscriber", Critical: false, Ping: goodLogSubscriber.Ping},
+		health.Check{Name: "blobStore", Critical: false, Ping: blobStore.Ping},
+	)
 
-	if err := postgresManagedDb.Disconnect(); err != nil {
-		log.Printf("Postgres database Shutdown: %v\n", err)
-	}
+	// Limits are shared between replicas through Redis and enforced per replica while Redis is unavailable.
+	rateLimiter := ratelimit.NewFallbackLimiter(ratelimit.NewRedisLimiter(redisManagedDb.RedisDb), ratelimit.NewMemoryLimiter())
 
-	if err := redisManagedDb.RedisDb.Close(); err != nil {
-		log.Printf("Redis database Shutdown: %v\n", err)
-	}
+	httpHandler := http.NewHandler(domainGoodService, healthChecker, rateLimiter, &appConfig.RateLimit, redisStorage, &appConfig.Idempotency, &appConfig.Auth, &appConfig.Batch, goodImportRunner, &appConfig.Import, &appConfig.Export, &appConfig.Attachment, appConfig.Server.MaxBodyBytes)
 
-	if err := clickHouseManagedDb.ClickHouseDb.Close(); err != nil {
-		log.Printf("ClickHouse database Shutdown: %v\n", err)
+	appServer, err := httpserver.New(&appConfig.Server)
+	if err != nil {
+		return nil, err
 	}
 
-	log.Println("Server successfully shutdown")
+	// The access log is written from within the server span, so that it is correlated with the trace.
+	appServer.ChiRouter.Use(logging.RequestIdMiddleware)
+	appServer.ChiRouter.Use(tracing.HttpMiddleware)
+	appServer.ChiRouter.Use(logging.HttpMiddleware)
+	appServer.ChiRouter.Use(metrics.HttpMiddleware)
+	appServer.ChiRouter.Handle("/metrics", metrics.Handler())
+
+	httpHandler.InitRoutes(appServer.ChiRouter)
+
+	return appServer, nil
 }
 
 func loadEnvironmentVariables() error {
@@ -0,0 +1,101 @@
+package blobdeletion
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	defaultInterval  = 10 * time.Minute
+	defaultBatchSize = 100
+)
+
+// Job periodically deletes the attachment blobs left behind by deleted attachments, moved and purged goods.
+type Job struct {
+	goodService GoodService
+	interval    time.Duration
+	batchSize   int
+
+	cancel context.CancelFunc
+	done   sync.WaitGroup
+}
+
+func New(config *Config, goodService GoodService) *Job {
+	interval := config.Interval
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	batchSize := config.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	return &Job{
+		goodService: goodService,
+		interval:    interval,
+		batchSize:   batchSize,
+	}
+}
+
+// Start runs the job right away and then every interval until Stop is called.
+func (j *Job) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	j.cancel = cancel
+
+	j.done.Add(1)
+	go func() {
+		defer j.done.Done()
+
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			j.run(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop interrupts the current run and waits for the job to exit or ctx to be done.
+func (j *Job) Stop(ctx context.Context) error {
+	if j.cancel == nil {
+		return nil
+	}
+	j.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		j.done.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (j *Job) run(ctx context.Context) {
+	deleted, err := j.goodService.DeleteQueuedBlobs(ctx, j.batchSize)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		slog.ErrorContext(ctx, "failed to delete attachment blobs", "deleted", deleted, "error", err)
+
+		return
+	}
+
+	if deleted > 0 {
+		slog.InfoContext(ctx, "deleted attachment blobs", "deleted", deleted)
+	}
+}
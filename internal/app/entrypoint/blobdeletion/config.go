@@ -0,0 +1,8 @@
+package blobdeletion
+
+import "time"
+
+type Config struct {
+	Interval  time.Duration `yaml:"interval"`
+	BatchSize int           `yaml:"batch_size"`
+}
@@ -0,0 +1,7 @@
+package blobdeletion
+
+import "context"
+
+type GoodService interface {
+	DeleteQueuedBlobs(ctx context.Context, batchSize int) (int, error)
+}
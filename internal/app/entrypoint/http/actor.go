@@ -0,0 +1,49 @@
+package http
+
+import (
+	"context"
+	"github.com/vaberof/hezzl-backend/internal/app/entrypoint/http/views"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"github.com/vaberof/hezzl-backend/pkg/http/protocols/apiv1"
+	"net/http"
+	"strings"
+)
+
+const actorHeader = "X-Actor"
+
+type actorContextKey struct{}
+
+// ContextWithActor returns a copy of ctx carrying the authenticated actor.
+// Authentication middlewares should use it so the actor takes precedence over the X-Actor header.
+func ContextWithActor(ctx context.Context, actor domain.Actor) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+// ActorFromContext returns the actor stored in ctx, if any.
+func ActorFromContext(ctx context.Context) (domain.Actor, bool) {
+	actor, ok := ctx.Value(actorContextKey{}).(domain.Actor)
+	if !ok || actor == "" {
+		return "", false
+	}
+	return actor, true
+}
+
+// RequireActor rejects requests that carry no actor identity either in the auth context or in the X-Actor header.
+func (h *Handler) RequireActor(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, request *http.Request) {
+		if _, ok := ActorFromContext(request.Context()); ok {
+			next.ServeHTTP(rw, request)
+
+			return
+		}
+
+		actor := strings.TrimSpace(request.Header.Get(actorHeader))
+		if actor == "" {
+			views.RenderJSON(rw, request, http.StatusUnauthorized, apiv1.Error(CodeUnauthorized, ErrMessageUnauthorized, apiv1.ErrorDescription{"details": "Missing required header '" + actorHeader + "'"}))
+
+			return
+		}
+
+		next.ServeHTTP(rw, request.WithContext(ContextWithActor(request.Context(), domain.Actor(actor))))
+	})
+}
@@ -0,0 +1,22 @@
+package http
+
+import (
+	"github.com/vaberof/hezzl-backend/internal/app/entrypoint/http/views"
+	"github.com/vaberof/hezzl-backend/pkg/http/protocols/apiv1"
+	"net/http"
+)
+
+// RequireAdmin rejects requests that are not authenticated with the API key of an admin.
+// The X-Actor header is not trusted, since any client can set it.
+func (h *Handler) RequireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, request *http.Request) {
+		principal, ok := PrincipalFromContext(request.Context())
+		if !ok || !principal.Admin {
+			views.RenderJSON(rw, request, http.StatusForbidden, apiv1.Error(CodeForbidden, ErrMessageForbidden, apiv1.ErrorDescription{"details": "Only admins are allowed to perform this action"}))
+
+			return
+		}
+
+		next.ServeHTTP(rw, request)
+	})
+}
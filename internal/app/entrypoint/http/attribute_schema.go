@@ -0,0 +1,150 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/vaberof/hezzl-backend/internal/app/entrypoint/http/views"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"github.com/vaberof/hezzl-backend/pkg/http/protocols/apiv1"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type setAttributeSchemaRequestBody struct {
+	Schema json.RawMessage `json:"schema"`
+}
+
+func (s *setAttributeSchemaRequestBody) Bind(req *http.Request) error {
+	return nil
+}
+
+type attributeSchemaResponseBody struct {
+	ProjectId int64           `json:"projectId"`
+	Schema    json.RawMessage `json:"schema"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+	UpdatedBy string          `json:"updatedBy"`
+}
+
+type deleteAttributeSchemaResponseBody struct {
+	ProjectId int64 `json:"projectId"`
+	Deleted   bool  `json:"deleted"`
+}
+
+func (h *Handler) GetAttributeSchemaHandler() http.HandlerFunc {
+	return func(rw http.ResponseWriter, request *http.Request) {
+		projectId, err := strconv.ParseInt(chi.URLParam(request, "id"), 10, 64)
+		if err != nil {
+			views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to convert id to int"}))
+
+			return
+		}
+
+		attributeSchema, err := h.goodService.GetAttributeSchema(request.Context(), domain.ProjectId(projectId))
+		if err != nil {
+			if errors.Is(err, good.ErrAttributeSchemaNotFound) {
+				renderAttributeSchemaNotFound(rw, request)
+			} else {
+				views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to get the attribute schema"}))
+			}
+
+			return
+		}
+
+		payload, _ := json.Marshal(buildAttributeSchemaResponseBody(attributeSchema))
+
+		views.RenderJSON(rw, request, http.StatusOK, apiv1.Success(payload))
+	}
+}
+
+func (h *Handler) SetAttributeSchemaHandler() http.HandlerFunc {
+	return func(rw http.ResponseWriter, request *http.Request) {
+		projectId, err := strconv.ParseInt(chi.URLParam(request, "id"), 10, 64)
+		if err != nil {
+			views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to convert id to int"}))
+
+			return
+		}
+
+		setAttributeSchemaReqBody := &setAttributeSchemaRequestBody{}
+		if err = render.Bind(request, setAttributeSchemaReqBody); err != nil {
+			renderBindError(rw, request, err)
+
+			return
+		}
+
+		if len(setAttributeSchemaReqBody.Schema) == 0 || string(setAttributeSchemaReqBody.Schema) == "null" {
+			views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "Missing required field 'schema'"}))
+
+			return
+		}
+
+		actor, _ := ActorFromContext(request.Context())
+
+		attributeSchema, err := h.goodService.SetAttributeSchema(request.Context(), domain.ProjectId(projectId), setAttributeSchemaReqBody.Schema, actor)
+		if err != nil {
+			if errors.Is(err, good.ErrInvalidAttributeSchema) {
+				views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidAttributeSchema, apiv1.ErrorDescription{"details": err.Error()}))
+			} else if errors.Is(err, good.ErrProjectNotFound) {
+				views.RenderJSON(rw, request, http.StatusNotFound, apiv1.Error(CodeNotFound, ErrMessageProjectNotFound, apiv1.ErrorDescription{"details": "Project is not found"}))
+			} else {
+				views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to set the attribute schema"}))
+			}
+
+			return
+		}
+
+		payload, _ := json.Marshal(buildAttributeSchemaResponseBody(attributeSchema))
+
+		views.RenderJSON(rw, request, http.StatusOK, apiv1.Success(payload))
+	}
+}
+
+func (h *Handler) DeleteAttributeSchemaHandler() http.HandlerFunc {
+	return func(rw http.ResponseWriter, request *http.Request) {
+		projectId, err := strconv.ParseInt(chi.URLParam(request, "id"), 10, 64)
+		if err != nil {
+			views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to convert id to int"}))
+
+			return
+		}
+
+		err = h.goodService.DeleteAttributeSchema(request.Context(), domain.ProjectId(projectId))
+		if err != nil {
+			if errors.Is(err, good.ErrAttributeSchemaNotFound) {
+				renderAttributeSchemaNotFound(rw, request)
+			} else {
+				views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to delete the attribute schema"}))
+			}
+
+			return
+		}
+
+		payload, _ := json.Marshal(&deleteAttributeSchemaResponseBody{
+			ProjectId: projectId,
+			Deleted:   true,
+		})
+
+		views.RenderJSON(rw, request, http.StatusOK, apiv1.Success(payload))
+	}
+}
+
+func buildAttributeSchemaResponseBody(attributeSchema *good.AttributeSchema) *attributeSchemaResponseBody {
+	return &attributeSchemaResponseBody{
+		ProjectId: attributeSchema.ProjectId.Int64(),
+		Schema:    attributeSchema.Schema,
+		UpdatedAt: attributeSchema.UpdatedAt,
+		UpdatedBy: attributeSchema.UpdatedBy.String(),
+	}
+}
+
+func renderAttributeSchemaNotFound(rw http.ResponseWriter, request *http.Request) {
+	views.RenderJSON(rw, request, http.StatusNotFound, apiv1.Error(CodeNotFound, ErrMessageAttributeSchemaNotFound, apiv1.ErrorDescription{"details": "Project has no attribute schema"}))
+}
+
+func renderInvalidAttributes(rw http.ResponseWriter, request *http.Request, err error) {
+	views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidAttributes, apiv1.ErrorDescription{"details": err.Error()}))
+}
@@ -0,0 +1,124 @@
+package http
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/vaberof/hezzl-backend/internal/app/entrypoint/http/views"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"github.com/vaberof/hezzl-backend/pkg/http/protocols/apiv1"
+	"net/http"
+	"strings"
+)
+
+const apiKeyHeader = "X-Api-Key"
+
+// keyIdLength is the number of hex digits of the key digest that identify an API key in rate limit keys.
+const keyIdLength = 16
+
+// adminApiKeyFlag marks the entries of ParseApiKeys that belong to admins.
+const adminApiKeyFlag = "admin"
+
+type AuthConfig struct {
+	// ApiKeys lists the API keys clients authenticate with in the X-Api-Key header. They are read from
+	// the environment with ParseApiKeys rather than from the config files, so that no deployment ships with keys.
+	ApiKeys []ApiKeyConfig `yaml:"-"`
+}
+
+type ApiKeyConfig struct {
+	// Principal names the client the key belongs to; it is the actor of the requests made with the key.
+	Principal string `yaml:"principal"`
+	// KeySha256 is the hex-encoded SHA-256 digest of the key, so that the config holds no secrets.
+	KeySha256 string `yaml:"key_sha256"`
+	// Admin allows the client to use the admin endpoints.
+	Admin bool `yaml:"admin"`
+}
+
+// ParseApiKeys parses comma-separated API keys of the form "principal:sha256" or "principal:sha256:admin",
+// where sha256 is the hex-encoded SHA-256 digest of the key.
+func ParseApiKeys(value string) ([]ApiKeyConfig, error) {
+	var apiKeys []ApiKeyConfig
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		fields := strings.Split(entry, ":")
+		if len(fields) < 2 || len(fields) > 3 || fields[0] == "" {
+			return nil, fmt.Errorf("invalid API key %q: want principal:sha256[:admin]", entry)
+		}
+
+		keySha256, err := hex.DecodeString(fields[1])
+		if err != nil || len(keySha256) != sha256.Size {
+			return nil, fmt.Errorf("invalid API key of %q: the digest must be %d hex-encoded bytes", fields[0], sha256.Size)
+		}
+
+		apiKey := ApiKeyConfig{Principal: fields[0], KeySha256: strings.ToLower(fields[1])}
+		if len(fields) == 3 {
+			if fields[2] != adminApiKeyFlag {
+				return nil, fmt.Errorf("invalid API key of %q: unknown flag %q", fields[0], fields[2])
+			}
+			apiKey.Admin = true
+		}
+
+		apiKeys = append(apiKeys, apiKey)
+	}
+
+	return apiKeys, nil
+}
+
+// Principal is a client authenticated by its API key.
+type Principal struct {
+	Name string
+	// KeyId identifies the API key without revealing it.
+	KeyId string
+	Admin bool
+}
+
+type principalContextKey struct{}
+
+func ContextWithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+// PrincipalFromContext returns the authenticated principal stored in ctx, if any.
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalContextKey{}).(*Principal)
+	return principal, ok
+}
+
+// Authenticate resolves the X-Api-Key header to the principal the key belongs to, which also becomes the actor
+// of the request. Requests without the header stay anonymous, requests with an unknown key are rejected.
+func (h *Handler) Authenticate(next http.Handler) http.Handler {
+	principals := make(map[string]*Principal, len(h.authConfig.ApiKeys))
+	for _, apiKey := range h.authConfig.ApiKeys {
+		keySha256 := strings.ToLower(apiKey.KeySha256)
+		principals[keySha256] = &Principal{Name: apiKey.Principal, KeyId: keySha256[:min(keyIdLength, len(keySha256))], Admin: apiKey.Admin}
+	}
+
+	return http.HandlerFunc(func(rw http.ResponseWriter, request *http.Request) {
+		apiKey := request.Header.Get(apiKeyHeader)
+		if apiKey == "" {
+			next.ServeHTTP(rw, request)
+
+			return
+		}
+
+		keySha256 := sha256.Sum256([]byte(apiKey))
+
+		principal, ok := principals[hex.EncodeToString(keySha256[:])]
+		if !ok {
+			views.RenderJSON(rw, request, http.StatusUnauthorized, apiv1.Error(CodeUnauthorized, ErrMessageUnauthorized, apiv1.ErrorDescription{"details": "Invalid API key"}))
+
+			return
+		}
+
+		ctx := ContextWithPrincipal(request.Context(), principal)
+		ctx = ContextWithActor(ctx, domain.Actor(principal.Name))
+
+		next.ServeHTTP(rw, request.WithContext(ctx))
+	})
+}
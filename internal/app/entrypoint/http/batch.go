@@ -0,0 +1,112 @@
+package http
+
+import (
+	"errors"
+	"github.com/vaberof/hezzl-backend/internal/app/entrypoint/http/views"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/pkg/http/protocols/apiv1"
+	"net/http"
+	"strconv"
+)
+
+const defaultBatchMaxItems = 500
+
+type BatchConfig struct {
+	// MaxItems limits how many goods a single batch request may contain.
+	MaxItems int `yaml:"max_items"`
+}
+
+type batchResponseBody struct {
+	Succeeded int                       `json:"succeeded"`
+	Failed    int                       `json:"failed"`
+	Results   []*batchItemResultPayload `json:"results"`
+}
+
+type batchItemResultPayload struct {
+	Index int                    `json:"index"`
+	Good  *listGoodPayload       `json:"good,omitempty"`
+	Error *batchItemErrorPayload `json:"error,omitempty"`
+}
+
+type batchItemErrorPayload struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Details string `json:"details"`
+}
+
+func (h *Handler) batchMaxItems() int {
+	if h.batchConfig.MaxItems <= 0 {
+		return defaultBatchMaxItems
+	}
+	return h.batchConfig.MaxItems
+}
+
+// validateBatchSize responds with 400 and returns false if the batch is empty or too large.
+func (h *Handler) validateBatchSize(rw http.ResponseWriter, request *http.Request, size int) bool {
+	if size == 0 {
+		views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "'items' must not be empty"}))
+
+		return false
+	}
+
+	if maxItems := h.batchMaxItems(); size > maxItems {
+		views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "'items' must not contain more than " + strconv.Itoa(maxItems) + " goods"}))
+
+		return false
+	}
+
+	return true
+}
+
+// renderBatchError responds to an error that failed the whole batch.
+func renderBatchError(rw http.ResponseWriter, request *http.Request, err error, details string) {
+	if errors.Is(err, good.ErrBatchDuplicateGood) {
+		views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "Every good may occur in 'items' only once"}))
+
+		return
+	}
+	if errors.Is(err, good.ErrInvalidAttributes) {
+		renderInvalidAttributes(rw, request, err)
+
+		return
+	}
+
+	views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": details}))
+}
+
+func (h *Handler) buildBatchResponseBody(results []*good.BatchResult) *batchResponseBody {
+	responseBody := &batchResponseBody{
+		Results: make([]*batchItemResultPayload, len(results)),
+	}
+
+	for i, result := range results {
+		itemResult := &batchItemResultPayload{Index: i}
+
+		if result.Err != nil {
+			itemResult.Error = buildBatchItemErrorPayload(result.Err)
+			responseBody.Failed++
+		} else {
+			itemResult.Good = h.buildListGoodPayload(result.Good)
+			responseBody.Succeeded++
+		}
+
+		responseBody.Results[i] = itemResult
+	}
+
+	return responseBody
+}
+
+func buildBatchItemErrorPayload(err error) *batchItemErrorPayload {
+	switch {
+	case errors.Is(err, good.ErrGoodNotFound):
+		return &batchItemErrorPayload{Code: CodeNotFound, Message: ErrMessageGoodNotFound, Details: "Good is not found"}
+	case errors.Is(err, good.ErrGoodRemoved):
+		return &batchItemErrorPayload{Code: CodeConflict, Message: ErrMessageGoodRemoved, Details: "Good is removed"}
+	case errors.Is(err, good.ErrGoodVersionMismatch):
+		return &batchItemErrorPayload{Code: CodePreconditionFailed, Message: ErrMessageVersionMismatch, Details: "Good has been modified since it was read"}
+	case errors.Is(err, good.ErrInvalidAttributes):
+		return &batchItemErrorPayload{Code: CodeBadRequest, Message: ErrMessageInvalidAttributes, Details: err.Error()}
+	default:
+		return &batchItemErrorPayload{Code: CodeInternalError, Message: ErrMessageInternalServerError, Details: "Failed to process the good"}
+	}
+}
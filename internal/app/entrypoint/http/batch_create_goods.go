@@ -0,0 +1,73 @@
+package http
+
+import (
+	"encoding/json"
+	"github.com/go-chi/render"
+	"github.com/vaberof/hezzl-backend/internal/app/entrypoint/http/views"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"github.com/vaberof/hezzl-backend/pkg/http/protocols/apiv1"
+	"net/http"
+)
+
+type batchCreateGoodsRequestBody struct {
+	Items []*batchCreateGoodItem `json:"items"`
+}
+
+type batchCreateGoodItem struct {
+	ProjectId  int64          `json:"projectId"`
+	Name       string         `json:"name"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+func (b *batchCreateGoodsRequestBody) Bind(req *http.Request) error {
+	return nil
+}
+
+func (h *Handler) BatchCreateGoodsHandler() http.HandlerFunc {
+	return func(rw http.ResponseWriter, request *http.Request) {
+		batchCreateGoodsReqBody := &batchCreateGoodsRequestBody{}
+		if err := render.Bind(request, batchCreateGoodsReqBody); err != nil {
+			renderBindError(rw, request, err)
+
+			return
+		}
+
+		if !h.validateBatchSize(rw, request, len(batchCreateGoodsReqBody.Items)) {
+			return
+		}
+
+		items := make([]*good.CreateItem, len(batchCreateGoodsReqBody.Items))
+		for i, item := range batchCreateGoodsReqBody.Items {
+			if item == nil {
+				views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "'items' must not contain null"}))
+
+				return
+			}
+
+			items[i] = &good.CreateItem{
+				ProjectId:  domain.ProjectId(item.ProjectId),
+				Name:       domain.GoodName(item.Name),
+				Attributes: item.Attributes,
+			}
+		}
+
+		actor, _ := ActorFromContext(request.Context())
+
+		domainGoods, err := h.goodService.CreateBatch(request.Context(), items, actor)
+		if err != nil {
+			renderBatchError(rw, request, err, "Failed to create goods")
+
+			return
+		}
+
+		results := make([]*good.BatchResult, len(domainGoods))
+		for i := range domainGoods {
+			results[i] = &good.BatchResult{Good: domainGoods[i]}
+		}
+
+		payload, _ := json.Marshal(h.buildBatchResponseBody(results))
+
+		views.RenderJSON(rw, request, http.StatusOK, apiv1.Success(payload))
+	}
+}
@@ -0,0 +1,71 @@
+package http
+
+import (
+	"encoding/json"
+	"github.com/go-chi/render"
+	"github.com/vaberof/hezzl-backend/internal/app/entrypoint/http/views"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"github.com/vaberof/hezzl-backend/pkg/http/protocols/apiv1"
+	"net/http"
+)
+
+type batchRemoveGoodsRequestBody struct {
+	Items []*batchRemoveGoodItem `json:"items"`
+}
+
+type batchRemoveGoodItem struct {
+	Id        int64  `json:"id"`
+	ProjectId int64  `json:"projectId"`
+	Version   *int64 `json:"version,omitempty"`
+}
+
+func (b *batchRemoveGoodsRequestBody) Bind(req *http.Request) error {
+	return nil
+}
+
+func (h *Handler) BatchRemoveGoodsHandler() http.HandlerFunc {
+	return func(rw http.ResponseWriter, request *http.Request) {
+		batchRemoveGoodsReqBody := &batchRemoveGoodsRequestBody{}
+		if err := render.Bind(request, batchRemoveGoodsReqBody); err != nil {
+			renderBindError(rw, request, err)
+
+			return
+		}
+
+		if !h.validateBatchSize(rw, request, len(batchRemoveGoodsReqBody.Items)) {
+			return
+		}
+
+		items := make([]*good.RemoveItem, len(batchRemoveGoodsReqBody.Items))
+		for i, item := range batchRemoveGoodsReqBody.Items {
+			if item == nil {
+				views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "'items' must not contain null"}))
+
+				return
+			}
+
+			items[i] = &good.RemoveItem{
+				Id:        domain.GoodId(item.Id),
+				ProjectId: domain.ProjectId(item.ProjectId),
+			}
+			if item.Version != nil {
+				version := domain.GoodVersion(*item.Version)
+				items[i].Version = &version
+			}
+		}
+
+		actor, _ := ActorFromContext(request.Context())
+
+		results, err := h.goodService.DeleteBatch(request.Context(), items, actor)
+		if err != nil {
+			renderBatchError(rw, request, err, "Failed to remove goods")
+
+			return
+		}
+
+		payload, _ := json.Marshal(h.buildBatchResponseBody(results))
+
+		views.RenderJSON(rw, request, http.StatusOK, apiv1.Success(payload))
+	}
+}
@@ -0,0 +1,80 @@
+package http
+
+import (
+	"encoding/json"
+	"github.com/go-chi/render"
+	"github.com/vaberof/hezzl-backend/internal/app/entrypoint/http/views"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"github.com/vaberof/hezzl-backend/pkg/http/protocols/apiv1"
+	"net/http"
+)
+
+type batchUpdateGoodsRequestBody struct {
+	Items []*batchUpdateGoodItem `json:"items"`
+}
+
+type batchUpdateGoodItem struct {
+	Id          int64          `json:"id"`
+	ProjectId   int64          `json:"projectId"`
+	Name        string         `json:"name"`
+	Description *string        `json:"description,omitempty"`
+	Attributes  map[string]any `json:"attributes,omitempty"`
+	Version     *int64         `json:"version,omitempty"`
+}
+
+func (b *batchUpdateGoodsRequestBody) Bind(req *http.Request) error {
+	return nil
+}
+
+func (h *Handler) BatchUpdateGoodsHandler() http.HandlerFunc {
+	return func(rw http.ResponseWriter, request *http.Request) {
+		batchUpdateGoodsReqBody := &batchUpdateGoodsRequestBody{}
+		if err := render.Bind(request, batchUpdateGoodsReqBody); err != nil {
+			renderBindError(rw, request, err)
+
+			return
+		}
+
+		if !h.validateBatchSize(rw, request, len(batchUpdateGoodsReqBody.Items)) {
+			return
+		}
+
+		items := make([]*good.UpdateItem, len(batchUpdateGoodsReqBody.Items))
+		for i, item := range batchUpdateGoodsReqBody.Items {
+			if item == nil {
+				views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "'items' must not contain null"}))
+
+				return
+			}
+
+			items[i] = &good.UpdateItem{
+				Id:         domain.GoodId(item.Id),
+				ProjectId:  domain.ProjectId(item.ProjectId),
+				Name:       domain.GoodName(item.Name),
+				Attributes: item.Attributes,
+			}
+			if item.Description != nil {
+				description := domain.GoodDescription(*item.Description)
+				items[i].Description = &description
+			}
+			if item.Version != nil {
+				version := domain.GoodVersion(*item.Version)
+				items[i].Version = &version
+			}
+		}
+
+		actor, _ := ActorFromContext(request.Context())
+
+		results, err := h.goodService.UpdateBatch(request.Context(), items, actor)
+		if err != nil {
+			renderBatchError(rw, request, err, "Failed to update goods")
+
+			return
+		}
+
+		payload, _ := json.Marshal(h.buildBatchResponseBody(results))
+
+		views.RenderJSON(rw, request, http.StatusOK, apiv1.Success(payload))
+	}
+}
@@ -0,0 +1,47 @@
+package http
+
+import (
+	"errors"
+	"github.com/vaberof/hezzl-backend/internal/app/entrypoint/http/views"
+	"github.com/vaberof/hezzl-backend/pkg/http/protocols/apiv1"
+	"net/http"
+	"strconv"
+)
+
+// BodyLimitMiddleware rejects requests whose body exceeds maxBytes with 413.
+// Requests without a declared length are cut off while the body is read, see renderBindError.
+func BodyLimitMiddleware(maxBytes int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if maxBytes <= 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(rw http.ResponseWriter, request *http.Request) {
+			if request.ContentLength > maxBytes {
+				renderRequestTooLarge(rw, request, maxBytes)
+
+				return
+			}
+
+			request.Body = http.MaxBytesReader(rw, request.Body, maxBytes)
+
+			next.ServeHTTP(rw, request)
+		})
+	}
+}
+
+// renderBindError responds to a failed request body decoding.
+func renderBindError(rw http.ResponseWriter, request *http.Request, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		renderRequestTooLarge(rw, request, maxBytesErr.Limit)
+
+		return
+	}
+
+	views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "Invalid request body"}))
+}
+
+func renderRequestTooLarge(rw http.ResponseWriter, request *http.Request, maxBytes int64) {
+	views.RenderJSON(rw, request, http.StatusRequestEntityTooLarge, apiv1.Error(CodeRequestTooLarge, ErrMessageRequestTooLarge, apiv1.ErrorDescription{"details": "Request body must not exceed " + strconv.FormatInt(maxBytes, 10) + " bytes"}))
+}
@@ -1,7 +1,15 @@
 package http
 
 const (
-	CodeBadRequest    = 2
-	CodeNotFound      = 3
-	CodeInternalError = 4
+	CodeBadRequest           = 2
+	CodeNotFound             = 3
+	CodeInternalError        = 4
+	CodeUnauthorized         = 5
+	CodeRequestTooLarge      = 6
+	CodeTooManyRequests      = 7
+	CodeConflict             = 8
+	CodePreconditionFailed   = 9
+	CodeForbidden            = 10
+	CodeUnsupportedMediaType = 11
+	CodeLengthRequired       = 12
 )
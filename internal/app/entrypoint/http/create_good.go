@@ -2,8 +2,10 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"github.com/go-chi/render"
 	"github.com/vaberof/hezzl-backend/internal/app/entrypoint/http/views"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
 	"github.com/vaberof/hezzl-backend/pkg/domain"
 	"github.com/vaberof/hezzl-backend/pkg/http/protocols/apiv1"
 	"net/http"
@@ -12,7 +14,8 @@ import (
 )
 
 type createGoodRequestBody struct {
-	Name string `json:"name"`
+	Name       string         `json:"name"`
+	Attributes map[string]any `json:"attributes,omitempty"`
 }
 
 func (c *createGoodRequestBody) Bind(req *http.Request) error {
@@ -20,13 +23,22 @@ func (c *createGoodRequestBody) Bind(req *http.Request) error {
 }
 
 type createGoodResponseBody struct {
-	Id          int64     `json:"id"`
-	ProjectId   int64     `json:"projectId"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Priority    int       `json:"priority"`
-	Removed     bool      `json:"removed"`
-	CreatedAt   time.Time `json:"createdAt"`
+	Id          int64          `json:"id"`
+	ProjectId   int64          `json:"projectId"`
+	ParentId    *int64         `json:"parentId,omitempty"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Priority    int            `json:"priority"`
+	Removed     bool           `json:"removed"`
+	CreatedAt   time.Time      `json:"createdAt"`
+	CreatedBy   string         `json:"createdBy"`
+	UpdatedBy   string         `json:"updatedBy"`
+	Version     int64          `json:"version"`
+	ExternalKey string         `json:"externalKey,omitempty"`
+	Attributes  map[string]any `json:"attributes"`
+	Price       *pricePayload  `json:"price"`
+	Stock       int64          `json:"stock"`
+	Reserved    int64          `json:"reserved"`
 }
 
 func (h *Handler) CreateGoodHandler() http.HandlerFunc {
@@ -40,7 +52,7 @@ func (h *Handler) CreateGoodHandler() http.HandlerFunc {
 
 		createGoodReqBody := &createGoodRequestBody{}
 		if err := render.Bind(request, createGoodReqBody); err != nil {
-			views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "Invalid request body"}))
+			renderBindError(rw, request, err)
 
 			return
 		}
@@ -52,8 +64,16 @@ func (h *Handler) CreateGoodHandler() http.HandlerFunc {
 			return
 		}
 
-		domainGood, err := h.goodService.Create(domain.ProjectId(projectId), domain.GoodName(createGoodReqBody.Name))
+		actor, _ := ActorFromContext(request.Context())
+
+		domainGood, err := h.goodService.Create(request.Context(), domain.ProjectId(projectId), domain.GoodName(createGoodReqBody.Name), createGoodReqBody.Attributes, actor)
 		if err != nil {
+			if errors.Is(err, good.ErrInvalidAttributes) {
+				renderInvalidAttributes(rw, request, err)
+
+				return
+			}
+
 			views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to create a new good"}))
 
 			return
@@ -62,13 +82,24 @@ func (h *Handler) CreateGoodHandler() http.HandlerFunc {
 		payload, _ := json.Marshal(&createGoodResponseBody{
 			Id:          domainGood.Id.Int64(),
 			ProjectId:   domainGood.ProjectId.Int64(),
+			ParentId:    parentIdPayload(domainGood),
 			Name:        domainGood.Name.String(),
 			Description: domainGood.Description.String(),
 			Priority:    domainGood.Priority.Int(),
 			Removed:     domainGood.Removed.Bool(),
 			CreatedAt:   domainGood.CreatedAt.Time(),
+			CreatedBy:   domainGood.CreatedBy.String(),
+			UpdatedBy:   domainGood.UpdatedBy.String(),
+			Version:     domainGood.Version.Int64(),
+			ExternalKey: domainGood.ExternalKey.String(),
+			Attributes:  domainGood.Attributes,
+			Price:       buildPricePayload(domainGood.Price),
+			Stock:       domainGood.Stock,
+			Reserved:    domainGood.Reserved,
 		})
 
+		rw.Header().Set(etagHeader, goodETag(domainGood.Version))
+
 		views.RenderJSON(rw, request, http.StatusOK, apiv1.Success(payload))
 	}
 }
@@ -15,6 +15,7 @@ type deleteGoodResponseBody struct {
 	Id        int64 `json:"id"`
 	ProjectId int64 `json:"projectId"`
 	Removed   bool  `json:"removed"`
+	Version   int64 `json:"version"`
 }
 
 func (h *Handler) DeleteGoodHandler() http.HandlerFunc {
@@ -47,10 +48,23 @@ func (h *Handler) DeleteGoodHandler() http.HandlerFunc {
 			return
 		}
 
-		domainGood, err := h.goodService.Delete(domain.GoodId(goodId), domain.ProjectId(projectId))
+		expectedVersion, err := expectedVersionFromRequest(request)
+		if err != nil {
+			renderMalformedIfMatch(rw, request)
+
+			return
+		}
+
+		actor, _ := ActorFromContext(request.Context())
+
+		domainGood, err := h.goodService.Delete(request.Context(), domain.GoodId(goodId), domain.ProjectId(projectId), actor, expectedVersion)
 		if err != nil {
 			if errors.Is(err, good.ErrGoodNotFound) {
 				views.RenderJSON(rw, request, http.StatusNotFound, apiv1.Error(CodeNotFound, ErrMessageGoodNotFound, apiv1.ErrorDescription{"details": "Good is not found"}))
+			} else if errors.Is(err, good.ErrGoodRemoved) {
+				views.RenderJSON(rw, request, http.StatusConflict, apiv1.Error(CodeConflict, ErrMessageGoodRemoved, apiv1.ErrorDescription{"details": "Good is already removed"}))
+			} else if errors.Is(err, good.ErrGoodVersionMismatch) {
+				renderVersionMismatch(rw, request)
 			} else {
 				views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to delete a good"}))
 			}
@@ -62,8 +76,11 @@ func (h *Handler) DeleteGoodHandler() http.HandlerFunc {
 			Id:        goodId,
 			ProjectId: projectId,
 			Removed:   domainGood.Removed.Bool(),
+			Version:   domainGood.Version.Int64(),
 		})
 
+		rw.Header().Set(etagHeader, goodETag(domainGood.Version))
+
 		views.RenderJSON(rw, request, http.StatusOK, apiv1.Success(payload))
 	}
 }
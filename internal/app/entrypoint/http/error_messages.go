@@ -1,7 +1,37 @@
 package http
 
 var (
-	ErrMessageInvalidRequestBody  = "errors.good.invalidRequestBody"
-	ErrMessageGoodNotFound        = "errors.good.notFound"
-	ErrMessageInternalServerError = "errors.good.internalServerError"
+	ErrMessageInvalidRequestBody      = "errors.good.invalidRequestBody"
+	ErrMessageGoodNotFound            = "errors.good.notFound"
+	ErrMessageInternalServerError     = "errors.good.internalServerError"
+	ErrMessageUnauthorized            = "errors.good.unauthorized"
+	ErrMessageRequestTooLarge         = "errors.good.requestTooLarge"
+	ErrMessageTooManyRequests         = "errors.good.tooManyRequests"
+	ErrMessageIdempotencyKeyReused    = "errors.good.idempotencyKeyReused"
+	ErrMessageRequestInProgress       = "errors.good.requestInProgress"
+	ErrMessageVersionMismatch         = "errors.good.versionMismatch"
+	ErrMessageForbidden               = "errors.good.forbidden"
+	ErrMessageGoodNotRemoved          = "errors.good.notRemoved"
+	ErrMessageGoodRemoved             = "errors.good.removed"
+	ErrMessageUnsupportedMediaType    = "errors.good.unsupportedMediaType"
+	ErrMessageInvalidImportFile       = "errors.good.invalidImportFile"
+	ErrMessageProjectNotFound         = "errors.good.projectNotFound"
+	ErrMessageImportJobNotFound       = "errors.good.importJobNotFound"
+	ErrMessageInvalidAttributes       = "errors.good.invalidAttributes"
+	ErrMessageInvalidAttributeSchema  = "errors.good.invalidAttributeSchema"
+	ErrMessageAttributeSchemaNotFound = "errors.good.attributeSchemaNotFound"
+	ErrMessageAttachmentNotFound      = "errors.good.attachmentNotFound"
+	ErrMessageInvalidAttachment       = "errors.good.invalidAttachment"
+	ErrMessageLengthRequired          = "errors.good.lengthRequired"
+	ErrMessageInvalidPrice            = "errors.good.invalidPrice"
+	ErrMessageInvalidStockMovement    = "errors.good.invalidStockMovement"
+	ErrMessageInsufficientStock       = "errors.good.insufficientStock"
+	ErrMessageNestedVariant           = "errors.good.nestedVariant"
+	ErrMessageParentRemoved           = "errors.good.parentRemoved"
+	ErrMessageInvalidLocale           = "errors.good.invalidLocale"
+	ErrMessageInvalidTranslation      = "errors.good.invalidTranslation"
+	ErrMessageTranslationNotFound     = "errors.good.translationNotFound"
+	ErrMessageInvalidTransfer         = "errors.good.invalidTransfer"
+	ErrMessageVariantTransfer         = "errors.good.variantTransfer"
+	ErrMessageExternalKeyConflict     = "errors.good.externalKeyConflict"
 )
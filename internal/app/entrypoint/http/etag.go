@@ -0,0 +1,72 @@
+package http
+
+import (
+	"errors"
+	"github.com/vaberof/hezzl-backend/internal/app/entrypoint/http/views"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"github.com/vaberof/hezzl-backend/pkg/http/protocols/apiv1"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	etagHeader    = "ETag"
+	ifMatchHeader = "If-Match"
+)
+
+// etagLocaleSeparator separates the version from the locale in the entity tags of localized goods.
+const etagLocaleSeparator = "."
+
+var errMalformedIfMatch = errors.New("malformed If-Match header")
+
+// goodETag is a strong entity tag derived from the good version, which is bumped by every write.
+func goodETag(version domain.GoodVersion) string {
+	return `"` + strconv.FormatInt(version.Int64(), 10) + `"`
+}
+
+// localizedGoodETag is goodETag for a good served in the locale it was read in. Translations are changed without
+// bumping the good version, so the tag also holds the locale and, for a translated good, the time its translation
+// was last changed.
+func localizedGoodETag(domainGood *good.Good) string {
+	tag := strconv.FormatInt(domainGood.Version.Int64(), 10) + etagLocaleSeparator + domainGood.Locale.String()
+	if !domainGood.TranslatedAt.IsZero() {
+		tag += etagLocaleSeparator + strconv.FormatInt(domainGood.TranslatedAt.UnixMicro(), 10)
+	}
+	return `"` + tag + `"`
+}
+
+// expectedVersionFromRequest returns the good version the request is conditioned on with If-Match.
+// It returns nil if the header is absent or is "*", i.e. the good only has to exist.
+// Only single strong entity tags are accepted, since the API never issues weak ones.
+// Of a localized tag only the version is compared, since writes are checked against the good version alone.
+func expectedVersionFromRequest(request *http.Request) (*domain.GoodVersion, error) {
+	ifMatch := strings.TrimSpace(request.Header.Get(ifMatchHeader))
+	if ifMatch == "" || ifMatch == "*" {
+		return nil, nil
+	}
+
+	if len(ifMatch) < 2 || !strings.HasPrefix(ifMatch, `"`) || !strings.HasSuffix(ifMatch, `"`) {
+		return nil, errMalformedIfMatch
+	}
+
+	versionStr, _, _ := strings.Cut(ifMatch[1:len(ifMatch)-1], etagLocaleSeparator)
+
+	version, err := strconv.ParseInt(versionStr, 10, 64)
+	if err != nil {
+		return nil, errMalformedIfMatch
+	}
+
+	domainVersion := domain.GoodVersion(version)
+
+	return &domainVersion, nil
+}
+
+func renderMalformedIfMatch(rw http.ResponseWriter, request *http.Request) {
+	views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "'If-Match' header must hold a single strong entity tag or '*'"}))
+}
+
+func renderVersionMismatch(rw http.ResponseWriter, request *http.Request) {
+	views.RenderJSON(rw, request, http.StatusPreconditionFailed, apiv1.Error(CodePreconditionFailed, ErrMessageVersionMismatch, apiv1.ErrorDescription{"details": "Good has been modified since it was read"}))
+}
@@ -0,0 +1,68 @@
+package http
+
+import (
+	"errors"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLocalizedGoodETag(t *testing.T) {
+	translatedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	base := good.Good{Version: 3, Locale: "en"}
+	tag := localizedGoodETag(&base)
+
+	changes := map[string]good.Good{
+		"version":              {Version: 4, Locale: "en"},
+		"locale":               {Version: 3, Locale: "de"},
+		"translation":          {Version: 3, Locale: "en", TranslatedAt: translatedAt},
+		"translation revision": {Version: 3, Locale: "en", TranslatedAt: translatedAt.Add(time.Microsecond)},
+	}
+
+	seen := map[string]string{tag: "base"}
+	for name, changed := range changes {
+		changedTag := localizedGoodETag(&changed)
+		if other, ok := seen[changedTag]; ok {
+			t.Errorf("changing the %s keeps the tag %s of the %s", name, changedTag, other)
+		}
+		seen[changedTag] = name
+	}
+
+	if again := localizedGoodETag(&good.Good{Version: 3, Locale: "en"}); again != tag {
+		t.Errorf("got tag %s for the same good, want %s", again, tag)
+	}
+}
+
+func TestExpectedVersionFromLocalizedETag(t *testing.T) {
+	tags := []string{
+		goodETag(7),
+		localizedGoodETag(&good.Good{Version: 7, Locale: "en"}),
+		localizedGoodETag(&good.Good{Version: 7, Locale: "en", TranslatedAt: time.Now()}),
+	}
+
+	for _, tag := range tags {
+		request := httptest.NewRequest(http.MethodPatch, "/", nil)
+		request.Header.Set(ifMatchHeader, tag)
+
+		version, err := expectedVersionFromRequest(request)
+		if err != nil {
+			t.Fatalf("tag %s: %v", tag, err)
+		}
+		if version == nil || *version != domain.GoodVersion(7) {
+			t.Errorf("tag %s: got version %v, want 7", tag, version)
+		}
+	}
+
+	for _, ifMatch := range []string{`W/"7"`, `"en.7"`, `7`, `"7", "8"`} {
+		request := httptest.NewRequest(http.MethodPatch, "/", nil)
+		request.Header.Set(ifMatchHeader, ifMatch)
+
+		if _, err := expectedVersionFromRequest(request); !errors.Is(err, errMalformedIfMatch) {
+			t.Errorf("If-Match %s: got error %v, want %v", ifMatch, err, errMalformedIfMatch)
+		}
+	}
+}
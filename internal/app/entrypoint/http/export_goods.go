@@ -0,0 +1,229 @@
+package http
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"github.com/vaberof/hezzl-backend/internal/app/entrypoint/http/views"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/pkg/http/protocols/apiv1"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	exportFormatCSV    = "csv"
+	exportFormatNDJSON = "ndjson"
+)
+
+const (
+	defaultExportTimeout = 10 * time.Minute
+	exportFlushRows      = 500
+)
+
+type ExportConfig struct {
+	// Timeout limits how long a single export may stream, in place of the server request and write timeouts.
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+var goodExportCSVHeader = []string{"id", "project_id", "external_key", "name", "description", "priority", "removed", "created_at", "created_by", "updated_by", "version", "attributes", "price_amount", "price_currency", "stock", "reserved", "parent_id"}
+
+// goodExportWriter encodes exported goods; Flush writes the buffered goods to the response.
+type goodExportWriter interface {
+	WriteHeader() error
+	Write(domainGood *good.Good) error
+	Flush() error
+}
+
+// ExportGoodsHandler streams the goods of a project as a CSV or NDJSON download.
+// The response is written while the goods are read, so an error after the first good
+// aborts the connection instead of completing a truncated file.
+func (h *Handler) ExportGoodsHandler() http.HandlerFunc {
+	return func(rw http.ResponseWriter, request *http.Request) {
+		filter, ok := listFilterFromRequest(rw, request)
+		if !ok {
+			return
+		}
+
+		if filter.ProjectId == nil {
+			views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "Missing required query parameter 'projectId'"}))
+
+			return
+		}
+
+		format := request.URL.Query().Get("format")
+		if format == "" {
+			format = exportFormatCSV
+		}
+
+		var exportWriter goodExportWriter
+		var contentType string
+
+		switch format {
+		case exportFormatCSV:
+			exportWriter, contentType = newCSVGoodExportWriter(rw), "text/csv; charset=utf-8"
+		case exportFormatNDJSON:
+			exportWriter, contentType = newNDJSONGoodExportWriter(rw, h.buildListGoodPayload), "application/x-ndjson"
+		default:
+			views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "'format' must be 'csv' or 'ndjson'"}))
+
+			return
+		}
+
+		ctx, cancel := extendRequest(rw, request, h.exportTimeout())
+		defer cancel()
+
+		responseController := http.NewResponseController(rw)
+
+		fileName := "goods-" + strconv.FormatInt(filter.ProjectId.Int64(), 10) + "-" + time.Now().UTC().Format("20060102T150405Z") + "." + format
+
+		started := false
+		start := func() error {
+			started = true
+
+			rw.Header().Set("Content-Type", contentType)
+			rw.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+			rw.WriteHeader(http.StatusOK)
+
+			return exportWriter.WriteHeader()
+		}
+
+		rowCount := 0
+
+		err := h.goodService.Export(ctx, filter, func(domainGood *good.Good) error {
+			if !started {
+				if err := start(); err != nil {
+					return err
+				}
+			}
+
+			if err := exportWriter.Write(domainGood); err != nil {
+				return err
+			}
+
+			rowCount++
+			if rowCount%exportFlushRows != 0 {
+				return nil
+			}
+
+			if err := exportWriter.Flush(); err != nil {
+				return err
+			}
+			return responseController.Flush()
+		})
+		if err == nil && !started {
+			err = start()
+		}
+		if err == nil {
+			err = exportWriter.Flush()
+		}
+
+		if err != nil {
+			if !started {
+				views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to export goods"}))
+
+				return
+			}
+
+			slog.ErrorContext(request.Context(), "failed to export goods", "projectId", filter.ProjectId.Int64(), "exported", rowCount, "error", err)
+
+			panic(http.ErrAbortHandler)
+		}
+	}
+}
+
+func (h *Handler) exportTimeout() time.Duration {
+	if h.exportConfig.Timeout <= 0 {
+		return defaultExportTimeout
+	}
+	return h.exportConfig.Timeout
+}
+
+type csvGoodExportWriter struct {
+	writer *csv.Writer
+	record []string
+}
+
+func newCSVGoodExportWriter(w io.Writer) *csvGoodExportWriter {
+	return &csvGoodExportWriter{
+		writer: csv.NewWriter(w),
+		record: make([]string, len(goodExportCSVHeader)),
+	}
+}
+
+func (w *csvGoodExportWriter) WriteHeader() error {
+	return w.writer.Write(goodExportCSVHeader)
+}
+
+func (w *csvGoodExportWriter) Write(domainGood *good.Good) error {
+	w.record[0] = strconv.FormatInt(domainGood.Id.Int64(), 10)
+	w.record[1] = strconv.FormatInt(domainGood.ProjectId.Int64(), 10)
+	w.record[2] = domainGood.ExternalKey.String()
+	w.record[3] = domainGood.Name.String()
+	w.record[4] = domainGood.Description.String()
+	w.record[5] = strconv.Itoa(domainGood.Priority.Int())
+	w.record[6] = strconv.FormatBool(domainGood.Removed.Bool())
+	w.record[7] = domainGood.CreatedAt.Time().Format(time.RFC3339Nano)
+	w.record[8] = domainGood.CreatedBy.String()
+	w.record[9] = domainGood.UpdatedBy.String()
+	w.record[10] = strconv.FormatInt(domainGood.Version.Int64(), 10)
+
+	attributes, err := json.Marshal(domainGood.Attributes)
+	if err != nil {
+		return err
+	}
+	w.record[11] = string(attributes)
+
+	w.record[12], w.record[13] = "", ""
+	if domainGood.Price != nil {
+		w.record[12] = strconv.FormatInt(domainGood.Price.Amount, 10)
+		w.record[13] = domainGood.Price.Currency
+	}
+	w.record[14] = strconv.FormatInt(domainGood.Stock, 10)
+	w.record[15] = strconv.FormatInt(domainGood.Reserved, 10)
+
+	w.record[16] = ""
+	if domainGood.ParentId != nil {
+		w.record[16] = strconv.FormatInt(domainGood.ParentId.Int64(), 10)
+	}
+
+	return w.writer.Write(w.record)
+}
+
+func (w *csvGoodExportWriter) Flush() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+// ndjsonGoodExportWriter writes one good per line in the shape of the list payload.
+type ndjsonGoodExportWriter struct {
+	writer       *bufio.Writer
+	encoder      *json.Encoder
+	buildPayload func(domainGood *good.Good) *listGoodPayload
+}
+
+func newNDJSONGoodExportWriter(w io.Writer, buildPayload func(domainGood *good.Good) *listGoodPayload) *ndjsonGoodExportWriter {
+	writer := bufio.NewWriter(w)
+
+	return &ndjsonGoodExportWriter{
+		writer:       writer,
+		encoder:      json.NewEncoder(writer),
+		buildPayload: buildPayload,
+	}
+}
+
+func (w *ndjsonGoodExportWriter) WriteHeader() error {
+	return nil
+}
+
+func (w *ndjsonGoodExportWriter) Write(domainGood *good.Good) error {
+	return w.encoder.Encode(w.buildPayload(domainGood))
+}
+
+func (w *ndjsonGoodExportWriter) Flush() error {
+	return w.writer.Flush()
+}
@@ -0,0 +1,122 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/vaberof/hezzl-backend/internal/app/entrypoint/http/views"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"github.com/vaberof/hezzl-backend/pkg/http/protocols/apiv1"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type getGoodResponseBody struct {
+	Id          int64              `json:"id"`
+	ProjectId   int64              `json:"projectId"`
+	ParentId    *int64             `json:"parentId,omitempty"`
+	Locale      string             `json:"locale"`
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Priority    int                `json:"priority"`
+	Removed     bool               `json:"removed"`
+	CreatedAt   time.Time          `json:"createdAt"`
+	CreatedBy   string             `json:"createdBy"`
+	UpdatedBy   string             `json:"updatedBy"`
+	Version     int64              `json:"version"`
+	ExternalKey string             `json:"externalKey,omitempty"`
+	Attributes  map[string]any     `json:"attributes"`
+	Price       *pricePayload      `json:"price"`
+	Stock       int64              `json:"stock"`
+	Reserved    int64              `json:"reserved"`
+	Variants    []*listGoodPayload `json:"variants,omitempty"`
+}
+
+func (h *Handler) GetGoodHandler() http.HandlerFunc {
+	return func(rw http.ResponseWriter, request *http.Request) {
+		goodIdStr := request.URL.Query().Get("id")
+		if goodIdStr == "" {
+			views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "Missing required query parameter 'id'"}))
+
+			return
+		}
+
+		projectIdStr := request.URL.Query().Get("projectId")
+		if projectIdStr == "" {
+			views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "Missing required query parameter 'projectId'"}))
+
+			return
+		}
+
+		goodId, err := strconv.ParseInt(goodIdStr, 10, 64)
+		if err != nil {
+			views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to convert id to int"}))
+
+			return
+		}
+
+		projectId, err := strconv.ParseInt(projectIdStr, 10, 64)
+		if err != nil {
+			views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to convert projectId to int"}))
+
+			return
+		}
+
+		expand, ok := expandVariantsFromRequest(rw, request)
+		if !ok {
+			return
+		}
+
+		locales := localesFromRequest(request)
+
+		domainGood, err := h.goodService.Get(request.Context(), domain.GoodId(goodId), domain.ProjectId(projectId), locales)
+		if err != nil {
+			if errors.Is(err, good.ErrGoodNotFound) {
+				views.RenderJSON(rw, request, http.StatusNotFound, apiv1.Error(CodeNotFound, ErrMessageGoodNotFound, apiv1.ErrorDescription{"details": "Good is not found"}))
+			} else {
+				views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to get a good"}))
+			}
+
+			return
+		}
+
+		var variants []*listGoodPayload
+		if expand {
+			if err = h.goodService.ExpandVariants(request.Context(), []*good.Good{domainGood}, locales); err != nil {
+				views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to get variants"}))
+
+				return
+			}
+			if domainGood.Variants != nil {
+				variants = h.buildListGoodPayloads(domainGood.Variants)
+			}
+		}
+
+		payload, _ := json.Marshal(&getGoodResponseBody{
+			Id:          domainGood.Id.Int64(),
+			ProjectId:   domainGood.ProjectId.Int64(),
+			ParentId:    parentIdPayload(domainGood),
+			Locale:      domainGood.Locale.String(),
+			Name:        domainGood.Name.String(),
+			Description: domainGood.Description.String(),
+			Priority:    domainGood.Priority.Int(),
+			Removed:     domainGood.Removed.Bool(),
+			CreatedAt:   domainGood.CreatedAt.Time(),
+			CreatedBy:   domainGood.CreatedBy.String(),
+			UpdatedBy:   domainGood.UpdatedBy.String(),
+			Version:     domainGood.Version.Int64(),
+			ExternalKey: domainGood.ExternalKey.String(),
+			Attributes:  domainGood.Attributes,
+			Price:       buildPricePayload(domainGood.Price),
+			Stock:       domainGood.Stock,
+			Reserved:    domainGood.Reserved,
+			Variants:    variants,
+		})
+
+		rw.Header().Set(etagHeader, localizedGoodETag(domainGood))
+		setContentLanguage(rw, domainGood.Locale)
+
+		views.RenderJSON(rw, request, http.StatusOK, apiv1.Success(payload))
+	}
+}
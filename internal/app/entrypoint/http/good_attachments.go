@@ -0,0 +1,298 @@
+package http
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"github.com/vaberof/hezzl-backend/internal/app/entrypoint/http/views"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"github.com/vaberof/hezzl-backend/pkg/http/protocols/apiv1"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sniffLength is how many bytes http.DetectContentType considers.
+const sniffLength = 512
+
+const (
+	defaultAttachmentMaxBytes = 10 << 20
+	defaultAttachmentTimeout  = 5 * time.Minute
+)
+
+var defaultAttachmentContentTypes = []string{"image/jpeg", "image/png", "image/gif", "image/webp", "application/pdf", "text/plain"}
+
+type AttachmentConfig struct {
+	// MaxBytes limits the size of a single attachment.
+	MaxBytes int64 `yaml:"max_bytes"`
+	// AllowedContentTypes are the media types attachments may have. The type is sniffed from the content,
+	// so only types http.DetectContentType recognizes can be allowed.
+	AllowedContentTypes []string `yaml:"allowed_content_types"`
+	// Timeout limits how long a single upload or download may take, in place of the server request,
+	// read and write timeouts.
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+type attachmentResponseBody struct {
+	Id          int64     `json:"id"`
+	GoodId      int64     `json:"goodId"`
+	ProjectId   int64     `json:"projectId"`
+	Name        string    `json:"name"`
+	ContentType string    `json:"contentType"`
+	Size        int64     `json:"size"`
+	Checksum    string    `json:"checksum"`
+	CreatedAt   time.Time `json:"createdAt"`
+	CreatedBy   string    `json:"createdBy"`
+}
+
+type listAttachmentsResponseBody struct {
+	Attachments []*attachmentResponseBody `json:"attachments"`
+}
+
+// UploadAttachmentHandler stores the raw request body as an attachment of the good.
+// The body is streamed to the blob store, so its length must be declared up front.
+// The content type is sniffed from the content instead of trusting the one declared by the client.
+func (h *Handler) UploadAttachmentHandler() http.HandlerFunc {
+	return func(rw http.ResponseWriter, request *http.Request) {
+		goodId, projectId, ok := goodIdsFromRequest(rw, request)
+		if !ok {
+			return
+		}
+
+		name := request.URL.Query().Get("name")
+		if name == "" {
+			views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "Missing required query parameter 'name'"}))
+
+			return
+		}
+
+		if request.ContentLength < 0 {
+			views.RenderJSON(rw, request, http.StatusLengthRequired, apiv1.Error(CodeLengthRequired, ErrMessageLengthRequired, apiv1.ErrorDescription{"details": "Content-Length header is required"}))
+
+			return
+		}
+
+		ctx, cancel := extendRequest(rw, request, h.attachmentTimeout())
+		defer cancel()
+
+		content := bufio.NewReaderSize(request.Body, sniffLength)
+
+		head, err := content.Peek(sniffLength)
+		if err != nil && !errors.Is(err, io.EOF) {
+			renderBindError(rw, request, err)
+
+			return
+		}
+
+		contentType := http.DetectContentType(head)
+		if !h.attachmentContentTypeAllowed(contentType) {
+			views.RenderJSON(rw, request, http.StatusUnsupportedMediaType, apiv1.Error(CodeUnsupportedMediaType, ErrMessageUnsupportedMediaType, apiv1.ErrorDescription{"details": "Attachment content type '" + contentType + "' is not allowed"}))
+
+			return
+		}
+
+		actor, _ := ActorFromContext(request.Context())
+
+		attachment, err := h.goodService.AddAttachment(ctx, goodId, projectId, name, contentType, request.ContentLength, content, actor)
+		if err != nil {
+			renderAttachmentError(rw, request, err, "Failed to upload an attachment")
+
+			return
+		}
+
+		payload, _ := json.Marshal(buildAttachmentResponseBody(attachment))
+
+		views.RenderJSON(rw, request, http.StatusOK, apiv1.Success(payload))
+	}
+}
+
+func (h *Handler) ListAttachmentsHandler() http.HandlerFunc {
+	return func(rw http.ResponseWriter, request *http.Request) {
+		goodId, projectId, ok := goodIdsFromRequest(rw, request)
+		if !ok {
+			return
+		}
+
+		attachments, err := h.goodService.ListAttachments(request.Context(), goodId, projectId)
+		if err != nil {
+			renderAttachmentError(rw, request, err, "Failed to list attachments")
+
+			return
+		}
+
+		responseBody := &listAttachmentsResponseBody{Attachments: make([]*attachmentResponseBody, len(attachments))}
+		for i := range attachments {
+			responseBody.Attachments[i] = buildAttachmentResponseBody(attachments[i])
+		}
+
+		payload, _ := json.Marshal(responseBody)
+
+		views.RenderJSON(rw, request, http.StatusOK, apiv1.Success(payload))
+	}
+}
+
+// DownloadAttachmentHandler streams the content of the attachment. The checksum serves as a strong entity tag,
+// since the content of an attachment never changes.
+func (h *Handler) DownloadAttachmentHandler() http.HandlerFunc {
+	return func(rw http.ResponseWriter, request *http.Request) {
+		goodId, projectId, ok := goodIdsFromRequest(rw, request)
+		if !ok {
+			return
+		}
+
+		attachmentId, ok := attachmentIdFromRequest(rw, request)
+		if !ok {
+			return
+		}
+
+		// The blob is streamed under the extended context, since the content reader is bound to it.
+		ctx, cancel := extendRequest(rw, request, h.attachmentTimeout())
+		defer cancel()
+
+		attachment, content, err := h.goodService.OpenAttachment(ctx, goodId, projectId, attachmentId)
+		if err != nil {
+			renderAttachmentError(rw, request, err, "Failed to download an attachment")
+
+			return
+		}
+		defer content.Close()
+
+		etag := `"` + attachment.Checksum + `"`
+
+		rw.Header().Set(etagHeader, etag)
+		if request.Header.Get("If-None-Match") == etag {
+			rw.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		// Images are shown by browsers, anything else is downloaded; sniffing is disabled so that
+		// an attachment is never interpreted as another type than the one it was accepted as.
+		disposition := "attachment"
+		if strings.HasPrefix(attachment.ContentType, "image/") {
+			disposition = "inline"
+		}
+
+		rw.Header().Set("Content-Type", attachment.ContentType)
+		rw.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+		rw.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": attachment.Name}))
+		rw.Header().Set("X-Content-Type-Options", "nosniff")
+		rw.WriteHeader(http.StatusOK)
+
+		if _, err = io.Copy(rw, content); err != nil {
+			slog.ErrorContext(request.Context(), "failed to download attachment", "attachmentId", attachmentId.Int64(), "error", err)
+
+			panic(http.ErrAbortHandler)
+		}
+	}
+}
+
+func (h *Handler) RemoveAttachmentHandler() http.HandlerFunc {
+	return func(rw http.ResponseWriter, request *http.Request) {
+		goodId, projectId, ok := goodIdsFromRequest(rw, request)
+		if !ok {
+			return
+		}
+
+		attachmentId, ok := attachmentIdFromRequest(rw, request)
+		if !ok {
+			return
+		}
+
+		attachment, err := h.goodService.RemoveAttachment(request.Context(), goodId, projectId, attachmentId)
+		if err != nil {
+			renderAttachmentError(rw, request, err, "Failed to remove an attachment")
+
+			return
+		}
+
+		payload, _ := json.Marshal(buildAttachmentResponseBody(attachment))
+
+		views.RenderJSON(rw, request, http.StatusOK, apiv1.Success(payload))
+	}
+}
+
+func (h *Handler) attachmentMaxBytes() int64 {
+	if h.attachmentConfig.MaxBytes <= 0 {
+		return defaultAttachmentMaxBytes
+	}
+	return h.attachmentConfig.MaxBytes
+}
+
+func (h *Handler) attachmentTimeout() time.Duration {
+	if h.attachmentConfig.Timeout <= 0 {
+		return defaultAttachmentTimeout
+	}
+	return h.attachmentConfig.Timeout
+}
+
+func (h *Handler) attachmentContentTypeAllowed(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	allowed := h.attachmentConfig.AllowedContentTypes
+	if len(allowed) == 0 {
+		allowed = defaultAttachmentContentTypes
+	}
+
+	return slices.Contains(allowed, mediaType)
+}
+
+func attachmentIdFromRequest(rw http.ResponseWriter, request *http.Request) (domain.AttachmentId, bool) {
+	attachmentIdStr := request.URL.Query().Get("attachmentId")
+	if attachmentIdStr == "" {
+		views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "Missing required query parameter 'attachmentId'"}))
+
+		return 0, false
+	}
+
+	attachmentId, err := strconv.ParseInt(attachmentIdStr, 10, 64)
+	if err != nil {
+		views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to convert attachmentId to int"}))
+
+		return 0, false
+	}
+
+	return domain.AttachmentId(attachmentId), true
+}
+
+func buildAttachmentResponseBody(attachment *good.Attachment) *attachmentResponseBody {
+	return &attachmentResponseBody{
+		Id:          attachment.Id.Int64(),
+		GoodId:      attachment.GoodId.Int64(),
+		ProjectId:   attachment.ProjectId.Int64(),
+		Name:        attachment.Name,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		Checksum:    attachment.Checksum,
+		CreatedAt:   attachment.CreatedAt,
+		CreatedBy:   attachment.CreatedBy.String(),
+	}
+}
+
+func renderAttachmentError(rw http.ResponseWriter, request *http.Request, err error, details string) {
+	var maxBytesErr *http.MaxBytesError
+
+	switch {
+	case errors.As(err, &maxBytesErr):
+		renderRequestTooLarge(rw, request, maxBytesErr.Limit)
+	case errors.Is(err, good.ErrInvalidAttachment):
+		views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidAttachment, apiv1.ErrorDescription{"details": err.Error()}))
+	case errors.Is(err, good.ErrGoodNotFound):
+		views.RenderJSON(rw, request, http.StatusNotFound, apiv1.Error(CodeNotFound, ErrMessageGoodNotFound, apiv1.ErrorDescription{"details": "Good is not found"}))
+	case errors.Is(err, good.ErrAttachmentNotFound):
+		views.RenderJSON(rw, request, http.StatusNotFound, apiv1.Error(CodeNotFound, ErrMessageAttachmentNotFound, apiv1.ErrorDescription{"details": "Attachment is not found"}))
+	case errors.Is(err, good.ErrGoodRemoved):
+		views.RenderJSON(rw, request, http.StatusConflict, apiv1.Error(CodeConflict, ErrMessageGoodRemoved, apiv1.ErrorDescription{"details": "Good is removed"}))
+	default:
+		views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": details}))
+	}
+}
@@ -0,0 +1,15 @@
+package http
+
+import (
+	"context"
+	"github.com/vaberof/hezzl-backend/internal/app/goodimport"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"io"
+)
+
+type GoodImporter interface {
+	Import(ctx context.Context, projectId domain.ProjectId, format goodimport.Format, body io.Reader, actor domain.Actor) (*good.ImportReport, error)
+	Submit(ctx context.Context, projectId domain.ProjectId, format goodimport.Format, body io.Reader, actor domain.Actor) (*goodimport.Job, error)
+	Job(ctx context.Context, jobId string) (*goodimport.Job, error)
+}
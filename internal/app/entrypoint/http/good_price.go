@@ -0,0 +1,87 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/render"
+	"github.com/vaberof/hezzl-backend/internal/app/entrypoint/http/views"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/pkg/http/protocols/apiv1"
+	"net/http"
+)
+
+// pricePayload is a price in minor units of the currency, e.g. cents.
+type pricePayload struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+type setGoodPriceRequestBody struct {
+	// Price removes the price of the good if it is null.
+	Price *pricePayload `json:"price"`
+}
+
+func (s *setGoodPriceRequestBody) Bind(req *http.Request) error {
+	return nil
+}
+
+func (h *Handler) SetGoodPriceHandler() http.HandlerFunc {
+	return func(rw http.ResponseWriter, request *http.Request) {
+		goodId, projectId, ok := goodIdsFromRequest(rw, request)
+		if !ok {
+			return
+		}
+
+		setGoodPriceReqBody := &setGoodPriceRequestBody{}
+		if err := render.Bind(request, setGoodPriceReqBody); err != nil {
+			renderBindError(rw, request, err)
+
+			return
+		}
+
+		expectedVersion, err := expectedVersionFromRequest(request)
+		if err != nil {
+			renderMalformedIfMatch(rw, request)
+
+			return
+		}
+
+		var price *good.Price
+		if setGoodPriceReqBody.Price != nil {
+			price = &good.Price{Amount: setGoodPriceReqBody.Price.Amount, Currency: setGoodPriceReqBody.Price.Currency}
+		}
+
+		actor, _ := ActorFromContext(request.Context())
+
+		domainGood, err := h.goodService.SetPrice(request.Context(), goodId, projectId, price, actor, expectedVersion)
+		if err != nil {
+			switch {
+			case errors.Is(err, good.ErrInvalidPrice):
+				views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidPrice, apiv1.ErrorDescription{"details": err.Error()}))
+			case errors.Is(err, good.ErrGoodNotFound):
+				views.RenderJSON(rw, request, http.StatusNotFound, apiv1.Error(CodeNotFound, ErrMessageGoodNotFound, apiv1.ErrorDescription{"details": "Good is not found"}))
+			case errors.Is(err, good.ErrGoodRemoved):
+				views.RenderJSON(rw, request, http.StatusConflict, apiv1.Error(CodeConflict, ErrMessageGoodRemoved, apiv1.ErrorDescription{"details": "Good is removed"}))
+			case errors.Is(err, good.ErrGoodVersionMismatch):
+				renderVersionMismatch(rw, request)
+			default:
+				views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to set a good price"}))
+			}
+
+			return
+		}
+
+		payload, _ := json.Marshal(h.buildListGoodPayload(domainGood))
+
+		rw.Header().Set(etagHeader, goodETag(domainGood.Version))
+
+		views.RenderJSON(rw, request, http.StatusOK, apiv1.Success(payload))
+	}
+}
+
+func buildPricePayload(price *good.Price) *pricePayload {
+	if price == nil {
+		return nil
+	}
+	return &pricePayload{Amount: price.Amount, Currency: price.Currency}
+}
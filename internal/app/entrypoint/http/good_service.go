@@ -1,14 +1,50 @@
 package http
 
 import (
+	"context"
+	"encoding/json"
 	"github.com/vaberof/hezzl-backend/internal/domain/good"
 	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"io"
 )
 
 type GoodService interface {
-	Create(projectId domain.ProjectId, name domain.GoodName) (*good.Good, error)
-	Update(id domain.GoodId, projectId domain.ProjectId, name domain.GoodName, description *domain.GoodDescription) (*good.Good, error)
-	Delete(id domain.GoodId, projectId domain.ProjectId) (*good.Good, error)
-	List(limit, offset int) ([]*good.Good, error)
-	ChangePriority(id domain.GoodId, projectId domain.ProjectId, newPriority domain.GoodPriority) ([]*good.Good, error)
+	Create(ctx context.Context, projectId domain.ProjectId, name domain.GoodName, attributes domain.GoodAttributes, actor domain.Actor) (*good.Good, error)
+	Update(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, name domain.GoodName, description *domain.GoodDescription, attributes domain.GoodAttributes, actor domain.Actor, expectedVersion *domain.GoodVersion) (*good.Good, error)
+	Delete(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, actor domain.Actor, expectedVersion *domain.GoodVersion) (*good.Good, error)
+	Restore(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, actor domain.Actor, expectedVersion *domain.GoodVersion) (*good.Good, error)
+	Purge(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, actor domain.Actor, expectedVersion *domain.GoodVersion) (*good.Good, error)
+	Get(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, locales []domain.Locale) (*good.Good, error)
+	CreateBatch(ctx context.Context, items []*good.CreateItem, actor domain.Actor) ([]*good.Good, error)
+	UpdateBatch(ctx context.Context, items []*good.UpdateItem, actor domain.Actor) ([]*good.BatchResult, error)
+	DeleteBatch(ctx context.Context, items []*good.RemoveItem, actor domain.Actor) ([]*good.BatchResult, error)
+	List(ctx context.Context, filter *good.ListFilter, limit, offset int, locales []domain.Locale) ([]*good.Good, error)
+	Export(ctx context.Context, filter *good.ListFilter, fn func(*good.Good) error) error
+	Search(ctx context.Context, projectId domain.ProjectId, text string, limit, offset int) ([]*good.SearchResult, error)
+	AddTags(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, tags []domain.TagName, actor domain.Actor) ([]domain.TagName, error)
+	RemoveTags(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, tags []domain.TagName, actor domain.Actor) ([]domain.TagName, error)
+	GetTags(ctx context.Context, id domain.GoodId, projectId domain.ProjectId) ([]domain.TagName, error)
+	ListTags(ctx context.Context, projectId domain.ProjectId) ([]*good.Tag, error)
+	GetAttributeSchema(ctx context.Context, projectId domain.ProjectId) (*good.AttributeSchema, error)
+	SetAttributeSchema(ctx context.Context, projectId domain.ProjectId, schema json.RawMessage, actor domain.Actor) (*good.AttributeSchema, error)
+	DeleteAttributeSchema(ctx context.Context, projectId domain.ProjectId) error
+	AddAttachment(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, name, contentType string, size int64, content io.Reader, actor domain.Actor) (*good.Attachment, error)
+	ListAttachments(ctx context.Context, id domain.GoodId, projectId domain.ProjectId) ([]*good.Attachment, error)
+	OpenAttachment(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, attachmentId domain.AttachmentId) (*good.Attachment, io.ReadCloser, error)
+	RemoveAttachment(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, attachmentId domain.AttachmentId) (*good.Attachment, error)
+	SetPrice(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, price *good.Price, actor domain.Actor, expectedVersion *domain.GoodVersion) (*good.Good, error)
+	ReserveStock(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, quantity int64, reason string, actor domain.Actor) (*good.StockMovement, error)
+	ReleaseStock(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, quantity int64, reason string, actor domain.Actor) (*good.StockMovement, error)
+	AdjustStock(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, delta int64, reason string, actor domain.Actor) (*good.StockMovement, error)
+	ListStockMovements(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, limit, offset int) ([]*good.StockMovement, error)
+	CreateVariant(ctx context.Context, parentId domain.GoodId, projectId domain.ProjectId, name domain.GoodName, attributes domain.GoodAttributes, actor domain.Actor) (*good.Good, error)
+	ExpandVariants(ctx context.Context, domainGoods []*good.Good, locales []domain.Locale) error
+	SetTranslation(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, locale domain.Locale, name domain.GoodName, description domain.GoodDescription, actor domain.Actor) (*good.Translation, error)
+	RemoveTranslation(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, locale domain.Locale, actor domain.Actor) (*good.Translation, error)
+	ListTranslations(ctx context.Context, id domain.GoodId, projectId domain.ProjectId) ([]*good.Translation, error)
+	GetDefaultLocale(ctx context.Context, projectId domain.ProjectId) (domain.Locale, error)
+	SetDefaultLocale(ctx context.Context, projectId domain.ProjectId, locale domain.Locale) (domain.Locale, error)
+	Move(ctx context.Context, id domain.GoodId, projectId, targetProjectId domain.ProjectId, options *good.TransferOptions, actor domain.Actor, expectedVersion *domain.GoodVersion) (*good.Good, error)
+	Copy(ctx context.Context, id domain.GoodId, projectId, targetProjectId domain.ProjectId, options *good.TransferOptions, actor domain.Actor) (*good.Good, error)
+	ChangePriority(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, newPriority domain.GoodPriority, actor domain.Actor, expectedVersion *domain.GoodVersion) ([]*good.Good, error)
 }
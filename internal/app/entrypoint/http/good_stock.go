@@ -0,0 +1,176 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/render"
+	"github.com/vaberof/hezzl-backend/internal/app/entrypoint/http/views"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"github.com/vaberof/hezzl-backend/pkg/http/protocols/apiv1"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const maxStockMovementsLimit = 100
+
+type stockMovementRequestBody struct {
+	// Quantity is positive for reservations and releases; adjustments add it to the stock, or remove it if it is negative.
+	Quantity int64  `json:"quantity"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+func (s *stockMovementRequestBody) Bind(req *http.Request) error {
+	return nil
+}
+
+type stockMovementResponseBody struct {
+	Id        int64     `json:"id"`
+	GoodId    int64     `json:"goodId"`
+	ProjectId int64     `json:"projectId"`
+	Kind      string    `json:"kind"`
+	Quantity  int64     `json:"quantity"`
+	Stock     int64     `json:"stock"`
+	Reserved  int64     `json:"reserved"`
+	Available int64     `json:"available"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	CreatedBy string    `json:"createdBy"`
+}
+
+type listStockMovementsResponseBody struct {
+	StockMovements []*stockMovementResponseBody `json:"stockMovements"`
+}
+
+type moveStockFunc func(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, quantity int64, reason string, actor domain.Actor) (*good.StockMovement, error)
+
+func (h *Handler) ReserveStockHandler() http.HandlerFunc {
+	return h.moveStockHandler(h.goodService.ReserveStock, "Failed to reserve stock")
+}
+
+func (h *Handler) ReleaseStockHandler() http.HandlerFunc {
+	return h.moveStockHandler(h.goodService.ReleaseStock, "Failed to release stock")
+}
+
+func (h *Handler) AdjustStockHandler() http.HandlerFunc {
+	return h.moveStockHandler(h.goodService.AdjustStock, "Failed to adjust stock")
+}
+
+func (h *Handler) moveStockHandler(moveStock moveStockFunc, details string) http.HandlerFunc {
+	return func(rw http.ResponseWriter, request *http.Request) {
+		goodId, projectId, ok := goodIdsFromRequest(rw, request)
+		if !ok {
+			return
+		}
+
+		stockMovementReqBody := &stockMovementRequestBody{}
+		if err := render.Bind(request, stockMovementReqBody); err != nil {
+			renderBindError(rw, request, err)
+
+			return
+		}
+
+		actor, _ := ActorFromContext(request.Context())
+
+		stockMovement, err := moveStock(request.Context(), goodId, projectId, stockMovementReqBody.Quantity, stockMovementReqBody.Reason, actor)
+		if err != nil {
+			renderStockError(rw, request, err, details)
+
+			return
+		}
+
+		payload, _ := json.Marshal(buildStockMovementResponseBody(stockMovement))
+
+		views.RenderJSON(rw, request, http.StatusOK, apiv1.Success(payload))
+	}
+}
+
+func (h *Handler) ListStockMovementsHandler() http.HandlerFunc {
+	return func(rw http.ResponseWriter, request *http.Request) {
+		goodId, projectId, ok := goodIdsFromRequest(rw, request)
+		if !ok {
+			return
+		}
+
+		var err error
+
+		limit := defaultLimit
+		if limitStr := request.URL.Query().Get("limit"); limitStr != "" {
+			limit, err = strconv.Atoi(limitStr)
+			if err != nil {
+				views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to convert limit to int"}))
+
+				return
+			}
+			if limit < 0 || limit > maxStockMovementsLimit {
+				views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "'limit' must be between 0 and " + strconv.Itoa(maxStockMovementsLimit)}))
+
+				return
+			}
+		}
+
+		offset := 0
+		if offsetStr := request.URL.Query().Get("offset"); offsetStr != "" {
+			offset, err = strconv.Atoi(offsetStr)
+			if err != nil {
+				views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to convert offset to int"}))
+
+				return
+			}
+			if offset < 0 {
+				views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "'offset' must not be negative"}))
+
+				return
+			}
+		}
+
+		stockMovements, err := h.goodService.ListStockMovements(request.Context(), goodId, projectId, limit, offset)
+		if err != nil {
+			renderStockError(rw, request, err, "Failed to list stock movements")
+
+			return
+		}
+
+		responseBody := &listStockMovementsResponseBody{StockMovements: make([]*stockMovementResponseBody, len(stockMovements))}
+		for i := range stockMovements {
+			responseBody.StockMovements[i] = buildStockMovementResponseBody(stockMovements[i])
+		}
+
+		payload, _ := json.Marshal(responseBody)
+
+		views.RenderJSON(rw, request, http.StatusOK, apiv1.Success(payload))
+	}
+}
+
+func buildStockMovementResponseBody(stockMovement *good.StockMovement) *stockMovementResponseBody {
+	return &stockMovementResponseBody{
+		Id:        stockMovement.Id.Int64(),
+		GoodId:    stockMovement.GoodId.Int64(),
+		ProjectId: stockMovement.ProjectId.Int64(),
+		Kind:      string(stockMovement.Kind),
+		Quantity:  stockMovement.Quantity,
+		Stock:     stockMovement.Stock,
+		Reserved:  stockMovement.Reserved,
+		Available: stockMovement.Stock - stockMovement.Reserved,
+		Reason:    stockMovement.Reason,
+		CreatedAt: stockMovement.CreatedAt,
+		CreatedBy: stockMovement.CreatedBy.String(),
+	}
+}
+
+func renderStockError(rw http.ResponseWriter, request *http.Request, err error, details string) {
+	switch {
+	case errors.Is(err, good.ErrInvalidStockMovement):
+		views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidStockMovement, apiv1.ErrorDescription{"details": err.Error()}))
+	case errors.Is(err, good.ErrInsufficientStock):
+		views.RenderJSON(rw, request, http.StatusConflict, apiv1.Error(CodeConflict, ErrMessageInsufficientStock, apiv1.ErrorDescription{"details": "Not enough stock for the movement"}))
+	case errors.Is(err, good.ErrGoodNotFound):
+		views.RenderJSON(rw, request, http.StatusNotFound, apiv1.Error(CodeNotFound, ErrMessageGoodNotFound, apiv1.ErrorDescription{"details": "Good is not found"}))
+	case errors.Is(err, good.ErrGoodRemoved):
+		views.RenderJSON(rw, request, http.StatusConflict, apiv1.Error(CodeConflict, ErrMessageGoodRemoved, apiv1.ErrorDescription{"details": "Good is removed"}))
+	default:
+		views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": details}))
+	}
+}
@@ -0,0 +1,169 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/render"
+	"github.com/vaberof/hezzl-backend/internal/app/entrypoint/http/views"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"github.com/vaberof/hezzl-backend/pkg/http/protocols/apiv1"
+	"net/http"
+	"strconv"
+)
+
+type goodTagsRequestBody struct {
+	Tags []string `json:"tags"`
+}
+
+func (g *goodTagsRequestBody) Bind(req *http.Request) error {
+	return nil
+}
+
+type goodTagsResponseBody struct {
+	Id        int64    `json:"id"`
+	ProjectId int64    `json:"projectId"`
+	Tags      []string `json:"tags"`
+}
+
+func (h *Handler) GetGoodTagsHandler() http.HandlerFunc {
+	return func(rw http.ResponseWriter, request *http.Request) {
+		goodId, projectId, ok := goodIdsFromRequest(rw, request)
+		if !ok {
+			return
+		}
+
+		goodTags, err := h.goodService.GetTags(request.Context(), goodId, projectId)
+		if err != nil {
+			renderGoodTagsError(rw, request, err, "Failed to get good tags")
+
+			return
+		}
+
+		renderGoodTags(rw, request, goodId, projectId, goodTags)
+	}
+}
+
+func (h *Handler) AddGoodTagsHandler() http.HandlerFunc {
+	return func(rw http.ResponseWriter, request *http.Request) {
+		goodId, projectId, ok := goodIdsFromRequest(rw, request)
+		if !ok {
+			return
+		}
+
+		goodTagsReqBody := &goodTagsRequestBody{}
+		if err := render.Bind(request, goodTagsReqBody); err != nil {
+			renderBindError(rw, request, err)
+
+			return
+		}
+
+		actor, _ := ActorFromContext(request.Context())
+
+		goodTags, err := h.goodService.AddTags(request.Context(), goodId, projectId, toTagNames(goodTagsReqBody.Tags), actor)
+		if err != nil {
+			renderGoodTagsError(rw, request, err, "Failed to add good tags")
+
+			return
+		}
+
+		renderGoodTags(rw, request, goodId, projectId, goodTags)
+	}
+}
+
+func (h *Handler) RemoveGoodTagsHandler() http.HandlerFunc {
+	return func(rw http.ResponseWriter, request *http.Request) {
+		goodId, projectId, ok := goodIdsFromRequest(rw, request)
+		if !ok {
+			return
+		}
+
+		goodTagsReqBody := &goodTagsRequestBody{}
+		if err := render.Bind(request, goodTagsReqBody); err != nil {
+			renderBindError(rw, request, err)
+
+			return
+		}
+
+		actor, _ := ActorFromContext(request.Context())
+
+		goodTags, err := h.goodService.RemoveTags(request.Context(), goodId, projectId, toTagNames(goodTagsReqBody.Tags), actor)
+		if err != nil {
+			renderGoodTagsError(rw, request, err, "Failed to remove good tags")
+
+			return
+		}
+
+		renderGoodTags(rw, request, goodId, projectId, goodTags)
+	}
+}
+
+// goodIdsFromRequest reads the required 'id' and 'projectId' query parameters of a good.
+// It responds with an error and returns false if they are missing or malformed.
+func goodIdsFromRequest(rw http.ResponseWriter, request *http.Request) (domain.GoodId, domain.ProjectId, bool) {
+	goodIdStr := request.URL.Query().Get("id")
+	if goodIdStr == "" {
+		views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "Missing required query parameter 'id'"}))
+
+		return 0, 0, false
+	}
+
+	projectIdStr := request.URL.Query().Get("projectId")
+	if projectIdStr == "" {
+		views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "Missing required query parameter 'projectId'"}))
+
+		return 0, 0, false
+	}
+
+	goodId, err := strconv.ParseInt(goodIdStr, 10, 64)
+	if err != nil {
+		views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to convert id to int"}))
+
+		return 0, 0, false
+	}
+
+	projectId, err := strconv.ParseInt(projectIdStr, 10, 64)
+	if err != nil {
+		views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to convert projectId to int"}))
+
+		return 0, 0, false
+	}
+
+	return domain.GoodId(goodId), domain.ProjectId(projectId), true
+}
+
+func renderGoodTags(rw http.ResponseWriter, request *http.Request, goodId domain.GoodId, projectId domain.ProjectId, goodTags []domain.TagName) {
+	tags := make([]string, len(goodTags))
+	for i := range goodTags {
+		tags[i] = goodTags[i].String()
+	}
+
+	payload, _ := json.Marshal(&goodTagsResponseBody{
+		Id:        goodId.Int64(),
+		ProjectId: projectId.Int64(),
+		Tags:      tags,
+	})
+
+	views.RenderJSON(rw, request, http.StatusOK, apiv1.Success(payload))
+}
+
+func renderGoodTagsError(rw http.ResponseWriter, request *http.Request, err error, details string) {
+	switch {
+	case errors.Is(err, good.ErrInvalidTags):
+		views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": err.Error()}))
+	case errors.Is(err, good.ErrGoodNotFound):
+		views.RenderJSON(rw, request, http.StatusNotFound, apiv1.Error(CodeNotFound, ErrMessageGoodNotFound, apiv1.ErrorDescription{"details": "Good is not found"}))
+	case errors.Is(err, good.ErrGoodRemoved):
+		views.RenderJSON(rw, request, http.StatusConflict, apiv1.Error(CodeConflict, ErrMessageGoodRemoved, apiv1.ErrorDescription{"details": "Good is removed"}))
+	default:
+		views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": details}))
+	}
+}
+
+func toTagNames(tags []string) []domain.TagName {
+	tagNames := make([]domain.TagName, len(tags))
+	for i := range tags {
+		tagNames[i] = domain.TagName(tags[i])
+	}
+	return tagNames
+}
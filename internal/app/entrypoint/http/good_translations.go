@@ -0,0 +1,230 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/render"
+	"github.com/vaberof/hezzl-backend/internal/app/entrypoint/http/views"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"github.com/vaberof/hezzl-backend/pkg/http/protocols/apiv1"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxAcceptedLocales bounds the locales taken from Accept-Language, since each may cost a cache lookup.
+const maxAcceptedLocales = 5
+
+type setTranslationRequestBody struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func (s *setTranslationRequestBody) Bind(req *http.Request) error {
+	return nil
+}
+
+type translationResponseBody struct {
+	GoodId      int64     `json:"goodId"`
+	Locale      string    `json:"locale"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+	UpdatedBy   string    `json:"updatedBy"`
+}
+
+type listTranslationsResponseBody struct {
+	Translations []*translationResponseBody `json:"translations"`
+}
+
+func (h *Handler) ListTranslationsHandler() http.HandlerFunc {
+	return func(rw http.ResponseWriter, request *http.Request) {
+		goodId, projectId, ok := goodIdsFromRequest(rw, request)
+		if !ok {
+			return
+		}
+
+		translations, err := h.goodService.ListTranslations(request.Context(), goodId, projectId)
+		if err != nil {
+			renderTranslationError(rw, request, err, "Failed to list translations")
+
+			return
+		}
+
+		responseBody := &listTranslationsResponseBody{Translations: make([]*translationResponseBody, len(translations))}
+		for i := range translations {
+			responseBody.Translations[i] = buildTranslationResponseBody(translations[i])
+		}
+
+		payload, _ := json.Marshal(responseBody)
+
+		views.RenderJSON(rw, request, http.StatusOK, apiv1.Success(payload))
+	}
+}
+
+func (h *Handler) SetTranslationHandler() http.HandlerFunc {
+	return func(rw http.ResponseWriter, request *http.Request) {
+		goodId, projectId, ok := goodIdsFromRequest(rw, request)
+		if !ok {
+			return
+		}
+
+		locale, ok := localeFromRequest(rw, request)
+		if !ok {
+			return
+		}
+
+		setTranslationReqBody := &setTranslationRequestBody{}
+		if err := render.Bind(request, setTranslationReqBody); err != nil {
+			renderBindError(rw, request, err)
+
+			return
+		}
+
+		actor, _ := ActorFromContext(request.Context())
+
+		translation, err := h.goodService.SetTranslation(request.Context(), goodId, projectId, locale, domain.GoodName(setTranslationReqBody.Name), domain.GoodDescription(setTranslationReqBody.Description), actor)
+		if err != nil {
+			renderTranslationError(rw, request, err, "Failed to set a translation")
+
+			return
+		}
+
+		payload, _ := json.Marshal(buildTranslationResponseBody(translation))
+
+		views.RenderJSON(rw, request, http.StatusOK, apiv1.Success(payload))
+	}
+}
+
+func (h *Handler) RemoveTranslationHandler() http.HandlerFunc {
+	return func(rw http.ResponseWriter, request *http.Request) {
+		goodId, projectId, ok := goodIdsFromRequest(rw, request)
+		if !ok {
+			return
+		}
+
+		locale, ok := localeFromRequest(rw, request)
+		if !ok {
+			return
+		}
+
+		actor, _ := ActorFromContext(request.Context())
+
+		translation, err := h.goodService.RemoveTranslation(request.Context(), goodId, projectId, locale, actor)
+		if err != nil {
+			renderTranslationError(rw, request, err, "Failed to remove a translation")
+
+			return
+		}
+
+		payload, _ := json.Marshal(buildTranslationResponseBody(translation))
+
+		views.RenderJSON(rw, request, http.StatusOK, apiv1.Success(payload))
+	}
+}
+
+func localeFromRequest(rw http.ResponseWriter, request *http.Request) (domain.Locale, bool) {
+	locale := request.URL.Query().Get("locale")
+	if locale == "" {
+		views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "Missing required query parameter 'locale'"}))
+
+		return "", false
+	}
+
+	return domain.Locale(locale), true
+}
+
+// localesFromRequest returns the locales of the Accept-Language header in order of preference.
+// Wildcards and locales with a zero quality are left out; ill-formed entries are ignored rather than refused.
+func localesFromRequest(request *http.Request) []domain.Locale {
+	type acceptedLocale struct {
+		locale  domain.Locale
+		quality float64
+	}
+
+	var acceptedLocales []acceptedLocale
+
+	for _, entry := range strings.Split(request.Header.Get("Accept-Language"), ",") {
+		tag, params, _ := strings.Cut(entry, ";")
+
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		quality := 1.0
+		if qualityStr, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsedQuality, err := strconv.ParseFloat(qualityStr, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsedQuality
+		}
+		if quality <= 0 {
+			continue
+		}
+
+		acceptedLocales = append(acceptedLocales, acceptedLocale{locale: domain.Locale(tag), quality: quality})
+	}
+
+	slices.SortStableFunc(acceptedLocales, func(a, b acceptedLocale) int {
+		switch {
+		case a.quality > b.quality:
+			return -1
+		case a.quality < b.quality:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	if len(acceptedLocales) > maxAcceptedLocales {
+		acceptedLocales = acceptedLocales[:maxAcceptedLocales]
+	}
+
+	locales := make([]domain.Locale, len(acceptedLocales))
+	for i := range acceptedLocales {
+		locales[i] = acceptedLocales[i].locale
+	}
+
+	return locales
+}
+
+// setContentLanguage marks the response as depending on Accept-Language and names the locale it is in.
+func setContentLanguage(rw http.ResponseWriter, locale domain.Locale) {
+	rw.Header().Add("Vary", "Accept-Language")
+	if locale != "" {
+		rw.Header().Set("Content-Language", locale.String())
+	}
+}
+
+func buildTranslationResponseBody(translation *good.Translation) *translationResponseBody {
+	return &translationResponseBody{
+		GoodId:      translation.GoodId.Int64(),
+		Locale:      translation.Locale.String(),
+		Name:        translation.Name.String(),
+		Description: translation.Description.String(),
+		UpdatedAt:   translation.UpdatedAt,
+		UpdatedBy:   translation.UpdatedBy.String(),
+	}
+}
+
+func renderTranslationError(rw http.ResponseWriter, request *http.Request, err error, details string) {
+	switch {
+	case errors.Is(err, good.ErrInvalidLocale):
+		views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidLocale, apiv1.ErrorDescription{"details": err.Error()}))
+	case errors.Is(err, good.ErrInvalidTranslation):
+		views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidTranslation, apiv1.ErrorDescription{"details": err.Error()}))
+	case errors.Is(err, good.ErrGoodNotFound):
+		views.RenderJSON(rw, request, http.StatusNotFound, apiv1.Error(CodeNotFound, ErrMessageGoodNotFound, apiv1.ErrorDescription{"details": "Good is not found"}))
+	case errors.Is(err, good.ErrTranslationNotFound):
+		views.RenderJSON(rw, request, http.StatusNotFound, apiv1.Error(CodeNotFound, ErrMessageTranslationNotFound, apiv1.ErrorDescription{"details": "Translation is not found"}))
+	case errors.Is(err, good.ErrGoodRemoved):
+		views.RenderJSON(rw, request, http.StatusConflict, apiv1.Error(CodeConflict, ErrMessageGoodRemoved, apiv1.ErrorDescription{"details": "Good is removed"}))
+	default:
+		views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": details}))
+	}
+}
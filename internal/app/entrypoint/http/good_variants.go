@@ -0,0 +1,98 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/render"
+	"github.com/vaberof/hezzl-backend/internal/app/entrypoint/http/views"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"github.com/vaberof/hezzl-backend/pkg/http/protocols/apiv1"
+	"net/http"
+)
+
+const expandVariants = "variants"
+
+// CreateVariantHandler creates a variant of the good given by id. The variant belongs to the project of its parent
+// and is ordered among the other variants of the parent.
+func (h *Handler) CreateVariantHandler() http.HandlerFunc {
+	return func(rw http.ResponseWriter, request *http.Request) {
+		parentId, projectId, ok := goodIdsFromRequest(rw, request)
+		if !ok {
+			return
+		}
+
+		createGoodReqBody := &createGoodRequestBody{}
+		if err := render.Bind(request, createGoodReqBody); err != nil {
+			renderBindError(rw, request, err)
+
+			return
+		}
+
+		actor, _ := ActorFromContext(request.Context())
+
+		domainGood, err := h.goodService.CreateVariant(request.Context(), parentId, projectId, domain.GoodName(createGoodReqBody.Name), createGoodReqBody.Attributes, actor)
+		if err != nil {
+			switch {
+			case errors.Is(err, good.ErrInvalidAttributes):
+				renderInvalidAttributes(rw, request, err)
+			case errors.Is(err, good.ErrNestedVariant):
+				views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageNestedVariant, apiv1.ErrorDescription{"details": "Variants cannot have variants"}))
+			case errors.Is(err, good.ErrGoodNotFound):
+				views.RenderJSON(rw, request, http.StatusNotFound, apiv1.Error(CodeNotFound, ErrMessageGoodNotFound, apiv1.ErrorDescription{"details": "Good is not found"}))
+			case errors.Is(err, good.ErrGoodRemoved):
+				views.RenderJSON(rw, request, http.StatusConflict, apiv1.Error(CodeConflict, ErrMessageGoodRemoved, apiv1.ErrorDescription{"details": "Good is removed"}))
+			default:
+				views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to create a variant"}))
+			}
+
+			return
+		}
+
+		payload, _ := json.Marshal(&createGoodResponseBody{
+			Id:          domainGood.Id.Int64(),
+			ProjectId:   domainGood.ProjectId.Int64(),
+			ParentId:    parentIdPayload(domainGood),
+			Name:        domainGood.Name.String(),
+			Description: domainGood.Description.String(),
+			Priority:    domainGood.Priority.Int(),
+			Removed:     domainGood.Removed.Bool(),
+			CreatedAt:   domainGood.CreatedAt.Time(),
+			CreatedBy:   domainGood.CreatedBy.String(),
+			UpdatedBy:   domainGood.UpdatedBy.String(),
+			Version:     domainGood.Version.Int64(),
+			ExternalKey: domainGood.ExternalKey.String(),
+			Attributes:  domainGood.Attributes,
+			Price:       buildPricePayload(domainGood.Price),
+			Stock:       domainGood.Stock,
+			Reserved:    domainGood.Reserved,
+		})
+
+		rw.Header().Set(etagHeader, goodETag(domainGood.Version))
+
+		views.RenderJSON(rw, request, http.StatusOK, apiv1.Success(payload))
+	}
+}
+
+// expandVariantsFromRequest reports whether the variants of the goods are requested with 'expand=variants'.
+func expandVariantsFromRequest(rw http.ResponseWriter, request *http.Request) (expand bool, ok bool) {
+	expandStr := request.URL.Query().Get("expand")
+	switch expandStr {
+	case "":
+		return false, true
+	case expandVariants:
+		return true, true
+	default:
+		views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "'expand' must be '" + expandVariants + "'"}))
+
+		return false, false
+	}
+}
+
+func parentIdPayload(domainGood *good.Good) *int64 {
+	if domainGood.ParentId == nil {
+		return nil
+	}
+	parentId := domainGood.ParentId.Int64()
+	return &parentId
+}
@@ -1,27 +1,151 @@
 package http
 
-import "github.com/go-chi/chi/v5"
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/vaberof/hezzl-backend/internal/app/goodimport"
+	"github.com/vaberof/hezzl-backend/pkg/ratelimit"
+)
 
 type Handler struct {
-	goodService GoodService
+	goodService     GoodService
+	healthChecker   HealthChecker
+	rateLimiter     RateLimiter
+	rateLimitConfig *ratelimit.Config
+
+	idempotencyStorage IdempotencyStorage
+	idempotencyConfig  *IdempotencyConfig
+
+	authConfig  *AuthConfig
+	batchConfig *BatchConfig
+
+	goodImporter GoodImporter
+	importConfig *goodimport.Config
+
+	exportConfig *ExportConfig
+
+	attachmentConfig *AttachmentConfig
+
+	maxBodyBytes int64
 }
 
-func NewHandler(goodService GoodService) *Handler {
-	return &Handler{goodService: goodService}
+func NewHandler(goodService GoodService, healthChecker HealthChecker, rateLimiter RateLimiter, rateLimitConfig *ratelimit.Config, idempotencyStorage IdempotencyStorage, idempotencyConfig *IdempotencyConfig, authConfig *AuthConfig, batchConfig *BatchConfig, goodImporter GoodImporter, importConfig *goodimport.Config, exportConfig *ExportConfig, attachmentConfig *AttachmentConfig, maxBodyBytes int64) *Handler {
+	return &Handler{
+		goodService:     goodService,
+		healthChecker:   healthChecker,
+		rateLimiter:     rateLimiter,
+		rateLimitConfig: rateLimitConfig,
+
+		idempotencyStorage: idempotencyStorage,
+		idempotencyConfig:  idempotencyConfig,
+
+		authConfig:  authConfig,
+		batchConfig: batchConfig,
+
+		goodImporter: goodImporter,
+		importConfig: importConfig,
+
+		exportConfig: exportConfig,
+
+		attachmentConfig: attachmentConfig,
+
+		maxBodyBytes: maxBodyBytes,
+	}
 }
 
 func (h *Handler) InitRoutes(router chi.Router) chi.Router {
+	router.Get("/healthz", h.LivenessHandler())
+	router.Get("/readyz", h.ReadinessHandler())
+
 	router.Route("/api/v1", func(apiV1 chi.Router) {
+		apiV1.Use(h.Authenticate)
+
+		// Imports and attachments are limited by their own upload size, all other requests by the server body limit.
+		apiV1.Route("/projects/{id}/goods/import", func(goodsImport chi.Router) {
+			goodsImport.Use(h.RequireActor)
+
+			goodsImport.With(h.RateLimit(routeGoodsImport), BodyLimitMiddleware(h.importConfig.MaxUploadBytes)).Post("/", h.ImportGoodsHandler())
+			goodsImport.With(h.RateLimit(routeGoodsImportJob)).Get("/{jobId}", h.GetImportJobHandler())
+		})
+
+		apiV1.With(h.RequireActor, h.RateLimit(routeGoodAttachmentsUpload), BodyLimitMiddleware(h.attachmentMaxBytes())).Post("/good/attachments/upload", h.UploadAttachmentHandler())
+
+		apiV1 = apiV1.With(BodyLimitMiddleware(h.maxBodyBytes))
 
 		apiV1.Route("/good", func(good chi.Router) {
-			good.Post("/create", h.CreateGoodHandler())
-			good.Patch("/update", h.UpdateGoodHandler())
-			good.Patch("/reprioritize", h.UpdateGoodPriorityHandler())
-			good.Delete("/remove", h.DeleteGoodHandler())
+			good.With(h.RateLimit(routeGoodGet)).Get("/get", h.GetGoodHandler())
+			good.With(h.RateLimit(routeGoodTagsGet)).Get("/tags", h.GetGoodTagsHandler())
+			good.With(h.RateLimit(routeGoodAttachmentsList)).Get("/attachments", h.ListAttachmentsHandler())
+			good.With(h.RateLimit(routeGoodAttachmentsGet)).Get("/attachments/download", h.DownloadAttachmentHandler())
+			good.With(h.RateLimit(routeGoodStockMovements)).Get("/stock/movements", h.ListStockMovementsHandler())
+			good.With(h.RateLimit(routeGoodTranslationsList)).Get("/translations", h.ListTranslationsHandler())
+
+			good.Group(func(good chi.Router) {
+				good.Use(h.RequireActor)
+				good.Use(h.Idempotent)
+
+				good.With(h.RateLimit(routeGoodCreate)).Post("/create", h.CreateGoodHandler())
+				good.With(h.RateLimit(routeGoodVariantsCreate)).Post("/variants/create", h.CreateVariantHandler())
+				good.With(h.RateLimit(routeGoodUpdate)).Patch("/update", h.UpdateGoodHandler())
+				good.With(h.RateLimit(routeGoodReprioritize)).Patch("/reprioritize", h.UpdateGoodPriorityHandler())
+				good.With(h.RateLimit(routeGoodRemove)).Delete("/remove", h.DeleteGoodHandler())
+				good.With(h.RateLimit(routeGoodRestore)).Patch("/restore", h.RestoreGoodHandler())
+				good.With(h.RateLimit(routeGoodMove)).Patch("/move", h.MoveGoodHandler())
+				good.With(h.RateLimit(routeGoodCopy)).Post("/copy", h.CopyGoodHandler())
+				good.With(h.RateLimit(routeGoodTagsAdd)).Post("/tags/add", h.AddGoodTagsHandler())
+				good.With(h.RateLimit(routeGoodTagsRemove)).Delete("/tags/remove", h.RemoveGoodTagsHandler())
+				good.With(h.RateLimit(routeGoodAttachmentsRemove)).Delete("/attachments/remove", h.RemoveAttachmentHandler())
+				good.With(h.RateLimit(routeGoodPriceSet)).Patch("/price", h.SetGoodPriceHandler())
+				good.With(h.RateLimit(routeGoodStockReserve)).Post("/stock/reserve", h.ReserveStockHandler())
+				good.With(h.RateLimit(routeGoodStockRelease)).Post("/stock/release", h.ReleaseStockHandler())
+				good.With(h.RateLimit(routeGoodStockAdjust)).Post("/stock/adjust", h.AdjustStockHandler())
+				good.With(h.RateLimit(routeGoodTranslationsSet)).Put("/translations/set", h.SetTranslationHandler())
+				good.With(h.RateLimit(routeGoodTranslationsRemove)).Delete("/translations/remove", h.RemoveTranslationHandler())
+			})
+
+			// Admins are checked before idempotency, so a rejected request does not store its response under the key.
+			good.Group(func(good chi.Router) {
+				good.Use(h.RequireActor)
+				good.Use(h.RequireAdmin)
+				good.Use(h.Idempotent)
+
+				good.With(h.RateLimit(routeGoodPurge)).Delete("/purge", h.PurgeGoodHandler())
+			})
+		})
+
+		apiV1.Route("/projects/{id}/attributes/schema", func(attributeSchema chi.Router) {
+			attributeSchema.With(h.RateLimit(routeAttributeSchemaGet)).Get("/", h.GetAttributeSchemaHandler())
+
+			attributeSchema.Group(func(attributeSchema chi.Router) {
+				attributeSchema.Use(h.RequireActor)
+				attributeSchema.Use(h.RequireAdmin)
+
+				attributeSchema.With(h.RateLimit(routeAttributeSchemaSet)).Put("/", h.SetAttributeSchemaHandler())
+				attributeSchema.With(h.RateLimit(routeAttributeSchemaDelete)).Delete("/", h.DeleteAttributeSchemaHandler())
+			})
+		})
+
+		apiV1.Route("/projects/{id}/locale", func(locale chi.Router) {
+			locale.With(h.RateLimit(routeDefaultLocaleGet)).Get("/", h.GetDefaultLocaleHandler())
+			locale.With(h.RequireActor, h.RequireAdmin, h.RateLimit(routeDefaultLocaleSet)).Put("/", h.SetDefaultLocaleHandler())
+		})
+
+		apiV1.Route("/tags", func(tags chi.Router) {
+			tags.With(h.RateLimit(routeTagsList)).Get("/list", h.ListTagsHandler())
 		})
 
 		apiV1.Route("/goods", func(goods chi.Router) {
-			goods.Get("/list", h.ListGoodsHandler())
+			goods.With(h.RateLimit(routeGoodsList)).Get("/list", h.ListGoodsHandler())
+			goods.With(h.RateLimit(routeGoodsExport)).Get("/export", h.ExportGoodsHandler())
+			goods.With(h.RateLimit(routeGoodsSearch)).Get("/search", h.SearchGoodsHandler())
+
+			goods.Route("/batch", func(batch chi.Router) {
+				batch.Use(h.RequireActor)
+				batch.Use(h.Idempotent)
+
+				batch.With(h.RateLimit(routeGoodsBatchCreate)).Post("/create", h.BatchCreateGoodsHandler())
+				batch.With(h.RateLimit(routeGoodsBatchUpdate)).Patch("/update", h.BatchUpdateGoodsHandler())
+				batch.With(h.RateLimit(routeGoodsBatchRemove)).Delete("/remove", h.BatchRemoveGoodsHandler())
+			})
 		})
 	})
 
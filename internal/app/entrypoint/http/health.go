@@ -0,0 +1,69 @@
+package http
+
+import (
+	"encoding/json"
+	"github.com/vaberof/hezzl-backend/internal/app/entrypoint/http/views"
+	"github.com/vaberof/hezzl-backend/pkg/health"
+	"github.com/vaberof/hezzl-backend/pkg/http/protocols/apiv1"
+	"net/http"
+)
+
+type livenessResponseBody struct {
+	Status string `json:"status"`
+}
+
+type readinessResponseBody struct {
+	Status       string                        `json:"status"`
+	Dependencies map[string]*dependencyPayload `json:"dependencies"`
+}
+
+type dependencyPayload struct {
+	Status    string  `json:"status"`
+	Critical  bool    `json:"critical"`
+	LatencyMs float64 `json:"latencyMs"`
+	Error     string  `json:"error,omitempty"`
+}
+
+func (h *Handler) LivenessHandler() http.HandlerFunc {
+	return func(rw http.ResponseWriter, request *http.Request) {
+		payload, _ := json.Marshal(&livenessResponseBody{
+			Status: string(health.StatusUp),
+		})
+
+		views.RenderJSON(rw, request, http.StatusOK, apiv1.Success(payload))
+	}
+}
+
+// ReadinessHandler reports 503 only when a critical dependency is down,
+// a degraded service is still ready to accept traffic.
+func (h *Handler) ReadinessHandler() http.HandlerFunc {
+	return func(rw http.ResponseWriter, request *http.Request) {
+		report := h.healthChecker.Check(request.Context())
+
+		payload, _ := json.Marshal(&readinessResponseBody{
+			Status:       string(report.Status),
+			Dependencies: h.buildDependencyPayloads(report),
+		})
+
+		if report.Status == health.StatusDown {
+			views.RenderJSON(rw, request, http.StatusServiceUnavailable, apiv1.Success(payload))
+
+			return
+		}
+
+		views.RenderJSON(rw, request, http.StatusOK, apiv1.Success(payload))
+	}
+}
+
+func (h *Handler) buildDependencyPayloads(report *health.Report) map[string]*dependencyPayload {
+	dependencyPayloads := make(map[string]*dependencyPayload, len(report.Dependencies))
+	for name, dependencyReport := range report.Dependencies {
+		dependencyPayloads[name] = &dependencyPayload{
+			Status:    string(dependencyReport.Status),
+			Critical:  dependencyReport.Critical,
+			LatencyMs: float64(dependencyReport.Latency.Microseconds()) / 1000,
+			Error:     dependencyReport.Error,
+		}
+	}
+	return dependencyPayloads
+}
@@ -0,0 +1,10 @@
+package http
+
+import (
+	"context"
+	"github.com/vaberof/hezzl-backend/pkg/health"
+)
+
+type HealthChecker interface {
+	Check(ctx context.Context) *health.Report
+}
@@ -0,0 +1,215 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/vaberof/hezzl-backend/internal/app/entrypoint/http/views"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage"
+	"github.com/vaberof/hezzl-backend/pkg/http/protocols/apiv1"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const (
+	idempotencyKeyHeader      = "Idempotency-Key"
+	idempotentReplayedHeader  = "Idempotent-Replayed"
+	idempotencyKeyPrefix      = "idempotency_"
+	idempotencyKeyMaxLength   = 255
+	defaultIdempotencyTTL     = 24 * time.Hour
+	defaultIdempotencyLockTTL = 30 * time.Second
+)
+
+const (
+	idempotencyStateProcessing = "processing"
+	idempotencyStateCompleted  = "completed"
+)
+
+type IdempotencyConfig struct {
+	// TTL is how long completed responses are kept for replay.
+	TTL time.Duration `yaml:"ttl"`
+	// LockTTL bounds how long a request in progress blocks retries with the same key.
+	LockTTL time.Duration `yaml:"lock_ttl"`
+}
+
+type idempotencyRecord struct {
+	State       string `json:"state"`
+	Fingerprint string `json:"fingerprint"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+	ETag        string `json:"etag,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// Idempotent replays the stored response for requests repeating an Idempotency-Key of the same principal.
+// Reusing a key with a different request is rejected with 409, as are retries racing the first request.
+// Server errors are not stored, so that such requests can be retried with the same key.
+func (h *Handler) Idempotent(next http.Handler) http.Handler {
+	ttl := h.idempotencyConfig.TTL
+	if ttl <= 0 {
+		ttl = defaultIdempotencyTTL
+	}
+	lockTTL := h.idempotencyConfig.LockTTL
+	if lockTTL <= 0 {
+		lockTTL = defaultIdempotencyLockTTL
+	}
+
+	return http.HandlerFunc(func(rw http.ResponseWriter, request *http.Request) {
+		idempotencyKey := request.Header.Get(idempotencyKeyHeader)
+		if idempotencyKey == "" {
+			next.ServeHTTP(rw, request)
+
+			return
+		}
+		if len(idempotencyKey) > idempotencyKeyMaxLength {
+			views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "'Idempotency-Key' header is too long"}))
+
+			return
+		}
+
+		body, err := io.ReadAll(request.Body)
+		if err != nil {
+			renderBindError(rw, request, err)
+
+			return
+		}
+		request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := request.Context()
+		storageKey := h.idempotencyStorageKey(request, idempotencyKey)
+		fingerprint := requestFingerprint(request, body)
+
+		lockRecord, _ := json.Marshal(&idempotencyRecord{State: idempotencyStateProcessing, Fingerprint: fingerprint})
+
+		isLocked, err := h.idempotencyStorage.SetIfNotExists(ctx, storageKey, string(lockRecord), lockTTL)
+		if err != nil {
+			views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to check idempotency key"}))
+
+			return
+		}
+
+		if !isLocked {
+			h.replayIdempotentResponse(rw, request, storageKey, fingerprint)
+
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: rw, status: http.StatusOK}
+
+		next.ServeHTTP(recorder, request)
+
+		// The response has been sent already, the record must outlive the request context.
+		storeCtx := context.WithoutCancel(ctx)
+
+		if recorder.status >= http.StatusInternalServerError {
+			if err = h.idempotencyStorage.Delete(storeCtx, storageKey); err != nil && !errors.Is(err, storage.ErrRedisKeyNotFound) {
+				slog.ErrorContext(ctx, "failed to release idempotency key", "error", err)
+			}
+
+			return
+		}
+
+		completedRecord, _ := json.Marshal(&idempotencyRecord{
+			State:       idempotencyStateCompleted,
+			Fingerprint: fingerprint,
+			Status:      recorder.status,
+			ContentType: recorder.Header().Get("Content-Type"),
+			ETag:        recorder.Header().Get(etagHeader),
+			Body:        recorder.body.Bytes(),
+		})
+
+		if err = h.idempotencyStorage.Set(storeCtx, storageKey, string(completedRecord), ttl); err != nil {
+			slog.ErrorContext(ctx, "failed to store idempotent response", "error", err)
+		}
+	})
+}
+
+func (h *Handler) replayIdempotentResponse(rw http.ResponseWriter, request *http.Request, storageKey, fingerprint string) {
+	storedRecord, err := h.idempotencyStorage.Get(request.Context(), storageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrRedisKeyNotFound) {
+			// The first request failed and released the key in the meantime.
+			views.RenderJSON(rw, request, http.StatusConflict, apiv1.Error(CodeConflict, ErrMessageRequestInProgress, apiv1.ErrorDescription{"details": "Request with this idempotency key has just failed, retry it"}))
+
+			return
+		}
+		views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to check idempotency key"}))
+
+		return
+	}
+
+	var record idempotencyRecord
+	if err = json.Unmarshal([]byte(storedRecord), &record); err != nil {
+		views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to read stored idempotent response"}))
+
+		return
+	}
+
+	if record.Fingerprint != fingerprint {
+		views.RenderJSON(rw, request, http.StatusConflict, apiv1.Error(CodeConflict, ErrMessageIdempotencyKeyReused, apiv1.ErrorDescription{"details": "Idempotency key has already been used for a different request"}))
+
+		return
+	}
+
+	if record.State != idempotencyStateCompleted {
+		views.RenderJSON(rw, request, http.StatusConflict, apiv1.Error(CodeConflict, ErrMessageRequestInProgress, apiv1.ErrorDescription{"details": "Request with this idempotency key is still being processed"}))
+
+		return
+	}
+
+	if record.ContentType != "" {
+		rw.Header().Set("Content-Type", record.ContentType)
+	}
+	if record.ETag != "" {
+		rw.Header().Set(etagHeader, record.ETag)
+	}
+	rw.Header().Set(idempotentReplayedHeader, "true")
+	rw.WriteHeader(record.Status)
+	rw.Write(record.Body)
+}
+
+func (h *Handler) idempotencyStorageKey(request *http.Request, idempotencyKey string) string {
+	actor, _ := ActorFromContext(request.Context())
+	principal := sha256.Sum256([]byte(actor.String() + "\x00" + request.Header.Get(apiKeyHeader)))
+	return idempotencyKeyPrefix + hex.EncodeToString(principal[:]) + "_" + idempotencyKey
+}
+
+func requestFingerprint(request *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(request.Method))
+	hash.Write([]byte{0})
+	hash.Write([]byte(request.URL.Path))
+	hash.Write([]byte{0})
+	hash.Write([]byte(request.URL.Query().Encode()))
+	hash.Write([]byte{0})
+	hash.Write([]byte(request.Header.Get(ifMatchHeader)))
+	hash.Write([]byte{0})
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
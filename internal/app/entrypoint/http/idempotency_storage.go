@@ -0,0 +1,13 @@
+package http
+
+import (
+	"context"
+	"time"
+)
+
+type IdempotencyStorage interface {
+	SetIfNotExists(ctx context.Context, key, value string, exp time.Duration) (bool, error)
+	Set(ctx context.Context, key, value string, exp time.Duration) error
+	Get(ctx context.Context, key string) (string, error)
+	Delete(ctx context.Context, keys ...string) error
+}
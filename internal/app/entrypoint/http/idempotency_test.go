@@ -0,0 +1,248 @@
+package http
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"github.com/vaberof/hezzl-backend/internal/app/goodimport"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"github.com/vaberof/hezzl-backend/pkg/ratelimit"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeIdempotencyStorage struct {
+	records map[string]string
+}
+
+func newFakeIdempotencyStorage() *fakeIdempotencyStorage {
+	return &fakeIdempotencyStorage{records: make(map[string]string)}
+}
+
+func (s *fakeIdempotencyStorage) SetIfNotExists(ctx context.Context, key, value string, exp time.Duration) (bool, error) {
+	if _, ok := s.records[key]; ok {
+		return false, nil
+	}
+	s.records[key] = value
+	return true, nil
+}
+
+func (s *fakeIdempotencyStorage) Set(ctx context.Context, key, value string, exp time.Duration) error {
+	s.records[key] = value
+	return nil
+}
+
+func (s *fakeIdempotencyStorage) Get(ctx context.Context, key string) (string, error) {
+	value, ok := s.records[key]
+	if !ok {
+		return "", storage.ErrRedisKeyNotFound
+	}
+	return value, nil
+}
+
+func (s *fakeIdempotencyStorage) Delete(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		delete(s.records, key)
+	}
+	return nil
+}
+
+// countingHandler responds with status and counts the requests that reach it.
+type countingHandler struct {
+	status int
+	calls  int
+}
+
+func (c *countingHandler) ServeHTTP(rw http.ResponseWriter, request *http.Request) {
+	c.calls++
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(c.status)
+	rw.Write([]byte(`{"call":` + strconv.Itoa(c.calls) + `}`))
+}
+
+func newIdempotentRequest(idempotencyKey, body string) *http.Request {
+	request := httptest.NewRequest(http.MethodPost, "/api/v1/good/create?projectId=1", strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(idempotencyKeyHeader, idempotencyKey)
+	return request.WithContext(ContextWithActor(request.Context(), "tester"))
+}
+
+func responseMessage(t *testing.T, recorder *httptest.ResponseRecorder) string {
+	t.Helper()
+
+	var responseBody struct {
+		Payload struct {
+			Message string `json:"message"`
+		} `json:"payload"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &responseBody); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return responseBody.Payload.Message
+}
+
+func TestIdempotentReplaysStoredResponse(t *testing.T) {
+	h := &Handler{idempotencyStorage: newFakeIdempotencyStorage(), idempotencyConfig: &IdempotencyConfig{}}
+	next := &countingHandler{status: http.StatusCreated}
+	handler := h.Idempotent(next)
+
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, newIdempotentRequest("key", `{"name":"name"}`))
+
+	second := httptest.NewRecorder()
+	handler.ServeHTTP(second, newIdempotentRequest("key", `{"name":"name"}`))
+
+	if next.calls != 1 {
+		t.Fatalf("handler called %d times, want once", next.calls)
+	}
+	if second.Code != first.Code || second.Body.String() != first.Body.String() {
+		t.Errorf("replayed %d %s, want %d %s", second.Code, second.Body.String(), first.Code, first.Body.String())
+	}
+	if second.Header().Get(idempotentReplayedHeader) != "true" {
+		t.Errorf("replayed response misses the %s header", idempotentReplayedHeader)
+	}
+	if first.Header().Get(idempotentReplayedHeader) != "" {
+		t.Errorf("first response has the %s header", idempotentReplayedHeader)
+	}
+
+	third := httptest.NewRecorder()
+	handler.ServeHTTP(third, newIdempotentRequest("other", `{"name":"name"}`))
+	if next.calls != 2 {
+		t.Errorf("request with another key is not handled")
+	}
+}
+
+func TestIdempotentConflicts(t *testing.T) {
+	t.Run("key reused for a different request", func(t *testing.T) {
+		h := &Handler{idempotencyStorage: newFakeIdempotencyStorage(), idempotencyConfig: &IdempotencyConfig{}}
+		next := &countingHandler{status: http.StatusCreated}
+		handler := h.Idempotent(next)
+
+		handler.ServeHTTP(httptest.NewRecorder(), newIdempotentRequest("key", `{"name":"name"}`))
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, newIdempotentRequest("key", `{"name":"other"}`))
+
+		if recorder.Code != http.StatusConflict {
+			t.Fatalf("got status %d, want %d", recorder.Code, http.StatusConflict)
+		}
+		if message := responseMessage(t, recorder); message != ErrMessageIdempotencyKeyReused {
+			t.Errorf("got message %q, want %q", message, ErrMessageIdempotencyKeyReused)
+		}
+		if next.calls != 1 {
+			t.Errorf("handler called %d times, want once", next.calls)
+		}
+	})
+
+	t.Run("retry racing the first request", func(t *testing.T) {
+		h := &Handler{idempotencyStorage: newFakeIdempotencyStorage(), idempotencyConfig: &IdempotencyConfig{}}
+
+		var retry *httptest.ResponseRecorder
+		var handler http.Handler
+		handler = h.Idempotent(http.HandlerFunc(func(rw http.ResponseWriter, request *http.Request) {
+			retry = httptest.NewRecorder()
+			handler.ServeHTTP(retry, newIdempotentRequest("key", `{"name":"name"}`))
+			rw.WriteHeader(http.StatusCreated)
+		}))
+
+		handler.ServeHTTP(httptest.NewRecorder(), newIdempotentRequest("key", `{"name":"name"}`))
+
+		if retry.Code != http.StatusConflict {
+			t.Fatalf("got status %d, want %d", retry.Code, http.StatusConflict)
+		}
+		if message := responseMessage(t, retry); message != ErrMessageRequestInProgress {
+			t.Errorf("got message %q, want %q", message, ErrMessageRequestInProgress)
+		}
+	})
+}
+
+func TestIdempotentDoesNotStoreServerErrors(t *testing.T) {
+	idempotencyStorage := newFakeIdempotencyStorage()
+	h := &Handler{idempotencyStorage: idempotencyStorage, idempotencyConfig: &IdempotencyConfig{}}
+	next := &countingHandler{status: http.StatusInternalServerError}
+	handler := h.Idempotent(next)
+
+	handler.ServeHTTP(httptest.NewRecorder(), newIdempotentRequest("key", `{"name":"name"}`))
+	if len(idempotencyStorage.records) != 0 {
+		t.Fatalf("got %d stored records after a server error, want none", len(idempotencyStorage.records))
+	}
+
+	next.status = http.StatusCreated
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, newIdempotentRequest("key", `{"name":"name"}`))
+
+	if recorder.Code != http.StatusCreated || next.calls != 2 {
+		t.Errorf("retry got status %d after %d calls, want %d after 2", recorder.Code, next.calls, http.StatusCreated)
+	}
+}
+
+// fakePurgeService counts the goods it purges.
+type fakePurgeService struct {
+	GoodService
+
+	purged int
+}
+
+func (s *fakePurgeService) Purge(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, actor domain.Actor, expectedVersion *domain.GoodVersion) (*good.Good, error) {
+	s.purged++
+	return &good.Good{Id: id, ProjectId: projectId}, nil
+}
+
+func TestPurgeRejectsNonAdminsBeforeStoringIdempotentResponse(t *testing.T) {
+	keySha256 := func(key string) string {
+		sum := sha256.Sum256([]byte(key))
+		return hex.EncodeToString(sum[:])
+	}
+
+	goodService := &fakePurgeService{}
+	idempotencyStorage := newFakeIdempotencyStorage()
+	h := &Handler{
+		goodService:        goodService,
+		rateLimitConfig:    &ratelimit.Config{},
+		idempotencyStorage: idempotencyStorage,
+		idempotencyConfig:  &IdempotencyConfig{},
+		authConfig: &AuthConfig{ApiKeys: []ApiKeyConfig{
+			{Principal: "client", KeySha256: keySha256("client-key")},
+			{Principal: "admin", KeySha256: keySha256("admin-key"), Admin: true},
+		}},
+		importConfig:     &goodimport.Config{},
+		attachmentConfig: &AttachmentConfig{},
+		maxBodyBytes:     1 << 20,
+	}
+	router := h.InitRoutes(chi.NewRouter())
+
+	purge := func(apiKey string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodDelete, "/api/v1/good/purge?id=1&projectId=1", nil)
+		request.Header.Set(apiKeyHeader, apiKey)
+		request.Header.Set(idempotencyKeyHeader, "key")
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	if recorder := purge("client-key"); recorder.Code != http.StatusForbidden {
+		t.Fatalf("non-admin got status %d, want %d", recorder.Code, http.StatusForbidden)
+	}
+	if len(idempotencyStorage.records) != 0 {
+		t.Fatalf("got %d stored records after a forbidden purge, want none", len(idempotencyStorage.records))
+	}
+
+	if recorder := purge("admin-key"); recorder.Code != http.StatusOK {
+		t.Fatalf("admin got status %d, want %d: %s", recorder.Code, http.StatusOK, recorder.Body.String())
+	}
+	if recorder := purge("admin-key"); recorder.Header().Get(idempotentReplayedHeader) != "true" {
+		t.Errorf("repeated admin purge is not replayed")
+	}
+	if goodService.purged != 1 {
+		t.Errorf("purged %d times, want once", goodService.purged)
+	}
+}
@@ -0,0 +1,150 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/vaberof/hezzl-backend/internal/app/entrypoint/http/views"
+	"github.com/vaberof/hezzl-backend/internal/app/goodimport"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"github.com/vaberof/hezzl-backend/pkg/http/protocols/apiv1"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultImportUploadTimeout = 5 * time.Minute
+
+type importGoodsResponseBody struct {
+	Report *good.ImportReport `json:"report"`
+}
+
+type importJobResponseBody struct {
+	Job *goodimport.Job `json:"job"`
+}
+
+// ImportGoodsHandler imports an uploaded CSV or NDJSON file into the project.
+// Uploads up to the async threshold are imported right away and answered with the report,
+// larger ones and uploads of unknown size are answered with 202 and a job to poll.
+func (h *Handler) ImportGoodsHandler() http.HandlerFunc {
+	return func(rw http.ResponseWriter, request *http.Request) {
+		projectId, err := strconv.ParseInt(chi.URLParam(request, "id"), 10, 64)
+		if err != nil {
+			views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to convert id to int"}))
+
+			return
+		}
+
+		format, ok := importFormatFromRequest(request)
+		if !ok {
+			views.RenderJSON(rw, request, http.StatusUnsupportedMediaType, apiv1.Error(CodeUnsupportedMediaType, ErrMessageUnsupportedMediaType, apiv1.ErrorDescription{"details": "Content-Type must be 'text/csv' or 'application/x-ndjson'"}))
+
+			return
+		}
+
+		actor, _ := ActorFromContext(request.Context())
+
+		// Spooling a large upload or importing a small one synchronously takes longer than a regular API call.
+		ctx, cancel := extendRequest(rw, request, h.importUploadTimeout())
+		defer cancel()
+
+		if request.ContentLength < 0 || request.ContentLength > h.importConfig.AsyncThresholdBytes {
+			job, err := h.goodImporter.Submit(ctx, domain.ProjectId(projectId), format, request.Body, actor)
+			if err != nil {
+				renderImportError(rw, request, err)
+
+				return
+			}
+
+			payload, _ := json.Marshal(&importJobResponseBody{Job: job})
+
+			rw.Header().Set("Location", strings.TrimSuffix(request.URL.Path, "/")+"/"+job.Id)
+			views.RenderJSON(rw, request, http.StatusAccepted, apiv1.Success(payload))
+
+			return
+		}
+
+		report, err := h.goodImporter.Import(ctx, domain.ProjectId(projectId), format, request.Body, actor)
+		if err != nil {
+			renderImportError(rw, request, err)
+
+			return
+		}
+
+		payload, _ := json.Marshal(&importGoodsResponseBody{Report: report})
+
+		views.RenderJSON(rw, request, http.StatusOK, apiv1.Success(payload))
+	}
+}
+
+func (h *Handler) GetImportJobHandler() http.HandlerFunc {
+	return func(rw http.ResponseWriter, request *http.Request) {
+		projectId, err := strconv.ParseInt(chi.URLParam(request, "id"), 10, 64)
+		if err != nil {
+			views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to convert id to int"}))
+
+			return
+		}
+
+		job, err := h.goodImporter.Job(request.Context(), chi.URLParam(request, "jobId"))
+		if err != nil && !errors.Is(err, goodimport.ErrJobNotFound) {
+			views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to get an import job"}))
+
+			return
+		}
+
+		// A job of another project is reported as missing, so job ids cannot be probed across projects.
+		if job == nil || job.ProjectId != domain.ProjectId(projectId) {
+			views.RenderJSON(rw, request, http.StatusNotFound, apiv1.Error(CodeNotFound, ErrMessageImportJobNotFound, apiv1.ErrorDescription{"details": "Import job is not found"}))
+
+			return
+		}
+
+		payload, _ := json.Marshal(&importJobResponseBody{Job: job})
+
+		views.RenderJSON(rw, request, http.StatusOK, apiv1.Success(payload))
+	}
+}
+
+func (h *Handler) importUploadTimeout() time.Duration {
+	if h.importConfig.UploadTimeout <= 0 {
+		return defaultImportUploadTimeout
+	}
+	return h.importConfig.UploadTimeout
+}
+
+func importFormatFromRequest(request *http.Request) (goodimport.Format, bool) {
+	mediaType, _, err := mime.ParseMediaType(request.Header.Get("Content-Type"))
+	if err != nil {
+		return "", false
+	}
+
+	switch mediaType {
+	case "text/csv":
+		return goodimport.FormatCSV, true
+	case "application/x-ndjson", "application/ndjson":
+		return goodimport.FormatNDJSON, true
+	default:
+		return "", false
+	}
+}
+
+func renderImportError(rw http.ResponseWriter, request *http.Request, err error) {
+	var maxBytesErr *http.MaxBytesError
+
+	switch {
+	case errors.As(err, &maxBytesErr):
+		renderRequestTooLarge(rw, request, maxBytesErr.Limit)
+	case errors.Is(err, goodimport.ErrInvalidFile):
+		views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidImportFile, apiv1.ErrorDescription{"details": err.Error()}))
+	case errors.Is(err, good.ErrInvalidAttributes):
+		renderInvalidAttributes(rw, request, err)
+	case errors.Is(err, good.ErrProjectNotFound):
+		views.RenderJSON(rw, request, http.StatusNotFound, apiv1.Error(CodeNotFound, ErrMessageProjectNotFound, apiv1.ErrorDescription{"details": "Project is not found"}))
+	default:
+		views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to import goods"}))
+	}
+}
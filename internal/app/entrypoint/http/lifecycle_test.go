@@ -0,0 +1,115 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakeGoodService fails every lifecycle transition with err.
+type fakeGoodService struct {
+	GoodService
+
+	err error
+}
+
+func (s *fakeGoodService) Update(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, name domain.GoodName, description *domain.GoodDescription, attributes domain.GoodAttributes, actor domain.Actor, expectedVersion *domain.GoodVersion) (*good.Good, error) {
+	return nil, s.err
+}
+
+func (s *fakeGoodService) Delete(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, actor domain.Actor, expectedVersion *domain.GoodVersion) (*good.Good, error) {
+	return nil, s.err
+}
+
+func (s *fakeGoodService) Restore(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, actor domain.Actor, expectedVersion *domain.GoodVersion) (*good.Good, error) {
+	return nil, s.err
+}
+
+func (s *fakeGoodService) ChangePriority(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, newPriority domain.GoodPriority, actor domain.Actor, expectedVersion *domain.GoodVersion) ([]*good.Good, error) {
+	return nil, s.err
+}
+
+func TestLifecycleErrorStatuses(t *testing.T) {
+	type handlerFunc func(h *Handler) http.HandlerFunc
+
+	var (
+		update       handlerFunc = (*Handler).UpdateGoodHandler
+		reprioritize handlerFunc = (*Handler).UpdateGoodPriorityHandler
+		remove       handlerFunc = (*Handler).DeleteGoodHandler
+		restore      handlerFunc = (*Handler).RestoreGoodHandler
+	)
+
+	tests := []struct {
+		name        string
+		handler     handlerFunc
+		err         error
+		wantStatus  int
+		wantMessage string
+	}{
+		{"update removed", update, good.ErrGoodRemoved, http.StatusConflict, ErrMessageGoodRemoved},
+		{"update version mismatch", update, good.ErrGoodVersionMismatch, http.StatusPreconditionFailed, ErrMessageVersionMismatch},
+		{"reprioritize removed", reprioritize, good.ErrGoodRemoved, http.StatusConflict, ErrMessageGoodRemoved},
+		{"reprioritize version mismatch", reprioritize, good.ErrGoodVersionMismatch, http.StatusPreconditionFailed, ErrMessageVersionMismatch},
+		{"delete removed", remove, good.ErrGoodRemoved, http.StatusConflict, ErrMessageGoodRemoved},
+		{"delete version mismatch", remove, good.ErrGoodVersionMismatch, http.StatusPreconditionFailed, ErrMessageVersionMismatch},
+		{"restore active", restore, good.ErrGoodNotRemoved, http.StatusConflict, ErrMessageGoodNotRemoved},
+		{"restore version mismatch", restore, good.ErrGoodVersionMismatch, http.StatusPreconditionFailed, ErrMessageVersionMismatch},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := &Handler{goodService: &fakeGoodService{err: test.err}}
+
+			request := httptest.NewRequest(http.MethodPatch, "/?id=1&projectId=1", strings.NewReader(`{"name":"name","newPriority":2}`))
+			request.Header.Set("Content-Type", "application/json")
+			recorder := httptest.NewRecorder()
+
+			test.handler(h).ServeHTTP(recorder, request)
+
+			if recorder.Code != test.wantStatus {
+				t.Fatalf("got status %d, want %d: %s", recorder.Code, test.wantStatus, recorder.Body.String())
+			}
+
+			var responseBody struct {
+				Payload struct {
+					Message string `json:"message"`
+				} `json:"payload"`
+			}
+			if err := json.Unmarshal(recorder.Body.Bytes(), &responseBody); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if responseBody.Payload.Message != test.wantMessage {
+				t.Errorf("got message %q, want %q", responseBody.Payload.Message, test.wantMessage)
+			}
+		})
+	}
+}
+
+func TestBuildBatchItemErrorPayload(t *testing.T) {
+	tests := []struct {
+		err         error
+		wantCode    int
+		wantMessage string
+	}{
+		{good.ErrGoodRemoved, CodeConflict, ErrMessageGoodRemoved},
+		{good.ErrGoodVersionMismatch, CodePreconditionFailed, ErrMessageVersionMismatch},
+		{good.ErrGoodNotFound, CodeNotFound, ErrMessageGoodNotFound},
+		{errors.New("unexpected"), CodeInternalError, ErrMessageInternalServerError},
+	}
+
+	for _, test := range tests {
+		t.Run(test.err.Error(), func(t *testing.T) {
+			payload := buildBatchItemErrorPayload(test.err)
+
+			if payload.Code != test.wantCode || payload.Message != test.wantMessage {
+				t.Errorf("got %d %q, want %d %q", payload.Code, payload.Message, test.wantCode, test.wantMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package http
+
+import (
+	"encoding/json"
+	"github.com/vaberof/hezzl-backend/internal/app/entrypoint/http/views"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"github.com/vaberof/hezzl-backend/pkg/http/protocols/apiv1"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// listFilterFromRequest reads the optional 'projectId', 'removed', comma separated 'tags' and 'attributes'
+// query parameters shared by listing and export. 'attributes' is a JSON object the attributes of the goods must contain.
+// It responds with an error and returns false if a parameter is malformed.
+func listFilterFromRequest(rw http.ResponseWriter, request *http.Request) (*good.ListFilter, bool) {
+	var filter good.ListFilter
+
+	if projectIdStr := request.URL.Query().Get("projectId"); projectIdStr != "" {
+		projectId, err := strconv.ParseInt(projectIdStr, 10, 64)
+		if err != nil {
+			views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to convert projectId to int"}))
+
+			return nil, false
+		}
+
+		domainProjectId := domain.ProjectId(projectId)
+		filter.ProjectId = &domainProjectId
+	}
+
+	if removedStr := request.URL.Query().Get("removed"); removedStr != "" {
+		removed, err := strconv.ParseBool(removedStr)
+		if err != nil {
+			views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "'removed' must be a boolean"}))
+
+			return nil, false
+		}
+
+		filter.Removed = &removed
+	}
+
+	if tagsStr := request.URL.Query().Get("tags"); tagsStr != "" {
+		seen := make(map[domain.TagName]struct{})
+		for _, tag := range strings.Split(tagsStr, ",") {
+			tagName := domain.TagName(strings.TrimSpace(tag))
+			if _, ok := seen[tagName]; ok || tagName == "" {
+				continue
+			}
+			seen[tagName] = struct{}{}
+			filter.Tags = append(filter.Tags, tagName)
+		}
+	}
+
+	if attributesStr := request.URL.Query().Get("attributes"); attributesStr != "" {
+		if err := json.Unmarshal([]byte(attributesStr), &filter.Attributes); err != nil || filter.Attributes == nil {
+			views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "'attributes' must be a JSON object"}))
+
+			return nil, false
+		}
+	}
+
+	return &filter, true
+}
@@ -28,13 +28,24 @@ type metaPayload struct {
 }
 
 type listGoodPayload struct {
-	Id          int64     `json:"id"`
-	ProjectId   int64     `json:"projectId"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Priority    int       `json:"priority"`
-	Removed     bool      `json:"removed"`
-	CreatedAt   time.Time `json:"createdAt"`
+	Id          int64              `json:"id"`
+	ProjectId   int64              `json:"projectId"`
+	ParentId    *int64             `json:"parentId,omitempty"`
+	Locale      string             `json:"locale"`
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Priority    int                `json:"priority"`
+	Removed     bool               `json:"removed"`
+	CreatedAt   time.Time          `json:"createdAt"`
+	CreatedBy   string             `json:"createdBy"`
+	UpdatedBy   string             `json:"updatedBy"`
+	Version     int64              `json:"version"`
+	ExternalKey string             `json:"externalKey,omitempty"`
+	Attributes  map[string]any     `json:"attributes"`
+	Price       *pricePayload      `json:"price"`
+	Stock       int64              `json:"stock"`
+	Reserved    int64              `json:"reserved"`
+	Variants    []*listGoodPayload `json:"variants,omitempty"`
 }
 
 func (h *Handler) ListGoodsHandler() http.HandlerFunc {
@@ -77,18 +88,43 @@ func (h *Handler) ListGoodsHandler() http.HandlerFunc {
 			}
 		}
 
-		domainGoods, err := h.goodService.List(limit, offset)
+		filter, ok := listFilterFromRequest(rw, request)
+		if !ok {
+			return
+		}
+
+		expand, ok := expandVariantsFromRequest(rw, request)
+		if !ok {
+			return
+		}
+
+		// Expanded variants are nested in their parents instead of being listed on their own.
+		filter.TopLevel = expand
+
+		locales := localesFromRequest(request)
+
+		domainGoods, err := h.goodService.List(request.Context(), filter, limit, offset, locales)
 		if err != nil {
 			views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to list goods"}))
 
 			return
 		}
 
+		if expand {
+			if err = h.goodService.ExpandVariants(request.Context(), domainGoods, locales); err != nil {
+				views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to list variants"}))
+
+				return
+			}
+		}
+
 		payload, _ := json.Marshal(&listGoodsResponseBody{
 			Meta:  h.buildMetaPayload(domainGoods, limit, offset),
 			Goods: h.buildListGoodPayloads(domainGoods),
 		})
 
+		setContentLanguage(rw, "")
+
 		views.RenderJSON(rw, request, http.StatusOK, apiv1.Success(payload))
 	}
 }
@@ -126,11 +162,25 @@ func (h *Handler) buildListGoodPayload(domainGood *good.Good) *listGoodPayload {
 
 	goodPayload.Id = domainGood.Id.Int64()
 	goodPayload.ProjectId = domainGood.ProjectId.Int64()
+	goodPayload.ParentId = parentIdPayload(domainGood)
+	goodPayload.Locale = domainGood.Locale.String()
 	goodPayload.Name = domainGood.Name.String()
 	goodPayload.Description = domainGood.Description.String()
 	goodPayload.Priority = domainGood.Priority.Int()
 	goodPayload.Removed = domainGood.Removed.Bool()
 	goodPayload.CreatedAt = domainGood.CreatedAt.Time()
+	goodPayload.CreatedBy = domainGood.CreatedBy.String()
+	goodPayload.UpdatedBy = domainGood.UpdatedBy.String()
+	goodPayload.Version = domainGood.Version.Int64()
+	goodPayload.ExternalKey = domainGood.ExternalKey.String()
+	goodPayload.Attributes = domainGood.Attributes
+	goodPayload.Price = buildPricePayload(domainGood.Price)
+	goodPayload.Stock = domainGood.Stock
+	goodPayload.Reserved = domainGood.Reserved
+
+	if domainGood.Variants != nil {
+		goodPayload.Variants = h.buildListGoodPayloads(domainGood.Variants)
+	}
 
 	return &goodPayload
 }
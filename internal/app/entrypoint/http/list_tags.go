@@ -0,0 +1,60 @@
+package http
+
+import (
+	"encoding/json"
+	"github.com/vaberof/hezzl-backend/internal/app/entrypoint/http/views"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"github.com/vaberof/hezzl-backend/pkg/http/protocols/apiv1"
+	"net/http"
+	"strconv"
+)
+
+type listTagsResponseBody struct {
+	ProjectId int64         `json:"projectId"`
+	Tags      []*tagPayload `json:"tags"`
+}
+
+type tagPayload struct {
+	Name       string `json:"name"`
+	GoodsCount int    `json:"goodsCount"`
+}
+
+func (h *Handler) ListTagsHandler() http.HandlerFunc {
+	return func(rw http.ResponseWriter, request *http.Request) {
+		projectIdStr := request.URL.Query().Get("projectId")
+		if projectIdStr == "" {
+			views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "Missing required query parameter 'projectId'"}))
+
+			return
+		}
+
+		projectId, err := strconv.ParseInt(projectIdStr, 10, 64)
+		if err != nil {
+			views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to convert projectId to int"}))
+
+			return
+		}
+
+		domainTags, err := h.goodService.ListTags(request.Context(), domain.ProjectId(projectId))
+		if err != nil {
+			views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to list tags"}))
+
+			return
+		}
+
+		tags := make([]*tagPayload, len(domainTags))
+		for i, domainTag := range domainTags {
+			tags[i] = &tagPayload{
+				Name:       domainTag.Name.String(),
+				GoodsCount: domainTag.GoodsCount,
+			}
+		}
+
+		payload, _ := json.Marshal(&listTagsResponseBody{
+			ProjectId: projectId,
+			Tags:      tags,
+		})
+
+		views.RenderJSON(rw, request, http.StatusOK, apiv1.Success(payload))
+	}
+}
@@ -0,0 +1,34 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// extendRequest lets a handler transferring large bodies run for up to timeout, in place of the server
+// request, read and write timeouts, which are sized for regular API calls.
+// The returned context keeps the values of the request context, so traces and logs stay attached,
+// but is only canceled when the timeout passes or the client goes away.
+func extendRequest(rw http.ResponseWriter, request *http.Request, timeout time.Duration) (context.Context, context.CancelFunc) {
+	deadline := time.Now().Add(timeout)
+
+	// Writers that do not support deadlines keep the server timeouts.
+	responseController := http.NewResponseController(rw)
+	_ = responseController.SetReadDeadline(deadline)
+	_ = responseController.SetWriteDeadline(deadline)
+
+	ctx, cancel := context.WithDeadline(context.WithoutCancel(request.Context()), deadline)
+
+	stop := context.AfterFunc(request.Context(), func() {
+		if !errors.Is(request.Context().Err(), context.DeadlineExceeded) {
+			cancel()
+		}
+	})
+
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/vaberof/hezzl-backend/internal/app/entrypoint/http/views"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"github.com/vaberof/hezzl-backend/pkg/http/protocols/apiv1"
+	"net/http"
+	"strconv"
+)
+
+type setDefaultLocaleRequestBody struct {
+	DefaultLocale string `json:"defaultLocale"`
+}
+
+func (s *setDefaultLocaleRequestBody) Bind(req *http.Request) error {
+	return nil
+}
+
+type defaultLocaleResponseBody struct {
+	ProjectId     int64  `json:"projectId"`
+	DefaultLocale string `json:"defaultLocale"`
+}
+
+func (h *Handler) GetDefaultLocaleHandler() http.HandlerFunc {
+	return func(rw http.ResponseWriter, request *http.Request) {
+		projectId, err := strconv.ParseInt(chi.URLParam(request, "id"), 10, 64)
+		if err != nil {
+			views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to convert id to int"}))
+
+			return
+		}
+
+		defaultLocale, err := h.goodService.GetDefaultLocale(request.Context(), domain.ProjectId(projectId))
+		if err != nil {
+			if errors.Is(err, good.ErrProjectNotFound) {
+				views.RenderJSON(rw, request, http.StatusNotFound, apiv1.Error(CodeNotFound, ErrMessageProjectNotFound, apiv1.ErrorDescription{"details": "Project is not found"}))
+			} else {
+				views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to get the default locale"}))
+			}
+
+			return
+		}
+
+		payload, _ := json.Marshal(&defaultLocaleResponseBody{
+			ProjectId:     projectId,
+			DefaultLocale: defaultLocale.String(),
+		})
+
+		views.RenderJSON(rw, request, http.StatusOK, apiv1.Success(payload))
+	}
+}
+
+func (h *Handler) SetDefaultLocaleHandler() http.HandlerFunc {
+	return func(rw http.ResponseWriter, request *http.Request) {
+		projectId, err := strconv.ParseInt(chi.URLParam(request, "id"), 10, 64)
+		if err != nil {
+			views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to convert id to int"}))
+
+			return
+		}
+
+		setDefaultLocaleReqBody := &setDefaultLocaleRequestBody{}
+		if err = render.Bind(request, setDefaultLocaleReqBody); err != nil {
+			renderBindError(rw, request, err)
+
+			return
+		}
+
+		if setDefaultLocaleReqBody.DefaultLocale == "" {
+			views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "Missing required field 'defaultLocale'"}))
+
+			return
+		}
+
+		defaultLocale, err := h.goodService.SetDefaultLocale(request.Context(), domain.ProjectId(projectId), domain.Locale(setDefaultLocaleReqBody.DefaultLocale))
+		if err != nil {
+			if errors.Is(err, good.ErrInvalidLocale) {
+				views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidLocale, apiv1.ErrorDescription{"details": err.Error()}))
+			} else if errors.Is(err, good.ErrProjectNotFound) {
+				views.RenderJSON(rw, request, http.StatusNotFound, apiv1.Error(CodeNotFound, ErrMessageProjectNotFound, apiv1.ErrorDescription{"details": "Project is not found"}))
+			} else {
+				views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to set the default locale"}))
+			}
+
+			return
+		}
+
+		payload, _ := json.Marshal(&defaultLocaleResponseBody{
+			ProjectId:     projectId,
+			DefaultLocale: defaultLocale.String(),
+		})
+
+		views.RenderJSON(rw, request, http.StatusOK, apiv1.Success(payload))
+	}
+}
@@ -0,0 +1,80 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/vaberof/hezzl-backend/internal/app/entrypoint/http/views"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"github.com/vaberof/hezzl-backend/pkg/http/protocols/apiv1"
+	"net/http"
+	"strconv"
+)
+
+type purgeGoodResponseBody struct {
+	Id        int64 `json:"id"`
+	ProjectId int64 `json:"projectId"`
+	Purged    bool  `json:"purged"`
+}
+
+func (h *Handler) PurgeGoodHandler() http.HandlerFunc {
+	return func(rw http.ResponseWriter, request *http.Request) {
+		goodIdStr := request.URL.Query().Get("id")
+		if goodIdStr == "" {
+			views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "Missing required query parameter 'id'"}))
+
+			return
+		}
+
+		projectIdStr := request.URL.Query().Get("projectId")
+		if projectIdStr == "" {
+			views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "Missing required query parameter 'projectId'"}))
+
+			return
+		}
+
+		goodId, err := strconv.ParseInt(goodIdStr, 10, 64)
+		if err != nil {
+			views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to convert id to int"}))
+
+			return
+		}
+
+		projectId, err := strconv.ParseInt(projectIdStr, 10, 64)
+		if err != nil {
+			views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to convert projectId to int"}))
+
+			return
+		}
+
+		expectedVersion, err := expectedVersionFromRequest(request)
+		if err != nil {
+			renderMalformedIfMatch(rw, request)
+
+			return
+		}
+
+		actor, _ := ActorFromContext(request.Context())
+
+		_, err = h.goodService.Purge(request.Context(), domain.GoodId(goodId), domain.ProjectId(projectId), actor, expectedVersion)
+		if err != nil {
+			if errors.Is(err, good.ErrGoodNotFound) {
+				views.RenderJSON(rw, request, http.StatusNotFound, apiv1.Error(CodeNotFound, ErrMessageGoodNotFound, apiv1.ErrorDescription{"details": "Good is not found"}))
+			} else if errors.Is(err, good.ErrGoodVersionMismatch) {
+				renderVersionMismatch(rw, request)
+			} else {
+				views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to purge a good"}))
+			}
+
+			return
+		}
+
+		payload, _ := json.Marshal(&purgeGoodResponseBody{
+			Id:        goodId,
+			ProjectId: projectId,
+			Purged:    true,
+		})
+
+		views.RenderJSON(rw, request, http.StatusOK, apiv1.Success(payload))
+	}
+}
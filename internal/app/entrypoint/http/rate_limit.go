@@ -0,0 +1,133 @@
+package http
+
+import (
+	"github.com/vaberof/hezzl-backend/internal/app/entrypoint/http/views"
+	"github.com/vaberof/hezzl-backend/pkg/http/protocols/apiv1"
+	"github.com/vaberof/hezzl-backend/pkg/ratelimit"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	routeGoodGet                = "good.get"
+	routeGoodCreate             = "good.create"
+	routeGoodUpdate             = "good.update"
+	routeGoodReprioritize       = "good.reprioritize"
+	routeGoodRemove             = "good.remove"
+	routeGoodRestore            = "good.restore"
+	routeGoodPurge              = "good.purge"
+	routeGoodsList              = "goods.list"
+	routeGoodsBatchCreate       = "goods.batch.create"
+	routeGoodsBatchUpdate       = "goods.batch.update"
+	routeGoodsBatchRemove       = "goods.batch.remove"
+	routeGoodsImport            = "goods.import"
+	routeGoodsImportJob         = "goods.import.job"
+	routeGoodsExport            = "goods.export"
+	routeGoodsSearch            = "goods.search"
+	routeGoodTagsGet            = "good.tags.get"
+	routeGoodTagsAdd            = "good.tags.add"
+	routeGoodTagsRemove         = "good.tags.remove"
+	routeTagsList               = "tags.list"
+	routeAttributeSchemaGet     = "attributes.schema.get"
+	routeAttributeSchemaSet     = "attributes.schema.set"
+	routeAttributeSchemaDelete  = "attributes.schema.delete"
+	routeGoodAttachmentsUpload  = "good.attachments.upload"
+	routeGoodAttachmentsList    = "good.attachments.list"
+	routeGoodAttachmentsGet     = "good.attachments.get"
+	routeGoodAttachmentsRemove  = "good.attachments.remove"
+	routeGoodPriceSet           = "good.price.set"
+	routeGoodStockReserve       = "good.stock.reserve"
+	routeGoodStockRelease       = "good.stock.release"
+	routeGoodStockAdjust        = "good.stock.adjust"
+	routeGoodStockMovements     = "good.stock.movements"
+	routeGoodVariantsCreate     = "good.variants.create"
+	routeGoodTranslationsList   = "good.translations.list"
+	routeGoodTranslationsSet    = "good.translations.set"
+	routeGoodTranslationsRemove = "good.translations.remove"
+	routeDefaultLocaleGet       = "locale.default.get"
+	routeDefaultLocaleSet       = "locale.default.set"
+	routeGoodMove               = "good.move"
+	routeGoodCopy               = "good.copy"
+)
+
+// RateLimit limits the named route according to its rate limit config.
+// Routes without config are not limited; if the limiter fails the request is let through.
+func (h *Handler) RateLimit(route string) func(http.Handler) http.Handler {
+	routeConfig, ok := h.rateLimitConfig.Routes[route]
+	if !h.rateLimitConfig.Enabled || !ok || h.rateLimiter == nil {
+		return func(next http.Handler) http.Handler {
+			return next
+		}
+	}
+
+	limit := ratelimit.Limit{
+		Rate:  routeConfig.RequestsPerSecond,
+		Burst: routeConfig.Burst,
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, request *http.Request) {
+			key := route + "_" + rateLimitKey(request, routeConfig.KeyBy)
+
+			result, err := h.rateLimiter.Allow(request.Context(), key, limit)
+			if err != nil {
+				slog.ErrorContext(request.Context(), "rate limiter failed, skipping limit", "route", route, "error", err)
+				next.ServeHTTP(rw, request)
+
+				return
+			}
+
+			rw.Header().Set("X-RateLimit-Limit", strconv.Itoa(limit.Burst))
+			rw.Header().Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+
+			if !result.Allowed {
+				retryAfterSeconds := int(math.Ceil(result.RetryAfter.Seconds()))
+				rw.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds))
+
+				views.RenderJSON(rw, request, http.StatusTooManyRequests, apiv1.Error(CodeTooManyRequests, ErrMessageTooManyRequests, apiv1.ErrorDescription{"details": "Rate limit exceeded", "retryAfter": retryAfterSeconds}))
+
+				return
+			}
+
+			next.ServeHTTP(rw, request)
+		})
+	}
+}
+
+func rateLimitKey(request *http.Request, keyBy []string) string {
+	if len(keyBy) == 0 {
+		keyBy = []string{ratelimit.KeyByClient}
+	}
+
+	keyParts := make([]string, len(keyBy))
+	for i, attribute := range keyBy {
+		switch attribute {
+		case ratelimit.KeyByClient, ratelimit.KeyByApiKey:
+			// Only authenticated keys are trusted, otherwise a client could get a fresh bucket with every new key.
+			// The key itself is never part of the bucket key, which ends up in Redis and in logs.
+			if principal, ok := PrincipalFromContext(request.Context()); ok {
+				keyParts[i] = "key:" + principal.KeyId
+			} else {
+				keyParts[i] = "ip:" + clientIp(request)
+			}
+		case ratelimit.KeyByIp:
+			keyParts[i] = "ip:" + clientIp(request)
+		case ratelimit.KeyByProjectId:
+			keyParts[i] = "project:" + request.URL.Query().Get("projectId")
+		}
+	}
+
+	return strings.Join(keyParts, "_")
+}
+
+func clientIp(request *http.Request) string {
+	host, _, err := net.SplitHostPort(request.RemoteAddr)
+	if err != nil {
+		return request.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,10 @@
+package http
+
+import (
+	"context"
+	"github.com/vaberof/hezzl-backend/pkg/ratelimit"
+)
+
+type RateLimiter interface {
+	Allow(ctx context.Context, key string, limit ratelimit.Limit) (*ratelimit.Result, error)
+}
@@ -0,0 +1,113 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/vaberof/hezzl-backend/internal/app/entrypoint/http/views"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"github.com/vaberof/hezzl-backend/pkg/http/protocols/apiv1"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type restoreGoodResponseBody struct {
+	Id          int64          `json:"id"`
+	ProjectId   int64          `json:"projectId"`
+	ParentId    *int64         `json:"parentId,omitempty"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Priority    int            `json:"priority"`
+	Removed     bool           `json:"removed"`
+	CreatedAt   time.Time      `json:"createdAt"`
+	CreatedBy   string         `json:"createdBy"`
+	UpdatedBy   string         `json:"updatedBy"`
+	Version     int64          `json:"version"`
+	ExternalKey string         `json:"externalKey,omitempty"`
+	Attributes  map[string]any `json:"attributes"`
+	Price       *pricePayload  `json:"price"`
+	Stock       int64          `json:"stock"`
+	Reserved    int64          `json:"reserved"`
+}
+
+func (h *Handler) RestoreGoodHandler() http.HandlerFunc {
+	return func(rw http.ResponseWriter, request *http.Request) {
+		goodIdStr := request.URL.Query().Get("id")
+		if goodIdStr == "" {
+			views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "Missing required query parameter 'id'"}))
+
+			return
+		}
+
+		projectIdStr := request.URL.Query().Get("projectId")
+		if projectIdStr == "" {
+			views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "Missing required query parameter 'projectId'"}))
+
+			return
+		}
+
+		goodId, err := strconv.ParseInt(goodIdStr, 10, 64)
+		if err != nil {
+			views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to convert id to int"}))
+
+			return
+		}
+
+		projectId, err := strconv.ParseInt(projectIdStr, 10, 64)
+		if err != nil {
+			views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to convert projectId to int"}))
+
+			return
+		}
+
+		expectedVersion, err := expectedVersionFromRequest(request)
+		if err != nil {
+			renderMalformedIfMatch(rw, request)
+
+			return
+		}
+
+		actor, _ := ActorFromContext(request.Context())
+
+		domainGood, err := h.goodService.Restore(request.Context(), domain.GoodId(goodId), domain.ProjectId(projectId), actor, expectedVersion)
+		if err != nil {
+			if errors.Is(err, good.ErrGoodNotFound) {
+				views.RenderJSON(rw, request, http.StatusNotFound, apiv1.Error(CodeNotFound, ErrMessageGoodNotFound, apiv1.ErrorDescription{"details": "Good is not found"}))
+			} else if errors.Is(err, good.ErrParentRemoved) {
+				views.RenderJSON(rw, request, http.StatusConflict, apiv1.Error(CodeConflict, ErrMessageParentRemoved, apiv1.ErrorDescription{"details": "Parent good is removed"}))
+			} else if errors.Is(err, good.ErrGoodNotRemoved) {
+				views.RenderJSON(rw, request, http.StatusConflict, apiv1.Error(CodeConflict, ErrMessageGoodNotRemoved, apiv1.ErrorDescription{"details": "Good is not removed"}))
+			} else if errors.Is(err, good.ErrGoodVersionMismatch) {
+				renderVersionMismatch(rw, request)
+			} else {
+				views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to restore a good"}))
+			}
+
+			return
+		}
+
+		payload, _ := json.Marshal(&restoreGoodResponseBody{
+			Id:          domainGood.Id.Int64(),
+			ProjectId:   domainGood.ProjectId.Int64(),
+			ParentId:    parentIdPayload(domainGood),
+			Name:        domainGood.Name.String(),
+			Description: domainGood.Description.String(),
+			Priority:    domainGood.Priority.Int(),
+			Removed:     domainGood.Removed.Bool(),
+			CreatedAt:   domainGood.CreatedAt.Time(),
+			CreatedBy:   domainGood.CreatedBy.String(),
+			UpdatedBy:   domainGood.UpdatedBy.String(),
+			Version:     domainGood.Version.Int64(),
+			ExternalKey: domainGood.ExternalKey.String(),
+			Attributes:  domainGood.Attributes,
+			Price:       buildPricePayload(domainGood.Price),
+			Stock:       domainGood.Stock,
+			Reserved:    domainGood.Reserved,
+		})
+
+		rw.Header().Set(etagHeader, goodETag(domainGood.Version))
+
+		views.RenderJSON(rw, request, http.StatusOK, apiv1.Success(payload))
+	}
+}
@@ -0,0 +1,123 @@
+package http
+
+import (
+	"encoding/json"
+	"github.com/vaberof/hezzl-backend/internal/app/entrypoint/http/views"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"github.com/vaberof/hezzl-backend/pkg/http/protocols/apiv1"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxSearchLimit       = 100
+	maxSearchQueryLength = 200
+)
+
+type searchGoodsResponseBody struct {
+	Meta  metaPayload          `json:"meta"`
+	Goods []*searchGoodPayload `json:"goods"`
+}
+
+// searchGoodPayload is the list payload of a good extended with its search relevance.
+type searchGoodPayload struct {
+	*listGoodPayload
+	Rank      float64                `json:"rank"`
+	Highlight searchHighlightPayload `json:"highlight"`
+}
+
+type searchHighlightPayload struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+func (h *Handler) SearchGoodsHandler() http.HandlerFunc {
+	return func(rw http.ResponseWriter, request *http.Request) {
+		text := strings.TrimSpace(request.URL.Query().Get("q"))
+		if text == "" {
+			views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "Missing required query parameter 'q'"}))
+
+			return
+		}
+		if utf8.RuneCountInString(text) > maxSearchQueryLength {
+			views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "'q' must not exceed " + strconv.Itoa(maxSearchQueryLength) + " characters"}))
+
+			return
+		}
+
+		projectIdStr := request.URL.Query().Get("projectId")
+		if projectIdStr == "" {
+			views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "Missing required query parameter 'projectId'"}))
+
+			return
+		}
+
+		projectId, err := strconv.ParseInt(projectIdStr, 10, 64)
+		if err != nil {
+			views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to convert projectId to int"}))
+
+			return
+		}
+
+		limit := defaultLimit
+		if limitStr := request.URL.Query().Get("limit"); limitStr != "" {
+			limit, err = strconv.Atoi(limitStr)
+			if err != nil {
+				views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to convert limit to int"}))
+
+				return
+			}
+			if limit < 0 || limit > maxSearchLimit {
+				views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "'limit' must be between 0 and " + strconv.Itoa(maxSearchLimit)}))
+
+				return
+			}
+		}
+
+		offset := 0
+		if offsetStr := request.URL.Query().Get("offset"); offsetStr != "" {
+			offset, err = strconv.Atoi(offsetStr)
+			if err != nil {
+				views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to convert offset to int"}))
+
+				return
+			}
+			if offset < 0 {
+				views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "'offset' must not be negative"}))
+
+				return
+			}
+		}
+
+		searchResults, err := h.goodService.Search(request.Context(), domain.ProjectId(projectId), text, limit, offset)
+		if err != nil {
+			views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to search goods"}))
+
+			return
+		}
+
+		domainGoods := make([]*good.Good, len(searchResults))
+		goodPayloads := make([]*searchGoodPayload, len(searchResults))
+		for i, searchResult := range searchResults {
+			domainGoods[i] = searchResult.Good
+			goodPayloads[i] = &searchGoodPayload{
+				listGoodPayload: h.buildListGoodPayload(searchResult.Good),
+				Rank:            searchResult.Rank,
+				Highlight: searchHighlightPayload{
+					Name:        searchResult.NameHighlight,
+					Description: searchResult.DescriptionHighlight,
+				},
+			}
+		}
+
+		payload, _ := json.Marshal(&searchGoodsResponseBody{
+			Meta:  h.buildMetaPayload(domainGoods, limit, offset),
+			Goods: goodPayloads,
+		})
+
+		views.RenderJSON(rw, request, http.StatusOK, apiv1.Success(payload))
+	}
+}
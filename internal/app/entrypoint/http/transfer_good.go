@@ -0,0 +1,132 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/render"
+	"github.com/vaberof/hezzl-backend/internal/app/entrypoint/http/views"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"github.com/vaberof/hezzl-backend/pkg/http/protocols/apiv1"
+	"net/http"
+)
+
+type transferGoodRequestBody struct {
+	TargetProjectId *int64 `json:"targetProjectId"`
+	// Tags and Attachments carry the tags and the attachments of the good over to the target project.
+	Tags        bool `json:"tags"`
+	Attachments bool `json:"attachments"`
+}
+
+func (t *transferGoodRequestBody) Bind(req *http.Request) error {
+	return nil
+}
+
+func (h *Handler) MoveGoodHandler() http.HandlerFunc {
+	return func(rw http.ResponseWriter, request *http.Request) {
+		goodId, projectId, ok := goodIdsFromRequest(rw, request)
+		if !ok {
+			return
+		}
+
+		transferGoodReqBody, ok := transferGoodRequestBodyFromRequest(rw, request)
+		if !ok {
+			return
+		}
+
+		expectedVersion, err := expectedVersionFromRequest(request)
+		if err != nil {
+			renderMalformedIfMatch(rw, request)
+
+			return
+		}
+
+		actor, _ := ActorFromContext(request.Context())
+
+		options := &good.TransferOptions{Tags: transferGoodReqBody.Tags, Attachments: transferGoodReqBody.Attachments}
+
+		domainGood, err := h.goodService.Move(request.Context(), goodId, projectId, domain.ProjectId(*transferGoodReqBody.TargetProjectId), options, actor, expectedVersion)
+		if err != nil {
+			renderTransferError(rw, request, err, "Failed to move a good")
+
+			return
+		}
+
+		payload, _ := json.Marshal(h.buildListGoodPayload(domainGood))
+
+		rw.Header().Set(etagHeader, goodETag(domainGood.Version))
+
+		views.RenderJSON(rw, request, http.StatusOK, apiv1.Success(payload))
+	}
+}
+
+func (h *Handler) CopyGoodHandler() http.HandlerFunc {
+	return func(rw http.ResponseWriter, request *http.Request) {
+		goodId, projectId, ok := goodIdsFromRequest(rw, request)
+		if !ok {
+			return
+		}
+
+		transferGoodReqBody, ok := transferGoodRequestBodyFromRequest(rw, request)
+		if !ok {
+			return
+		}
+
+		actor, _ := ActorFromContext(request.Context())
+
+		options := &good.TransferOptions{Tags: transferGoodReqBody.Tags, Attachments: transferGoodReqBody.Attachments}
+
+		domainGood, err := h.goodService.Copy(request.Context(), goodId, projectId, domain.ProjectId(*transferGoodReqBody.TargetProjectId), options, actor)
+		if err != nil {
+			renderTransferError(rw, request, err, "Failed to copy a good")
+
+			return
+		}
+
+		payload, _ := json.Marshal(h.buildListGoodPayload(domainGood))
+
+		rw.Header().Set(etagHeader, goodETag(domainGood.Version))
+
+		views.RenderJSON(rw, request, http.StatusCreated, apiv1.Success(payload))
+	}
+}
+
+func transferGoodRequestBodyFromRequest(rw http.ResponseWriter, request *http.Request) (*transferGoodRequestBody, bool) {
+	transferGoodReqBody := &transferGoodRequestBody{}
+	if err := render.Bind(request, transferGoodReqBody); err != nil {
+		renderBindError(rw, request, err)
+
+		return nil, false
+	}
+
+	if transferGoodReqBody.TargetProjectId == nil {
+		views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "Missing required field 'targetProjectId'"}))
+
+		return nil, false
+	}
+
+	return transferGoodReqBody, true
+}
+
+func renderTransferError(rw http.ResponseWriter, request *http.Request, err error, details string) {
+	switch {
+	case errors.Is(err, good.ErrInvalidTransfer):
+		views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidTransfer, apiv1.ErrorDescription{"details": err.Error()}))
+	case errors.Is(err, good.ErrInvalidAttributes):
+		renderInvalidAttributes(rw, request, err)
+	case errors.Is(err, good.ErrGoodNotFound):
+		views.RenderJSON(rw, request, http.StatusNotFound, apiv1.Error(CodeNotFound, ErrMessageGoodNotFound, apiv1.ErrorDescription{"details": "Good is not found"}))
+	case errors.Is(err, good.ErrProjectNotFound):
+		views.RenderJSON(rw, request, http.StatusNotFound, apiv1.Error(CodeNotFound, ErrMessageProjectNotFound, apiv1.ErrorDescription{"details": "Target project is not found"}))
+	case errors.Is(err, good.ErrGoodRemoved):
+		views.RenderJSON(rw, request, http.StatusConflict, apiv1.Error(CodeConflict, ErrMessageGoodRemoved, apiv1.ErrorDescription{"details": "Good is removed"}))
+	case errors.Is(err, good.ErrVariantTransfer):
+		views.RenderJSON(rw, request, http.StatusConflict, apiv1.Error(CodeConflict, ErrMessageVariantTransfer, apiv1.ErrorDescription{"details": err.Error()}))
+	case errors.Is(err, good.ErrExternalKeyConflict):
+		views.RenderJSON(rw, request, http.StatusConflict, apiv1.Error(CodeConflict, ErrMessageExternalKeyConflict, apiv1.ErrorDescription{"details": err.Error()}))
+	case errors.Is(err, good.ErrGoodVersionMismatch):
+		renderVersionMismatch(rw, request)
+	default:
+		views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": details}))
+	}
+}
@@ -16,6 +16,8 @@ import (
 type updateGoodRequestBody struct {
 	Name        string  `json:"name"`
 	Description *string `json:"description,omitempty"`
+	// Attributes replace the attributes of the good; they are kept if omitted.
+	Attributes map[string]any `json:"attributes,omitempty"`
 }
 
 func (u *updateGoodRequestBody) Bind(req *http.Request) error {
@@ -23,13 +25,22 @@ func (u *updateGoodRequestBody) Bind(req *http.Request) error {
 }
 
 type updateGoodResponseBody struct {
-	Id          int64     `json:"id"`
-	ProjectId   int64     `json:"projectId"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Priority    int       `json:"priority"`
-	Removed     bool      `json:"removed"`
-	CreatedAt   time.Time `json:"createdAt"`
+	Id          int64          `json:"id"`
+	ProjectId   int64          `json:"projectId"`
+	ParentId    *int64         `json:"parentId,omitempty"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Priority    int            `json:"priority"`
+	Removed     bool           `json:"removed"`
+	CreatedAt   time.Time      `json:"createdAt"`
+	CreatedBy   string         `json:"createdBy"`
+	UpdatedBy   string         `json:"updatedBy"`
+	Version     int64          `json:"version"`
+	ExternalKey string         `json:"externalKey,omitempty"`
+	Attributes  map[string]any `json:"attributes"`
+	Price       *pricePayload  `json:"price"`
+	Stock       int64          `json:"stock"`
+	Reserved    int64          `json:"reserved"`
 }
 
 func (h *Handler) UpdateGoodHandler() http.HandlerFunc {
@@ -50,8 +61,7 @@ func (h *Handler) UpdateGoodHandler() http.HandlerFunc {
 
 		updateGoodReqBody := &updateGoodRequestBody{}
 		if err := render.Bind(request, updateGoodReqBody); err != nil {
-
-			views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "Invalid request body"}))
+			renderBindError(rw, request, err)
 
 			return
 		}
@@ -76,10 +86,25 @@ func (h *Handler) UpdateGoodHandler() http.HandlerFunc {
 			goodDescription = &domainDescription
 		}
 
-		domainGood, err := h.goodService.Update(domain.GoodId(goodId), domain.ProjectId(projectId), domain.GoodName(updateGoodReqBody.Name), goodDescription)
+		expectedVersion, err := expectedVersionFromRequest(request)
+		if err != nil {
+			renderMalformedIfMatch(rw, request)
+
+			return
+		}
+
+		actor, _ := ActorFromContext(request.Context())
+
+		domainGood, err := h.goodService.Update(request.Context(), domain.GoodId(goodId), domain.ProjectId(projectId), domain.GoodName(updateGoodReqBody.Name), goodDescription, updateGoodReqBody.Attributes, actor, expectedVersion)
 		if err != nil {
 			if errors.Is(err, good.ErrGoodNotFound) {
 				views.RenderJSON(rw, request, http.StatusNotFound, apiv1.Error(CodeNotFound, ErrMessageGoodNotFound, apiv1.ErrorDescription{"details": "Good is not found"}))
+			} else if errors.Is(err, good.ErrGoodRemoved) {
+				views.RenderJSON(rw, request, http.StatusConflict, apiv1.Error(CodeConflict, ErrMessageGoodRemoved, apiv1.ErrorDescription{"details": "Good is removed"}))
+			} else if errors.Is(err, good.ErrGoodVersionMismatch) {
+				renderVersionMismatch(rw, request)
+			} else if errors.Is(err, good.ErrInvalidAttributes) {
+				renderInvalidAttributes(rw, request, err)
 			} else {
 				views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to update a good"}))
 			}
@@ -90,13 +115,24 @@ func (h *Handler) UpdateGoodHandler() http.HandlerFunc {
 		payload, _ := json.Marshal(&updateGoodResponseBody{
 			Id:          domainGood.Id.Int64(),
 			ProjectId:   domainGood.ProjectId.Int64(),
+			ParentId:    parentIdPayload(domainGood),
 			Name:        domainGood.Name.String(),
 			Description: domainGood.Description.String(),
 			Priority:    domainGood.Priority.Int(),
 			Removed:     domainGood.Removed.Bool(),
 			CreatedAt:   domainGood.CreatedAt.Time(),
+			CreatedBy:   domainGood.CreatedBy.String(),
+			UpdatedBy:   domainGood.UpdatedBy.String(),
+			Version:     domainGood.Version.Int64(),
+			ExternalKey: domainGood.ExternalKey.String(),
+			Attributes:  domainGood.Attributes,
+			Price:       buildPricePayload(domainGood.Price),
+			Stock:       domainGood.Stock,
+			Reserved:    domainGood.Reserved,
 		})
 
+		rw.Header().Set(etagHeader, goodETag(domainGood.Version))
+
 		views.RenderJSON(rw, request, http.StatusOK, apiv1.Success(payload))
 	}
 }
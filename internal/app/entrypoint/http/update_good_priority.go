@@ -27,6 +27,7 @@ type updateGoodPriorityResponseBody struct {
 type updateGoodPriorityPayload struct {
 	Id       int64 `json:"id"`
 	Priority int   `json:"priority"`
+	Version  int64 `json:"version"`
 }
 
 func (h *Handler) UpdateGoodPriorityHandler() http.HandlerFunc {
@@ -47,7 +48,7 @@ func (h *Handler) UpdateGoodPriorityHandler() http.HandlerFunc {
 
 		updateGoodPriorityReqBody := &updateGoodPriorityRequestBody{}
 		if err := render.Bind(request, updateGoodPriorityReqBody); err != nil {
-			views.RenderJSON(rw, request, http.StatusBadRequest, apiv1.Error(CodeBadRequest, ErrMessageInvalidRequestBody, apiv1.ErrorDescription{"details": "Invalid request body"}))
+			renderBindError(rw, request, err)
 
 			return
 		}
@@ -66,10 +67,23 @@ func (h *Handler) UpdateGoodPriorityHandler() http.HandlerFunc {
 			return
 		}
 
-		domainGoods, err := h.goodService.ChangePriority(domain.GoodId(goodId), domain.ProjectId(projectId), domain.GoodPriority(updateGoodPriorityReqBody.NewPriority))
+		expectedVersion, err := expectedVersionFromRequest(request)
+		if err != nil {
+			renderMalformedIfMatch(rw, request)
+
+			return
+		}
+
+		actor, _ := ActorFromContext(request.Context())
+
+		domainGoods, err := h.goodService.ChangePriority(request.Context(), domain.GoodId(goodId), domain.ProjectId(projectId), domain.GoodPriority(updateGoodPriorityReqBody.NewPriority), actor, expectedVersion)
 		if err != nil {
 			if errors.Is(err, good.ErrGoodNotFound) {
 				views.RenderJSON(rw, request, http.StatusNotFound, apiv1.Error(CodeNotFound, ErrMessageGoodNotFound, apiv1.ErrorDescription{"details": "Good is not found"}))
+			} else if errors.Is(err, good.ErrGoodRemoved) {
+				views.RenderJSON(rw, request, http.StatusConflict, apiv1.Error(CodeConflict, ErrMessageGoodRemoved, apiv1.ErrorDescription{"details": "Good is removed"}))
+			} else if errors.Is(err, good.ErrGoodVersionMismatch) {
+				renderVersionMismatch(rw, request)
 			} else {
 				views.RenderJSON(rw, request, http.StatusInternalServerError, apiv1.Error(CodeInternalError, ErrMessageInternalServerError, apiv1.ErrorDescription{"details": "Failed to update a good"}))
 			}
@@ -98,6 +112,7 @@ func (h *Handler) buildUpdateGoodPriorityPayload(domainGood *good.Good) *updateG
 
 	goodPriorityPayload.Id = domainGood.Id.Int64()
 	goodPriorityPayload.Priority = domainGood.Priority.Int()
+	goodPriorityPayload.Version = domainGood.Version.Int64()
 
 	return &goodPriorityPayload
 }
@@ -0,0 +1,11 @@
+package retention
+
+import "time"
+
+type Config struct {
+	Enabled bool `yaml:"enabled"`
+	// RemovedGoodsDays is how many days removed goods are kept before they are purged.
+	RemovedGoodsDays int           `yaml:"removed_goods_days"`
+	Interval         time.Duration `yaml:"interval"`
+	BatchSize        int           `yaml:"batch_size"`
+}
@@ -0,0 +1,11 @@
+package retention
+
+import (
+	"context"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"time"
+)
+
+type GoodService interface {
+	PurgeRemoved(ctx context.Context, retention time.Duration, batchSize int, actor domain.Actor) (int, error)
+}
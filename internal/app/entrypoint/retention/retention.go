@@ -0,0 +1,112 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// actor is recorded in the good logs of the goods purged by the job.
+const actor domain.Actor = "system:retention"
+
+const (
+	defaultInterval  = 1 * time.Hour
+	defaultBatchSize = 100
+)
+
+// Job periodically purges goods that have been removed longer than the configured retention.
+type Job struct {
+	goodService GoodService
+	retention   time.Duration
+	interval    time.Duration
+	batchSize   int
+
+	cancel context.CancelFunc
+	done   sync.WaitGroup
+}
+
+func New(config *Config, goodService GoodService) (*Job, error) {
+	if config.RemovedGoodsDays <= 0 {
+		return nil, errors.New("retention: removed_goods_days must be positive")
+	}
+
+	interval := config.Interval
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	batchSize := config.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	return &Job{
+		goodService: goodService,
+		retention:   time.Duration(config.RemovedGoodsDays) * 24 * time.Hour,
+		interval:    interval,
+		batchSize:   batchSize,
+	}, nil
+}
+
+// Start runs the job right away and then every interval until Stop is called.
+func (j *Job) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	j.cancel = cancel
+
+	j.done.Add(1)
+	go func() {
+		defer j.done.Done()
+
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			j.run(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop interrupts the current run and waits for the job to exit or ctx to be done.
+func (j *Job) Stop(ctx context.Context) error {
+	if j.cancel == nil {
+		return nil
+	}
+	j.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		j.done.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (j *Job) run(ctx context.Context) {
+	purged, err := j.goodService.PurgeRemoved(ctx, j.retention, j.batchSize, actor)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		slog.ErrorContext(ctx, "failed to purge removed goods", "purged", purged, "error", err)
+
+		return
+	}
+
+	if purged > 0 {
+		slog.InfoContext(ctx, "purged removed goods", "purged", purged, "retention", j.retention.String())
+	}
+}
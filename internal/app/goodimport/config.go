@@ -0,0 +1,18 @@
+package goodimport
+
+import "time"
+
+type Config struct {
+	// MaxUploadBytes limits the size of an uploaded file.
+	MaxUploadBytes int64 `yaml:"max_upload_bytes"`
+	// AsyncThresholdBytes is the upload size above which the import runs as a background job.
+	// Uploads of unknown size always run in the background.
+	AsyncThresholdBytes int64 `yaml:"async_threshold_bytes"`
+	// SpoolDir keeps the uploads of background jobs until they are imported, the system temp dir by default.
+	SpoolDir string `yaml:"spool_dir"`
+	// UploadTimeout limits how long a single upload may take, including a synchronous import,
+	// in place of the server request and read timeouts.
+	UploadTimeout time.Duration `yaml:"upload_timeout"`
+	// JobTTL is how long the status of a finished job is kept.
+	JobTTL time.Duration `yaml:"job_ttl"`
+}
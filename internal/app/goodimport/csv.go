@@ -0,0 +1,98 @@
+package goodimport
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"io"
+	"strings"
+)
+
+const (
+	csvColumnExternalKey = "external_key"
+	csvColumnName        = "name"
+	csvColumnDescription = "description"
+)
+
+// csvRowReader reads CSV files with a header row. The external_key and name columns are required,
+// description is optional; an empty description cell keeps the description of an existing good.
+type csvRowReader struct {
+	reader *csv.Reader
+
+	externalKeyColumn int
+	nameColumn        int
+	descriptionColumn int
+}
+
+func newCSVRowReader(r io.Reader) (*csvRowReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: missing header row", ErrInvalidFile)
+		}
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+	}
+
+	csvReader := &csvRowReader{
+		reader:            reader,
+		externalKeyColumn: -1,
+		nameColumn:        -1,
+		descriptionColumn: -1,
+	}
+
+	for i, column := range header {
+		switch strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff"))) {
+		case csvColumnExternalKey:
+			csvReader.externalKeyColumn = i
+		case csvColumnName:
+			csvReader.nameColumn = i
+		case csvColumnDescription:
+			csvReader.descriptionColumn = i
+		}
+	}
+
+	if csvReader.externalKeyColumn < 0 {
+		return nil, fmt.Errorf("%w: missing %q column", ErrInvalidFile, csvColumnExternalKey)
+	}
+	if csvReader.nameColumn < 0 {
+		return nil, fmt.Errorf("%w: missing %q column", ErrInvalidFile, csvColumnName)
+	}
+
+	return csvReader, nil
+}
+
+func (r *csvRowReader) Next() (*good.ImportRow, error) {
+	record, err := r.reader.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, &good.ImportRowError{Line: parseErr.StartLine, Reason: parseErr.Err.Error()}
+		}
+		return nil, err
+	}
+
+	line, _ := r.reader.FieldPos(0)
+
+	if r.externalKeyColumn >= len(record) || r.nameColumn >= len(record) {
+		return nil, &good.ImportRowError{Line: line, Reason: "too few columns"}
+	}
+
+	row := &good.ImportRow{
+		Line:        line,
+		ExternalKey: domain.GoodExternalKey(strings.TrimSpace(record[r.externalKeyColumn])),
+		Name:        domain.GoodName(record[r.nameColumn]),
+	}
+
+	if r.descriptionColumn >= 0 && r.descriptionColumn < len(record) && record[r.descriptionColumn] != "" {
+		description := domain.GoodDescription(record[r.descriptionColumn])
+		row.Description = &description
+	}
+
+	return row, nil
+}
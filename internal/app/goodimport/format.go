@@ -0,0 +1,31 @@
+package goodimport
+
+import (
+	"errors"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"io"
+)
+
+type Format string
+
+const (
+	FormatCSV    Format = "csv"
+	FormatNDJSON Format = "ndjson"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported import format")
+	ErrInvalidFile       = errors.New("invalid import file")
+)
+
+// NewRowReader returns a reader streaming the rows of r in the given format.
+func NewRowReader(format Format, r io.Reader) (good.ImportRowReader, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVRowReader(r)
+	case FormatNDJSON:
+		return newNDJSONRowReader(r), nil
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
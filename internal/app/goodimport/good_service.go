@@ -0,0 +1,11 @@
+package goodimport
+
+import (
+	"context"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+)
+
+type GoodService interface {
+	Import(ctx context.Context, projectId domain.ProjectId, rows good.ImportRowReader, actor domain.Actor) (*good.ImportReport, error)
+}
@@ -0,0 +1,11 @@
+package goodimport
+
+import (
+	"context"
+	"time"
+)
+
+type JobStorage interface {
+	Set(ctx context.Context, key, value string, exp time.Duration) error
+	Get(ctx context.Context, key string) (string, error)
+}
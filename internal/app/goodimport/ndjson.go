@@ -0,0 +1,70 @@
+package goodimport
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"io"
+)
+
+const maxNDJSONLineBytes = 1 << 20
+
+type ndjsonRow struct {
+	ExternalKey string  `json:"externalKey"`
+	Name        string  `json:"name"`
+	Description *string `json:"description"`
+}
+
+// ndjsonRowReader reads one JSON object per line; blank lines are skipped.
+type ndjsonRowReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func newNDJSONRowReader(r io.Reader) *ndjsonRowReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxNDJSONLineBytes)
+
+	return &ndjsonRowReader{scanner: scanner}
+}
+
+func (r *ndjsonRowReader) Next() (*good.ImportRow, error) {
+	for r.scanner.Scan() {
+		r.line++
+
+		data := bytes.TrimSpace(r.scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		var jsonRow ndjsonRow
+		if err := json.Unmarshal(data, &jsonRow); err != nil {
+			return nil, &good.ImportRowError{Line: r.line, Reason: "invalid JSON: " + err.Error()}
+		}
+
+		row := &good.ImportRow{
+			Line:        r.line,
+			ExternalKey: domain.GoodExternalKey(jsonRow.ExternalKey),
+			Name:        domain.GoodName(jsonRow.Name),
+		}
+
+		if jsonRow.Description != nil {
+			description := domain.GoodDescription(*jsonRow.Description)
+			row.Description = &description
+		}
+
+		return row, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		if err == bufio.ErrTooLong {
+			return nil, fmt.Errorf("%w: line %d is longer than %d bytes", ErrInvalidFile, r.line+1, maxNDJSONLineBytes)
+		}
+		return nil, err
+	}
+
+	return nil, io.EOF
+}
@@ -0,0 +1,247 @@
+package goodimport
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	jobKeyPrefix  = "good_import_job_"
+	defaultJobTTL = 24 * time.Hour
+)
+
+type JobState string
+
+const (
+	JobStatePending   JobState = "pending"
+	JobStateRunning   JobState = "running"
+	JobStateCompleted JobState = "completed"
+	JobStateFailed    JobState = "failed"
+)
+
+var ErrJobNotFound = errors.New("import job not found")
+
+type Job struct {
+	Id         string             `json:"id"`
+	ProjectId  domain.ProjectId   `json:"projectId"`
+	Format     Format             `json:"format"`
+	State      JobState           `json:"state"`
+	Report     *good.ImportReport `json:"report,omitempty"`
+	Error      string             `json:"error,omitempty"`
+	CreatedBy  domain.Actor       `json:"createdBy"`
+	CreatedAt  time.Time          `json:"createdAt"`
+	FinishedAt *time.Time         `json:"finishedAt,omitempty"`
+}
+
+// Runner imports goods either right away or in background jobs whose status is kept in the job storage,
+// so that it can be queried from any replica.
+type Runner struct {
+	goodService GoodService
+	jobStorage  JobStorage
+	spoolDir    string
+	jobTTL      time.Duration
+
+	mu      sync.Mutex
+	stopped bool
+	cancels map[string]context.CancelFunc
+	jobs    sync.WaitGroup
+}
+
+func NewRunner(config *Config, goodService GoodService, jobStorage JobStorage) *Runner {
+	jobTTL := config.JobTTL
+	if jobTTL <= 0 {
+		jobTTL = defaultJobTTL
+	}
+
+	return &Runner{
+		goodService: goodService,
+		jobStorage:  jobStorage,
+		spoolDir:    config.SpoolDir,
+		jobTTL:      jobTTL,
+		cancels:     make(map[string]context.CancelFunc),
+	}
+}
+
+// Import imports the goods read from body and returns the report once done.
+func (r *Runner) Import(ctx context.Context, projectId domain.ProjectId, format Format, body io.Reader, actor domain.Actor) (*good.ImportReport, error) {
+	rows, err := NewRowReader(format, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.goodService.Import(ctx, projectId, rows, actor)
+}
+
+// Submit saves body to the spool dir and imports it in a background job.
+// The upload is read completely before the job is created, so a truncated upload never gets imported partially.
+func (r *Runner) Submit(ctx context.Context, projectId domain.ProjectId, format Format, body io.Reader, actor domain.Actor) (*Job, error) {
+	if format != FormatCSV && format != FormatNDJSON {
+		return nil, ErrUnsupportedFormat
+	}
+
+	jobId, err := newJobId()
+	if err != nil {
+		return nil, err
+	}
+
+	spoolFile, err := os.CreateTemp(r.spoolDir, "good-import-"+jobId+"-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create import spool file: %w", err)
+	}
+
+	_, err = io.Copy(spoolFile, body)
+	if closeErr := spoolFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(spoolFile.Name())
+		return nil, err
+	}
+
+	job := &Job{
+		Id:        jobId,
+		ProjectId: projectId,
+		Format:    format,
+		State:     JobStatePending,
+		CreatedBy: actor,
+		CreatedAt: time.Now(),
+	}
+
+	if err = r.saveJob(ctx, job); err != nil {
+		os.Remove(spoolFile.Name())
+		return nil, err
+	}
+
+	// The job keeps the trace and the request id of the upload, but not its deadline.
+	jobCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+
+	r.mu.Lock()
+	if r.stopped {
+		r.mu.Unlock()
+		cancel()
+		os.Remove(spoolFile.Name())
+		return nil, errors.New("import runner is stopped")
+	}
+	r.cancels[jobId] = cancel
+	r.jobs.Add(1)
+	r.mu.Unlock()
+
+	go r.run(jobCtx, job, spoolFile.Name())
+
+	return job, nil
+}
+
+// Job returns the current status of the job.
+func (r *Runner) Job(ctx context.Context, jobId string) (*Job, error) {
+	storedJob, err := r.jobStorage.Get(ctx, jobKeyPrefix+jobId)
+	if err != nil {
+		if errors.Is(err, storage.ErrRedisKeyNotFound) {
+			return nil, ErrJobNotFound
+		}
+		return nil, err
+	}
+
+	var job Job
+	if err = json.Unmarshal([]byte(storedJob), &job); err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// Stop interrupts the running jobs, which are marked as failed, and waits for them to exit or ctx to be done.
+func (r *Runner) Stop(ctx context.Context) error {
+	r.mu.Lock()
+	r.stopped = true
+	for _, cancel := range r.cancels {
+		cancel()
+	}
+	r.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		r.jobs.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("import jobs did not finish: %w", ctx.Err())
+	}
+}
+
+func (r *Runner) run(ctx context.Context, job *Job, spoolPath string) {
+	defer r.jobs.Done()
+	defer func() {
+		r.mu.Lock()
+		r.cancels[job.Id]()
+		delete(r.cancels, job.Id)
+		r.mu.Unlock()
+	}()
+	defer os.Remove(spoolPath)
+
+	job.State = JobStateRunning
+	if err := r.saveJob(ctx, job); err != nil {
+		slog.ErrorContext(ctx, "failed to save import job", "jobId", job.Id, "error", err)
+	}
+
+	report, err := r.importFile(ctx, job, spoolPath)
+
+	finishedAt := time.Now()
+	job.FinishedAt = &finishedAt
+	job.Report = report
+
+	if err != nil {
+		job.State = JobStateFailed
+		job.Error = err.Error()
+		slog.ErrorContext(ctx, "import job failed", "jobId", job.Id, "projectId", job.ProjectId, "error", err)
+	} else {
+		job.State = JobStateCompleted
+		slog.InfoContext(ctx, "import job completed", "jobId", job.Id, "projectId", job.ProjectId, "created", report.Created, "updated", report.Updated, "failed", report.Failed)
+	}
+
+	// The job status must be saved even if the job was interrupted.
+	if err = r.saveJob(context.WithoutCancel(ctx), job); err != nil {
+		slog.ErrorContext(ctx, "failed to save import job", "jobId", job.Id, "error", err)
+	}
+}
+
+func (r *Runner) importFile(ctx context.Context, job *Job, spoolPath string) (*good.ImportReport, error) {
+	spoolFile, err := os.Open(spoolPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open import spool file: %w", err)
+	}
+	defer spoolFile.Close()
+
+	return r.Import(ctx, job.ProjectId, job.Format, spoolFile, job.CreatedBy)
+}
+
+func (r *Runner) saveJob(ctx context.Context, job *Job) error {
+	jobBytes, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return r.jobStorage.Set(ctx, jobKeyPrefix+job.Id, string(jobBytes), r.jobTTL)
+}
+
+func newJobId() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("failed to generate import job id: %w", err)
+	}
+	return hex.EncodeToString(id), nil
+}
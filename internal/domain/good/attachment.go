@@ -0,0 +1,21 @@
+package good
+
+import (
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"time"
+)
+
+// Attachment is a file attached to a good. Its content is kept in the blob store under StorageKey.
+type Attachment struct {
+	Id          domain.AttachmentId
+	GoodId      domain.GoodId
+	ProjectId   domain.ProjectId
+	Name        string
+	ContentType string
+	Size        int64
+	// Checksum is the hex encoded SHA-256 of the content.
+	Checksum   string
+	StorageKey string
+	CreatedAt  time.Time
+	CreatedBy  domain.Actor
+}
@@ -0,0 +1,256 @@
+package good
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	maxAttachmentNameLength = 255
+	blobDeletionBatchSize   = 100
+)
+
+var (
+	ErrAttachmentNotFound = errors.New("attachment not found")
+	ErrInvalidAttachment  = errors.New("invalid attachment")
+)
+
+// AddAttachment stores size bytes of content as a new attachment of the good. The content is written
+// to the blob store before the attachment is recorded, so that no database transaction is held open
+// during the upload; the blob is deleted again if the attachment cannot be recorded.
+// Attachments are part of the good's data, so a removed good cannot get new ones.
+func (g *goodServiceImpl) AddAttachment(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, name, contentType string, size int64, content io.Reader, actor domain.Actor) (*Attachment, error) {
+	ctx, span := tracer.Start(ctx, "GoodService.AddAttachment")
+	defer span.End()
+
+	if err := validateAttachmentName(name); err != nil {
+		return nil, err
+	}
+	if size <= 0 {
+		return nil, fmt.Errorf("%w: attachment must not be empty", ErrInvalidAttachment)
+	}
+
+	// Fail fast instead of uploading the content of a good that cannot be attached to.
+	domainGood, err := g.goodStorage.Get(ctx, id, projectId)
+	if err != nil {
+		if errors.Is(err, storage.ErrPostgresGoodNotFound) {
+			return nil, ErrGoodNotFound
+		}
+		return nil, err
+	}
+	if err = rejectRemoved(domainGood); err != nil {
+		return nil, err
+	}
+
+	storageKey, err := newAttachmentStorageKey(id, projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	checksum := sha256.New()
+	counter := &byteCounter{}
+
+	err = g.blobStore.Put(ctx, storageKey, io.TeeReader(content, io.MultiWriter(checksum, counter)), size, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	if counter.n != size {
+		g.deleteBlob(ctx, storageKey)
+		return nil, fmt.Errorf("%w: attachment has %d bytes instead of %d", ErrInvalidAttachment, counter.n, size)
+	}
+
+	attachment, err := g.goodStorage.CreateAttachment(ctx, &Attachment{
+		GoodId:      id,
+		ProjectId:   projectId,
+		Name:        name,
+		ContentType: contentType,
+		Size:        size,
+		Checksum:    hex.EncodeToString(checksum.Sum(nil)),
+		StorageKey:  storageKey,
+	}, actor)
+	if err != nil {
+		g.deleteBlob(ctx, storageKey)
+		return nil, g.mapWriteError(ctx, id, projectId, err)
+	}
+
+	return attachment, nil
+}
+
+func (g *goodServiceImpl) ListAttachments(ctx context.Context, id domain.GoodId, projectId domain.ProjectId) ([]*Attachment, error) {
+	ctx, span := tracer.Start(ctx, "GoodService.ListAttachments")
+	defer span.End()
+
+	attachments, err := g.goodStorage.ListAttachments(ctx, id, projectId)
+	if err != nil {
+		if errors.Is(err, storage.ErrPostgresGoodNotFound) {
+			return nil, ErrGoodNotFound
+		}
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+// OpenAttachment returns the attachment along with its content, which the caller must close.
+func (g *goodServiceImpl) OpenAttachment(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, attachmentId domain.AttachmentId) (*Attachment, io.ReadCloser, error) {
+	ctx, span := tracer.Start(ctx, "GoodService.OpenAttachment")
+	defer span.End()
+
+	attachment, err := g.goodStorage.GetAttachment(ctx, id, projectId, attachmentId)
+	if err != nil {
+		if errors.Is(err, storage.ErrPostgresAttachmentNotFound) {
+			return nil, nil, ErrAttachmentNotFound
+		}
+		return nil, nil, err
+	}
+
+	content, err := g.blobStore.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		// The attachment has been deleted while it was being opened.
+		if errors.Is(err, storage.ErrBlobNotFound) {
+			return nil, nil, ErrAttachmentNotFound
+		}
+		return nil, nil, err
+	}
+
+	return attachment, content, nil
+}
+
+// RemoveAttachment deletes the attachment and then its blob, unless copies of the attachment still share it.
+// A blob that cannot be deleted right away stays queued and is deleted later by DeleteQueuedBlobs.
+func (g *goodServiceImpl) RemoveAttachment(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, attachmentId domain.AttachmentId) (*Attachment, error) {
+	ctx, span := tracer.Start(ctx, "GoodService.RemoveAttachment")
+	defer span.End()
+
+	attachment, queued, err := g.goodStorage.DeleteAttachment(ctx, id, projectId, attachmentId)
+	if err != nil {
+		if errors.Is(err, storage.ErrPostgresAttachmentNotFound) {
+			return nil, ErrAttachmentNotFound
+		}
+		return nil, g.mapWriteError(ctx, id, projectId, err)
+	}
+
+	if !queued {
+		return attachment, nil
+	}
+
+	if err = g.blobStore.Delete(ctx, attachment.StorageKey); err != nil {
+		slog.WarnContext(ctx, "failed to delete attachment blob, it stays queued", "attachmentId", attachment.Id.Int64(), "error", err)
+		return attachment, nil
+	}
+
+	if err = g.goodStorage.CompleteBlobDeletions(ctx, []string{attachment.StorageKey}); err != nil {
+		slog.ErrorContext(ctx, "failed to dequeue deleted attachment blob", "attachmentId", attachment.Id.Int64(), "error", err)
+	}
+
+	return attachment, nil
+}
+
+// DeleteQueuedBlobs deletes the blobs of deleted attachments, including those of purged goods, in batches
+// and returns how many blobs were deleted. Deleting a blob twice is harmless, so replicas may run it concurrently.
+func (g *goodServiceImpl) DeleteQueuedBlobs(ctx context.Context, batchSize int) (int, error) {
+	ctx, span := tracer.Start(ctx, "GoodService.DeleteQueuedBlobs")
+	defer span.End()
+
+	var deleted int
+
+	for {
+		batchDeleted, err := g.deleteQueuedBlobBatch(ctx, batchSize)
+		deleted += batchDeleted
+		if err != nil {
+			return deleted, err
+		}
+
+		if batchDeleted < batchSize {
+			return deleted, nil
+		}
+	}
+}
+
+func (g *goodServiceImpl) deleteQueuedBlobBatch(ctx context.Context, batchSize int) (int, error) {
+	storageKeys, err := g.goodStorage.QueuedBlobDeletions(ctx, batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var deletedKeys []string
+
+	for _, storageKey := range storageKeys {
+		if err = g.blobStore.Delete(ctx, storageKey); err != nil {
+			break
+		}
+		deletedKeys = append(deletedKeys, storageKey)
+	}
+
+	if len(deletedKeys) > 0 {
+		if completeErr := g.goodStorage.CompleteBlobDeletions(ctx, deletedKeys); completeErr != nil {
+			return 0, completeErr
+		}
+	}
+
+	return len(deletedKeys), err
+}
+
+// tryDeleteQueuedBlobs deletes a batch of queued blobs right after a write queued some. It is best effort:
+// the blobs that cannot be deleted stay queued for DeleteQueuedBlobs.
+func (g *goodServiceImpl) tryDeleteQueuedBlobs(ctx context.Context) {
+	deleted, err := g.deleteQueuedBlobBatch(ctx, blobDeletionBatchSize)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to delete attachment blobs, they stay queued", "deleted", deleted, "error", err)
+	}
+}
+
+// deleteBlob deletes a blob that was never recorded as an attachment. It is best effort: the request
+// has failed already and a leftover blob is not referenced by anything.
+func (g *goodServiceImpl) deleteBlob(ctx context.Context, storageKey string) {
+	_ = g.blobStore.Delete(context.WithoutCancel(ctx), storageKey)
+}
+
+func validateAttachmentName(name string) error {
+	switch {
+	case strings.TrimSpace(name) == "":
+		return fmt.Errorf("%w: name is required", ErrInvalidAttachment)
+	case utf8.RuneCountInString(name) > maxAttachmentNameLength:
+		return fmt.Errorf("%w: name is longer than %d characters", ErrInvalidAttachment, maxAttachmentNameLength)
+	case !utf8.ValidString(name):
+		return fmt.Errorf("%w: name must be valid UTF-8", ErrInvalidAttachment)
+	case strings.ContainsAny(name, `/\`):
+		return fmt.Errorf("%w: name must not contain path separators", ErrInvalidAttachment)
+	case strings.ContainsFunc(name, unicode.IsControl):
+		return fmt.Errorf("%w: name must not contain control characters", ErrInvalidAttachment)
+	}
+
+	return nil
+}
+
+// newAttachmentStorageKey returns a fresh key under the prefix of the good, so that a key is never reused.
+func newAttachmentStorageKey(id domain.GoodId, projectId domain.ProjectId) (string, error) {
+	suffix := make([]byte, 16)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("failed to generate attachment storage key: %w", err)
+	}
+
+	return "projects/" + strconv.FormatInt(projectId.Int64(), 10) + "/goods/" + strconv.Itoa(int(id)) + "/" + hex.EncodeToString(suffix), nil
+}
+
+type byteCounter struct {
+	n int64
+}
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	c.n += int64(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,215 @@
+package good
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// fakeAttachmentStorage keeps goods and their attachments and queues blob deletions like the Postgres storage:
+// copies share the storage key of the original, and a key is queued once no attachment refers to it anymore.
+type fakeAttachmentStorage struct {
+	GoodStorage
+
+	goods        map[domain.GoodId]*Good
+	attachments  map[domain.AttachmentId]*Attachment
+	blobQueue    map[string]struct{}
+	nextGoodId   domain.GoodId
+	nextAttachId domain.AttachmentId
+}
+
+func newFakeAttachmentStorage() *fakeAttachmentStorage {
+	return &fakeAttachmentStorage{
+		goods:        make(map[domain.GoodId]*Good),
+		attachments:  make(map[domain.AttachmentId]*Attachment),
+		blobQueue:    make(map[string]struct{}),
+		nextGoodId:   1,
+		nextAttachId: 1,
+	}
+}
+
+func (s *fakeAttachmentStorage) addGood(projectId domain.ProjectId) *Good {
+	good := &Good{Id: s.nextGoodId, ProjectId: projectId, Name: "name", Version: 1}
+	s.goods[good.Id] = good
+	s.nextGoodId++
+	return good
+}
+
+func (s *fakeAttachmentStorage) Get(ctx context.Context, id domain.GoodId, projectId domain.ProjectId) (*Good, error) {
+	good, ok := s.goods[id]
+	if !ok || good.ProjectId != projectId {
+		return nil, storage.ErrPostgresGoodNotFound
+	}
+	copied := *good
+	return &copied, nil
+}
+
+func (s *fakeAttachmentStorage) ListVariants(ctx context.Context, parentIds []domain.GoodId) ([]*Good, error) {
+	return nil, nil
+}
+
+func (s *fakeAttachmentStorage) GetAttributeSchema(ctx context.Context, projectId domain.ProjectId) (*AttributeSchema, error) {
+	return nil, storage.ErrPostgresAttributeSchemaNotFound
+}
+
+func (s *fakeAttachmentStorage) Copy(ctx context.Context, id domain.GoodId, projectId, targetProjectId domain.ProjectId, options *TransferOptions, actor domain.Actor) (*Good, error) {
+	copied := s.addGood(targetProjectId)
+
+	if options.Attachments {
+		for _, attachment := range s.attachmentsOf(id) {
+			copiedAttachment := *attachment
+			copiedAttachment.Id = s.nextAttachId
+			copiedAttachment.GoodId = copied.Id
+			copiedAttachment.ProjectId = targetProjectId
+			s.attachments[copiedAttachment.Id] = &copiedAttachment
+			s.nextAttachId++
+		}
+	}
+
+	return copied, nil
+}
+
+func (s *fakeAttachmentStorage) CreateAttachment(ctx context.Context, attachment *Attachment, actor domain.Actor) (*Attachment, error) {
+	created := *attachment
+	created.Id = s.nextAttachId
+	s.attachments[created.Id] = &created
+	s.nextAttachId++
+	return &created, nil
+}
+
+func (s *fakeAttachmentStorage) GetAttachment(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, attachmentId domain.AttachmentId) (*Attachment, error) {
+	attachment, ok := s.attachments[attachmentId]
+	if !ok || attachment.GoodId != id || attachment.ProjectId != projectId {
+		return nil, storage.ErrPostgresAttachmentNotFound
+	}
+	return attachment, nil
+}
+
+func (s *fakeAttachmentStorage) DeleteAttachment(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, attachmentId domain.AttachmentId) (*Attachment, bool, error) {
+	attachment, err := s.GetAttachment(ctx, id, projectId, attachmentId)
+	if err != nil {
+		return nil, false, err
+	}
+	delete(s.attachments, attachmentId)
+
+	for _, other := range s.attachments {
+		if other.StorageKey == attachment.StorageKey {
+			return attachment, false, nil
+		}
+	}
+	s.blobQueue[attachment.StorageKey] = struct{}{}
+
+	return attachment, true, nil
+}
+
+func (s *fakeAttachmentStorage) QueuedBlobDeletions(ctx context.Context, limit int) ([]string, error) {
+	var storageKeys []string
+	for storageKey := range s.blobQueue {
+		storageKeys = append(storageKeys, storageKey)
+	}
+	sort.Strings(storageKeys)
+	if len(storageKeys) > limit {
+		storageKeys = storageKeys[:limit]
+	}
+	return storageKeys, nil
+}
+
+func (s *fakeAttachmentStorage) CompleteBlobDeletions(ctx context.Context, storageKeys []string) error {
+	for _, storageKey := range storageKeys {
+		delete(s.blobQueue, storageKey)
+	}
+	return nil
+}
+
+func (s *fakeAttachmentStorage) attachmentsOf(id domain.GoodId) []*Attachment {
+	var attachments []*Attachment
+	for _, attachment := range s.attachments {
+		if attachment.GoodId == id {
+			attachments = append(attachments, attachment)
+		}
+	}
+	return attachments
+}
+
+type fakeBlobStore struct {
+	blobs map[string][]byte
+}
+
+func (b *fakeBlobStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	b.blobs[key] = content
+	return nil
+}
+
+func (b *fakeBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	content, ok := b.blobs[key]
+	if !ok {
+		return nil, storage.ErrBlobNotFound
+	}
+	return io.NopCloser(bytes.NewReader(content)), nil
+}
+
+func (b *fakeBlobStore) Delete(ctx context.Context, key string) error {
+	delete(b.blobs, key)
+	return nil
+}
+
+func TestRemoveAttachmentKeepsBlobsSharedWithCopies(t *testing.T) {
+	ctx := context.Background()
+
+	goodStorage := newFakeAttachmentStorage()
+	blobStore := &fakeBlobStore{blobs: make(map[string][]byte)}
+	goodService := NewGoodService(goodStorage, fakeInMemoryStorage{}, blobStore)
+
+	original := goodStorage.addGood(1)
+
+	attachment, err := goodService.AddAttachment(ctx, original.Id, original.ProjectId, "photo.txt", "text/plain", 5, strings.NewReader("photo"), "tester")
+	if err != nil {
+		t.Fatalf("add attachment: %v", err)
+	}
+
+	copied, err := goodService.Copy(ctx, original.Id, original.ProjectId, 2, &TransferOptions{Attachments: true}, "tester")
+	if err != nil {
+		t.Fatalf("copy: %v", err)
+	}
+
+	copiedAttachments := goodStorage.attachmentsOf(copied.Id)
+	if len(copiedAttachments) != 1 || copiedAttachments[0].StorageKey != attachment.StorageKey {
+		t.Fatalf("copy does not share the blob of the original attachment: %+v", copiedAttachments)
+	}
+
+	if _, err = goodService.RemoveAttachment(ctx, original.Id, original.ProjectId, attachment.Id); err != nil {
+		t.Fatalf("remove original attachment: %v", err)
+	}
+
+	_, content, err := goodService.OpenAttachment(ctx, copied.Id, copied.ProjectId, copiedAttachments[0].Id)
+	if err != nil {
+		t.Fatalf("open copied attachment after removing the original: %v", err)
+	}
+	content.Close()
+
+	if _, err = goodService.RemoveAttachment(ctx, copied.Id, copied.ProjectId, copiedAttachments[0].Id); err != nil {
+		t.Fatalf("remove copied attachment: %v", err)
+	}
+
+	if _, ok := blobStore.blobs[attachment.StorageKey]; ok {
+		t.Error("blob is kept after its last attachment was removed")
+	}
+	if len(goodStorage.blobQueue) != 0 {
+		t.Errorf("got %d queued blob deletions, want none", len(goodStorage.blobQueue))
+	}
+
+	_, _, err = goodService.OpenAttachment(ctx, copied.Id, copied.ProjectId, copiedAttachments[0].Id)
+	if !errors.Is(err, ErrAttachmentNotFound) {
+		t.Errorf("got error %v, want %v", err, ErrAttachmentNotFound)
+	}
+}
@@ -0,0 +1,15 @@
+package good
+
+import (
+	"encoding/json"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"time"
+)
+
+// AttributeSchema is the JSON Schema the attributes of the goods of a project must satisfy.
+type AttributeSchema struct {
+	ProjectId domain.ProjectId
+	Schema    json.RawMessage
+	UpdatedAt time.Time
+	UpdatedBy domain.Actor
+}
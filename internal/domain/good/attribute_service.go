@@ -0,0 +1,232 @@
+package good
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"github.com/vaberof/hezzl-backend/pkg/metrics"
+	"io"
+	"strconv"
+	"time"
+)
+
+const attributeSchemaKey = "attribute_schema_"
+
+const attributeSchemaCacheExpireTime = 1 * time.Minute
+
+const attributeSchemaCacheName = "attribute_schema"
+
+// noAttributeSchema is cached for the projects without a schema.
+const noAttributeSchema = "null"
+
+const attributeSchemaURL = "mem:///attributes.json"
+
+var (
+	ErrInvalidAttributes        = errors.New("invalid attributes")
+	ErrInvalidAttributeSchema   = errors.New("invalid attribute schema")
+	ErrAttributeSchemaNotFound  = errors.New("attribute schema not found")
+	errAttributeSchemaReference = errors.New("external references are not allowed")
+)
+
+func (g *goodServiceImpl) GetAttributeSchema(ctx context.Context, projectId domain.ProjectId) (*AttributeSchema, error) {
+	ctx, span := tracer.Start(ctx, "GoodService.GetAttributeSchema")
+	defer span.End()
+
+	attributeSchema, err := g.goodStorage.GetAttributeSchema(ctx, projectId)
+	if err != nil {
+		if errors.Is(err, storage.ErrPostgresAttributeSchemaNotFound) {
+			return nil, ErrAttributeSchemaNotFound
+		}
+		return nil, err
+	}
+
+	return attributeSchema, nil
+}
+
+// SetAttributeSchema replaces the attribute schema of the project. Goods created before are not revalidated;
+// the schema applies to the attributes written from now on.
+func (g *goodServiceImpl) SetAttributeSchema(ctx context.Context, projectId domain.ProjectId, schema json.RawMessage, actor domain.Actor) (*AttributeSchema, error) {
+	ctx, span := tracer.Start(ctx, "GoodService.SetAttributeSchema")
+	defer span.End()
+
+	if _, err := compileAttributeSchema(schema); err != nil {
+		return nil, err
+	}
+
+	attributeSchema, err := g.goodStorage.SetAttributeSchema(ctx, projectId, schema, actor)
+	if err != nil {
+		if errors.Is(err, storage.ErrPostgresProjectNotFound) {
+			return nil, ErrProjectNotFound
+		}
+		return nil, err
+	}
+
+	if err = g.deleteCachedAttributeSchema(ctx, projectId); err != nil {
+		return nil, err
+	}
+
+	return attributeSchema, nil
+}
+
+func (g *goodServiceImpl) DeleteAttributeSchema(ctx context.Context, projectId domain.ProjectId) error {
+	ctx, span := tracer.Start(ctx, "GoodService.DeleteAttributeSchema")
+	defer span.End()
+
+	err := g.goodStorage.DeleteAttributeSchema(ctx, projectId)
+	if err != nil {
+		if errors.Is(err, storage.ErrPostgresAttributeSchemaNotFound) {
+			return ErrAttributeSchemaNotFound
+		}
+		return err
+	}
+
+	return g.deleteCachedAttributeSchema(ctx, projectId)
+}
+
+// validateAttributes checks the attributes against the schema of the project, if the project has one.
+func (g *goodServiceImpl) validateAttributes(ctx context.Context, projectId domain.ProjectId, attributes domain.GoodAttributes) error {
+	schema, err := g.getProjectAttributeSchema(ctx, projectId)
+	if err != nil || schema == nil {
+		return err
+	}
+
+	return validateAttributes(schema, attributes)
+}
+
+// attributeSchemas resolves the attribute schema of each project at most once, for the calls that validate
+// the attributes of many goods, so that the schema is neither read nor compiled per good.
+type attributeSchemas struct {
+	goodService *goodServiceImpl
+	schemas     map[domain.ProjectId]*jsonschema.Schema
+}
+
+func (g *goodServiceImpl) newAttributeSchemas() *attributeSchemas {
+	return &attributeSchemas{goodService: g, schemas: make(map[domain.ProjectId]*jsonschema.Schema)}
+}
+
+// validate is goodServiceImpl.validateAttributes with the schema of the project resolved on first use.
+func (s *attributeSchemas) validate(ctx context.Context, projectId domain.ProjectId, attributes domain.GoodAttributes) error {
+	schema, ok := s.schemas[projectId]
+	if !ok {
+		var err error
+
+		schema, err = s.goodService.getProjectAttributeSchema(ctx, projectId)
+		if err != nil {
+			return err
+		}
+		s.schemas[projectId] = schema
+	}
+
+	if schema == nil {
+		return nil
+	}
+
+	return validateAttributes(schema, attributes)
+}
+
+// getProjectAttributeSchema returns the compiled attribute schema of the project or nil if the project has none.
+func (g *goodServiceImpl) getProjectAttributeSchema(ctx context.Context, projectId domain.ProjectId) (*jsonschema.Schema, error) {
+	attributeSchemaCacheKey := g.getAttributeSchemaCacheKey(projectId)
+
+	rawSchema, err := g.inMemoryStorage.Get(ctx, attributeSchemaCacheKey)
+	switch {
+	case err == nil:
+		metrics.CacheRequestsTotal.WithLabelValues(attributeSchemaCacheName, metrics.CacheResultHit).Inc()
+	case errors.Is(err, storage.ErrRedisKeyNotFound):
+		metrics.CacheRequestsTotal.WithLabelValues(attributeSchemaCacheName, metrics.CacheResultMiss).Inc()
+
+		rawSchema = noAttributeSchema
+
+		attributeSchema, err := g.goodStorage.GetAttributeSchema(ctx, projectId)
+		if err != nil && !errors.Is(err, storage.ErrPostgresAttributeSchemaNotFound) {
+			return nil, err
+		}
+		if attributeSchema != nil {
+			rawSchema = string(attributeSchema.Schema)
+		}
+
+		err = g.inMemoryStorage.Set(ctx, attributeSchemaCacheKey, rawSchema, attributeSchemaCacheExpireTime)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	if rawSchema == noAttributeSchema {
+		return nil, nil
+	}
+
+	return compileAttributeSchema(json.RawMessage(rawSchema))
+}
+
+func (g *goodServiceImpl) deleteCachedAttributeSchema(ctx context.Context, projectId domain.ProjectId) error {
+	err := g.inMemoryStorage.Delete(ctx, g.getAttributeSchemaCacheKey(projectId))
+	if err != nil && !errors.Is(err, storage.ErrRedisKeyNotFound) {
+		return err
+	}
+	return nil
+}
+
+func (g *goodServiceImpl) getAttributeSchemaCacheKey(projectId domain.ProjectId) string {
+	return attributeSchemaKey + strconv.FormatInt(projectId.Int64(), 10)
+}
+
+// compileAttributeSchema compiles a JSON Schema of draft 2020-12 unless the schema declares another draft.
+// The schema must be self-contained: references to other documents are refused rather than fetched.
+func compileAttributeSchema(schema json.RawMessage) (*jsonschema.Schema, error) {
+	compiler := jsonschema.NewCompiler()
+	compiler.Draft = jsonschema.Draft2020
+	compiler.LoadURL = func(url string) (io.ReadCloser, error) {
+		return nil, fmt.Errorf("%w: %s", errAttributeSchemaReference, url)
+	}
+
+	if err := compiler.AddResource(attributeSchemaURL, bytes.NewReader(schema)); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidAttributeSchema, err)
+	}
+
+	compiledSchema, err := compiler.Compile(attributeSchemaURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidAttributeSchema, err)
+	}
+
+	return compiledSchema, nil
+}
+
+// validateAttributes validates the attributes against the schema; nil attributes are validated as an empty object.
+func validateAttributes(schema *jsonschema.Schema, attributes domain.GoodAttributes) error {
+	if attributes == nil {
+		attributes = domain.GoodAttributes{}
+	}
+
+	err := schema.Validate(map[string]any(attributes))
+	if err == nil {
+		return nil
+	}
+
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return err
+	}
+
+	return fmt.Errorf("%w: %s", ErrInvalidAttributes, describeValidationError(validationErr))
+}
+
+// describeValidationError describes the first leaf error of the validation, which names the offending attribute.
+func describeValidationError(validationErr *jsonschema.ValidationError) string {
+	for len(validationErr.Causes) > 0 {
+		validationErr = validationErr.Causes[0]
+	}
+
+	location := validationErr.InstanceLocation
+	if location == "" {
+		location = "/"
+	}
+
+	return location + ": " + validationErr.Message
+}
@@ -0,0 +1,33 @@
+package good
+
+import "github.com/vaberof/hezzl-backend/pkg/domain"
+
+type CreateItem struct {
+	ProjectId  domain.ProjectId
+	Name       domain.GoodName
+	Attributes domain.GoodAttributes
+}
+
+type UpdateItem struct {
+	Id          domain.GoodId
+	ProjectId   domain.ProjectId
+	Name        domain.GoodName
+	Description *domain.GoodDescription
+	// Attributes replace the attributes of the good; nil keeps them.
+	Attributes domain.GoodAttributes
+	// Version is the version the update is conditioned on, if any.
+	Version *domain.GoodVersion
+}
+
+type RemoveItem struct {
+	Id        domain.GoodId
+	ProjectId domain.ProjectId
+	// Version is the version the removal is conditioned on, if any.
+	Version *domain.GoodVersion
+}
+
+// BatchResult is the outcome of a single item of a batch: either the written good or the reason it was skipped.
+type BatchResult struct {
+	Good *Good
+	Err  error
+}
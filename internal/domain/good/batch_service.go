@@ -0,0 +1,223 @@
+package good
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+)
+
+var ErrBatchDuplicateGood = errors.New("good occurs in the batch more than once")
+
+// CreateBatch creates either all goods of the batch or none, so a single item with invalid attributes rejects the batch.
+func (g *goodServiceImpl) CreateBatch(ctx context.Context, items []*CreateItem, actor domain.Actor) ([]*Good, error) {
+	ctx, span := tracer.Start(ctx, "GoodService.CreateBatch")
+	defer span.End()
+
+	attributeSchemas := g.newAttributeSchemas()
+
+	for i, item := range items {
+		if err := attributeSchemas.validate(ctx, item.ProjectId, item.Attributes); err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+	}
+
+	return g.goodStorage.CreateBatch(ctx, items, actor)
+}
+
+// UpdateBatch updates the goods of the batch in a single transaction following the same lifecycle
+// rules as Update. Rejected items are reported in their results and do not prevent the others from being updated.
+func (g *goodServiceImpl) UpdateBatch(ctx context.Context, items []*UpdateItem, actor domain.Actor) ([]*BatchResult, error) {
+	ctx, span := tracer.Start(ctx, "GoodService.UpdateBatch")
+	defer span.End()
+
+	keys := make([]batchKey, len(items))
+	for i, item := range items {
+		keys[i] = batchKey{id: item.Id, projectId: item.ProjectId}
+	}
+
+	currentGoods, results, err := g.loadBatch(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		acceptedItems   []*UpdateItem
+		acceptedIndexes []int
+	)
+
+	attributeSchemas := g.newAttributeSchemas()
+
+	for i, item := range items {
+		if results[i] != nil {
+			continue
+		}
+		if item.Attributes != nil {
+			if err = attributeSchemas.validate(ctx, item.ProjectId, item.Attributes); err != nil {
+				if !errors.Is(err, ErrInvalidAttributes) {
+					return nil, err
+				}
+				results[i] = &BatchResult{Err: err}
+				continue
+			}
+		}
+
+		acceptedItem := *item
+		if acceptedItem.Version == nil {
+			acceptedItem.Version = &currentGoods[i].Version
+		}
+
+		acceptedItems = append(acceptedItems, &acceptedItem)
+		acceptedIndexes = append(acceptedIndexes, i)
+	}
+
+	if len(acceptedItems) > 0 {
+		storageResults, err := g.goodStorage.UpdateBatch(ctx, acceptedItems, actor)
+		if err != nil {
+			return nil, err
+		}
+		g.mergeBatchResults(results, acceptedIndexes, storageResults)
+	}
+
+	if err = g.deleteBatchFromCache(ctx, keys); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// DeleteBatch removes the goods of the batch in a single transaction following the same lifecycle
+// rules as Delete. Rejected items are reported in their results and do not prevent the others from being removed.
+func (g *goodServiceImpl) DeleteBatch(ctx context.Context, items []*RemoveItem, actor domain.Actor) ([]*BatchResult, error) {
+	ctx, span := tracer.Start(ctx, "GoodService.DeleteBatch")
+	defer span.End()
+
+	keys := make([]batchKey, len(items))
+	for i, item := range items {
+		keys[i] = batchKey{id: item.Id, projectId: item.ProjectId}
+	}
+
+	currentGoods, results, err := g.loadBatch(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		acceptedItems   []*RemoveItem
+		acceptedIndexes []int
+	)
+	for i, item := range items {
+		if results[i] != nil {
+			continue
+		}
+
+		acceptedItem := *item
+		if acceptedItem.Version == nil {
+			acceptedItem.Version = &currentGoods[i].Version
+		}
+
+		acceptedItems = append(acceptedItems, &acceptedItem)
+		acceptedIndexes = append(acceptedIndexes, i)
+	}
+
+	if len(acceptedItems) > 0 {
+		storageResults, err := g.goodStorage.DeleteBatch(ctx, acceptedItems, actor)
+		if err != nil {
+			return nil, err
+		}
+		g.mergeBatchResults(results, acceptedIndexes, storageResults)
+	}
+
+	// The variants removed along with their parents are cached on their own.
+	for _, result := range results {
+		if result.Good == nil {
+			continue
+		}
+		for _, variant := range result.Good.Variants {
+			keys = append(keys, batchKey{id: variant.Id, projectId: variant.ProjectId})
+		}
+	}
+
+	if err = g.deleteBatchFromCache(ctx, keys); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+type batchKey struct {
+	id        domain.GoodId
+	projectId domain.ProjectId
+}
+
+// loadBatch returns the current state of the goods of the batch along with the results
+// of the items rejected by the lifecycle rules; the results of the accepted items are nil.
+func (g *goodServiceImpl) loadBatch(ctx context.Context, keys []batchKey) ([]*Good, []*BatchResult, error) {
+	ids := make([]domain.GoodId, len(keys))
+	seenIds := make(map[domain.GoodId]struct{}, len(keys))
+	for i, key := range keys {
+		if _, ok := seenIds[key.id]; ok {
+			return nil, nil, ErrBatchDuplicateGood
+		}
+		seenIds[key.id] = struct{}{}
+		ids[i] = key.id
+	}
+
+	domainGoods, err := g.goodStorage.GetBatch(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	domainGoodsById := make(map[domain.GoodId]*Good, len(domainGoods))
+	for _, domainGood := range domainGoods {
+		domainGoodsById[domainGood.Id] = domainGood
+	}
+
+	currentGoods := make([]*Good, len(keys))
+	results := make([]*BatchResult, len(keys))
+
+	for i, key := range keys {
+		domainGood, ok := domainGoodsById[key.id]
+		if !ok || domainGood.ProjectId != key.projectId {
+			results[i] = &BatchResult{Err: ErrGoodNotFound}
+			continue
+		}
+		if err = rejectRemoved(domainGood); err != nil {
+			results[i] = &BatchResult{Err: err}
+			continue
+		}
+		currentGoods[i] = domainGood
+	}
+
+	return currentGoods, results, nil
+}
+
+func (g *goodServiceImpl) mergeBatchResults(results []*BatchResult, indexes []int, storageResults []*BatchResult) {
+	for j, storageResult := range storageResults {
+		switch {
+		case errors.Is(storageResult.Err, storage.ErrPostgresGoodNotFound):
+			storageResult.Err = ErrGoodNotFound
+		case errors.Is(storageResult.Err, storage.ErrPostgresGoodVersionMismatch):
+			storageResult.Err = ErrGoodVersionMismatch
+		}
+		results[indexes[j]] = storageResult
+	}
+}
+
+func (g *goodServiceImpl) deleteBatchFromCache(ctx context.Context, keys []batchKey) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	goodCacheKeys := make([]string, len(keys))
+	for i, key := range keys {
+		goodCacheKeys[i] = g.getGoodCacheKey(key.id, key.projectId)
+	}
+
+	err := g.inMemoryStorage.Delete(ctx, goodCacheKeys...)
+	if err != nil && !errors.Is(err, storage.ErrRedisKeyNotFound) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package good
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"testing"
+)
+
+// fakeSchemaStorage serves the attribute schema of every project and counts how often it is read.
+type fakeSchemaStorage struct {
+	GoodStorage
+
+	schema      json.RawMessage
+	schemaReads map[domain.ProjectId]int
+}
+
+func (s *fakeSchemaStorage) GetAttributeSchema(ctx context.Context, projectId domain.ProjectId) (*AttributeSchema, error) {
+	s.schemaReads[projectId]++
+	return &AttributeSchema{ProjectId: projectId, Schema: s.schema}, nil
+}
+
+func (s *fakeSchemaStorage) CreateBatch(ctx context.Context, items []*CreateItem, actor domain.Actor) ([]*Good, error) {
+	return make([]*Good, len(items)), nil
+}
+
+func TestCreateBatchResolvesAttributeSchemaOncePerProject(t *testing.T) {
+	goodStorage := &fakeSchemaStorage{
+		schema:      json.RawMessage(`{"type": "object", "properties": {"color": {"type": "string"}}}`),
+		schemaReads: make(map[domain.ProjectId]int),
+	}
+	goodService := NewGoodService(goodStorage, fakeInMemoryStorage{}, nil)
+
+	items := []*CreateItem{
+		{ProjectId: 1, Name: "a", Attributes: domain.GoodAttributes{"color": "red"}},
+		{ProjectId: 2, Name: "b", Attributes: domain.GoodAttributes{"color": "green"}},
+		{ProjectId: 1, Name: "c", Attributes: domain.GoodAttributes{"color": "blue"}},
+		{ProjectId: 1, Name: "d"},
+	}
+
+	if _, err := goodService.CreateBatch(context.Background(), items, "tester"); err != nil {
+		t.Fatalf("create batch: %v", err)
+	}
+
+	for projectId, reads := range goodStorage.schemaReads {
+		if reads != 1 {
+			t.Errorf("schema of project %d read %d times, want once", projectId, reads)
+		}
+	}
+	if len(goodStorage.schemaReads) != 2 {
+		t.Errorf("schemas of %d projects read, want 2", len(goodStorage.schemaReads))
+	}
+
+	items = append(items, &CreateItem{ProjectId: 2, Name: "e", Attributes: domain.GoodAttributes{"color": 1}})
+
+	if _, err := goodService.CreateBatch(context.Background(), items, "tester"); !errors.Is(err, ErrInvalidAttributes) {
+		t.Fatalf("got error %v, want %v", err, ErrInvalidAttributes)
+	}
+}
@@ -0,0 +1,14 @@
+package good
+
+import (
+	"context"
+	"io"
+)
+
+type BlobStore interface {
+	// Put stores size bytes read from r under key, replacing a blob stored under the same key.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete deletes the blob stored under key; deleting a missing blob is not an error.
+	Delete(ctx context.Context, key string) error
+}
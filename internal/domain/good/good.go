@@ -2,6 +2,7 @@ package good
 
 import (
 	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"time"
 )
 
 type Good struct {
@@ -12,4 +13,24 @@ type Good struct {
 	Priority    domain.GoodPriority
 	Removed     domain.GoodRemoved
 	CreatedAt   domain.GoodCreatedAt
+	CreatedBy   domain.Actor
+	UpdatedBy   domain.Actor
+	Version     domain.GoodVersion
+	ExternalKey domain.GoodExternalKey
+	Attributes  domain.GoodAttributes
+	// Price is nil if the good has no price.
+	Price *Price
+	// Stock is the quantity on hand, Reserved the part of it that is reserved.
+	Stock    int64
+	Reserved int64
+	// Locale is the locale of Name and Description. It is only set on the goods read through Get and List,
+	// which translate them according to the preferred locales of the reader.
+	Locale domain.Locale
+	// TranslatedAt is when the translation into Locale was last changed. It is zero if Name and Description
+	// are the good's own.
+	TranslatedAt time.Time
+	// ParentId is set if the good is a variant of another good.
+	ParentId *domain.GoodId
+	// Variants are only loaded on request, see GoodService.ExpandVariants, and never cached.
+	Variants []*Good `json:"-"`
 }
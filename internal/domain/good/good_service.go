@@ -1,51 +1,119 @@
 package good
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"github.com/vaberof/hezzl-backend/internal/infra/storage"
 	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"github.com/vaberof/hezzl-backend/pkg/metrics"
+	"github.com/vaberof/hezzl-backend/pkg/tracing"
+	"io"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 )
 
 const (
-	goodKey     = "good_"
-	goodListKey = "good_list_"
-	limitKey    = "limit_"
-	offsetKey   = "offset_"
+	goodKey       = "good_"
+	goodListKey   = "good_list_"
+	generationKey = "generation_"
+	limitKey      = "limit_"
+	offsetKey     = "offset_"
+	projectKey    = "project_"
+	removedKey    = "removed_"
+	tagsKey       = "tags_"
+	attributesKey = "attributes_"
+	topLevelKey   = "top_level"
 )
 
 const (
+	goodCacheExpireTime     = 1 * time.Minute
 	goodListCacheExpireTime = 1 * time.Minute
 )
 
+const (
+	goodCacheName     = "good"
+	goodListCacheName = "good_list"
+)
+
+var tracer = tracing.Tracer("github.com/vaberof/hezzl-backend/internal/domain/good")
+
 var (
-	ErrGoodNotFound = errors.New("good not found")
+	ErrGoodNotFound        = errors.New("good not found")
+	ErrGoodVersionMismatch = errors.New("good version mismatch")
+	ErrGoodNotRemoved      = errors.New("good is not removed")
+	ErrGoodRemoved         = errors.New("good is removed")
 )
 
 type GoodService interface {
-	Create(projectId domain.ProjectId, name domain.GoodName) (*Good, error)
-	Update(id domain.GoodId, projectId domain.ProjectId, name domain.GoodName, description *domain.GoodDescription) (*Good, error)
-	Delete(id domain.GoodId, projectId domain.ProjectId) (*Good, error)
-	List(limit, offset int) ([]*Good, error)
-	ChangePriority(id domain.GoodId, projectId domain.ProjectId, newPriority domain.GoodPriority) ([]*Good, error)
+	Create(ctx context.Context, projectId domain.ProjectId, name domain.GoodName, attributes domain.GoodAttributes, actor domain.Actor) (*Good, error)
+	Update(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, name domain.GoodName, description *domain.GoodDescription, attributes domain.GoodAttributes, actor domain.Actor, expectedVersion *domain.GoodVersion) (*Good, error)
+	Delete(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, actor domain.Actor, expectedVersion *domain.GoodVersion) (*Good, error)
+	Restore(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, actor domain.Actor, expectedVersion *domain.GoodVersion) (*Good, error)
+	Purge(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, actor domain.Actor, expectedVersion *domain.GoodVersion) (*Good, error)
+	PurgeRemoved(ctx context.Context, retention time.Duration, batchSize int, actor domain.Actor) (int, error)
+	Get(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, locales []domain.Locale) (*Good, error)
+	CreateBatch(ctx context.Context, items []*CreateItem, actor domain.Actor) ([]*Good, error)
+	UpdateBatch(ctx context.Context, items []*UpdateItem, actor domain.Actor) ([]*BatchResult, error)
+	DeleteBatch(ctx context.Context, items []*RemoveItem, actor domain.Actor) ([]*BatchResult, error)
+	Import(ctx context.Context, projectId domain.ProjectId, rows ImportRowReader, actor domain.Actor) (*ImportReport, error)
+	List(ctx context.Context, filter *ListFilter, limit, offset int, locales []domain.Locale) ([]*Good, error)
+	Export(ctx context.Context, filter *ListFilter, fn func(*Good) error) error
+	Search(ctx context.Context, projectId domain.ProjectId, text string, limit, offset int) ([]*SearchResult, error)
+	AddTags(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, tags []domain.TagName, actor domain.Actor) ([]domain.TagName, error)
+	RemoveTags(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, tags []domain.TagName, actor domain.Actor) ([]domain.TagName, error)
+	GetTags(ctx context.Context, id domain.GoodId, projectId domain.ProjectId) ([]domain.TagName, error)
+	ListTags(ctx context.Context, projectId domain.ProjectId) ([]*Tag, error)
+	GetAttributeSchema(ctx context.Context, projectId domain.ProjectId) (*AttributeSchema, error)
+	SetAttributeSchema(ctx context.Context, projectId domain.ProjectId, schema json.RawMessage, actor domain.Actor) (*AttributeSchema, error)
+	DeleteAttributeSchema(ctx context.Context, projectId domain.ProjectId) error
+	AddAttachment(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, name, contentType string, size int64, content io.Reader, actor domain.Actor) (*Attachment, error)
+	ListAttachments(ctx context.Context, id domain.GoodId, projectId domain.ProjectId) ([]*Attachment, error)
+	OpenAttachment(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, attachmentId domain.AttachmentId) (*Attachment, io.ReadCloser, error)
+	RemoveAttachment(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, attachmentId domain.AttachmentId) (*Attachment, error)
+	DeleteQueuedBlobs(ctx context.Context, batchSize int) (int, error)
+	SetPrice(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, price *Price, actor domain.Actor, expectedVersion *domain.GoodVersion) (*Good, error)
+	ReserveStock(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, quantity int64, reason string, actor domain.Actor) (*StockMovement, error)
+	ReleaseStock(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, quantity int64, reason string, actor domain.Actor) (*StockMovement, error)
+	AdjustStock(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, delta int64, reason string, actor domain.Actor) (*StockMovement, error)
+	ListStockMovements(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, limit, offset int) ([]*StockMovement, error)
+	CreateVariant(ctx context.Context, parentId domain.GoodId, projectId domain.ProjectId, name domain.GoodName, attributes domain.GoodAttributes, actor domain.Actor) (*Good, error)
+	ExpandVariants(ctx context.Context, domainGoods []*Good, locales []domain.Locale) error
+	SetTranslation(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, locale domain.Locale, name domain.GoodName, description domain.GoodDescription, actor domain.Actor) (*Translation, error)
+	RemoveTranslation(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, locale domain.Locale, actor domain.Actor) (*Translation, error)
+	ListTranslations(ctx context.Context, id domain.GoodId, projectId domain.ProjectId) ([]*Translation, error)
+	GetDefaultLocale(ctx context.Context, projectId domain.ProjectId) (domain.Locale, error)
+	SetDefaultLocale(ctx context.Context, projectId domain.ProjectId, locale domain.Locale) (domain.Locale, error)
+	Move(ctx context.Context, id domain.GoodId, projectId, targetProjectId domain.ProjectId, options *TransferOptions, actor domain.Actor, expectedVersion *domain.GoodVersion) (*Good, error)
+	Copy(ctx context.Context, id domain.GoodId, projectId, targetProjectId domain.ProjectId, options *TransferOptions, actor domain.Actor) (*Good, error)
+	ChangePriority(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, newPriority domain.GoodPriority, actor domain.Actor, expectedVersion *domain.GoodVersion) ([]*Good, error)
 }
 
 type goodServiceImpl struct {
 	goodStorage     GoodStorage
 	inMemoryStorage InMemoryStorage
+	blobStore       BlobStore
 }
 
-func NewGoodService(goodStorage GoodStorage, inMemoryStorage InMemoryStorage) GoodService {
+func NewGoodService(goodStorage GoodStorage, inMemoryStorage InMemoryStorage, blobStore BlobStore) GoodService {
 	return &goodServiceImpl{
 		goodStorage:     goodStorage,
 		inMemoryStorage: inMemoryStorage,
+		blobStore:       blobStore,
 	}
 }
 
-func (g *goodServiceImpl) Create(projectId domain.ProjectId, name domain.GoodName) (*Good, error) {
-	domainGood, err := g.goodStorage.Create(projectId, name)
+func (g *goodServiceImpl) Create(ctx context.Context, projectId domain.ProjectId, name domain.GoodName, attributes domain.GoodAttributes, actor domain.Actor) (*Good, error) {
+	ctx, span := tracer.Start(ctx, "GoodService.Create")
+	defer span.End()
+
+	if err := g.validateAttributes(ctx, projectId, attributes); err != nil {
+		return nil, err
+	}
+
+	domainGood, err := g.goodStorage.Create(ctx, projectId, name, attributes, actor)
 	if err != nil {
 		return nil, err
 	}
@@ -53,23 +121,29 @@ func (g *goodServiceImpl) Create(projectId domain.ProjectId, name domain.GoodNam
 	return domainGood, nil
 }
 
-func (g *goodServiceImpl) Update(id domain.GoodId, projectId domain.ProjectId, name domain.GoodName, description *domain.GoodDescription) (*Good, error) {
-	exists, err := g.goodStorage.IsExists(id, projectId)
-	if err != nil {
-		return nil, err
-	}
-	if !exists {
-		return nil, ErrGoodNotFound
+func (g *goodServiceImpl) Update(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, name domain.GoodName, description *domain.GoodDescription, attributes domain.GoodAttributes, actor domain.Actor, expectedVersion *domain.GoodVersion) (*Good, error) {
+	ctx, span := tracer.Start(ctx, "GoodService.Update")
+	defer span.End()
+
+	if attributes != nil {
+		if err := g.validateAttributes(ctx, projectId, attributes); err != nil {
+			return nil, err
+		}
 	}
 
-	domainGood, err := g.goodStorage.Update(id, projectId, name, description)
+	var domainGood *Good
+
+	err := g.transition(ctx, id, projectId, expectedVersion, rejectRemoved, func(version *domain.GoodVersion) (err error) {
+		domainGood, err = g.goodStorage.Update(ctx, id, projectId, name, description, attributes, actor, version)
+		return err
+	})
 	if err != nil {
-		return nil, err
+		return nil, g.mapWriteError(ctx, id, projectId, err)
 	}
 
 	goodCacheKey := g.getGoodCacheKey(id, projectId)
 
-	err = g.inMemoryStorage.Delete(goodCacheKey)
+	err = g.inMemoryStorage.Delete(ctx, goodCacheKey)
 	if err != nil {
 		if !errors.Is(err, storage.ErrRedisKeyNotFound) {
 			return nil, err
@@ -79,18 +153,43 @@ func (g *goodServiceImpl) Update(id domain.GoodId, projectId domain.ProjectId, n
 	return domainGood, nil
 }
 
-func (g *goodServiceImpl) Delete(id domain.GoodId, projectId domain.ProjectId) (*Good, error) {
-	domainGood, err := g.goodStorage.Delete(id, projectId)
+func (g *goodServiceImpl) Delete(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, actor domain.Actor, expectedVersion *domain.GoodVersion) (*Good, error) {
+	ctx, span := tracer.Start(ctx, "GoodService.Delete")
+	defer span.End()
+
+	var domainGood *Good
+
+	err := g.transition(ctx, id, projectId, expectedVersion, rejectRemoved, func(version *domain.GoodVersion) (err error) {
+		domainGood, err = g.goodStorage.Delete(ctx, id, projectId, actor, version)
+		return err
+	})
 	if err != nil {
-		if errors.Is(err, storage.ErrPostgresGoodNotFound) {
-			return nil, ErrGoodNotFound
+		return nil, g.mapWriteError(ctx, id, projectId, err)
+	}
+
+	err = g.inMemoryStorage.Delete(ctx, g.getGoodCacheKeysWithVariants(domainGood)...)
+	if err != nil {
+		if !errors.Is(err, storage.ErrRedisKeyNotFound) {
+			return nil, err
 		}
-		return nil, err
 	}
 
-	goodCacheKey := g.getGoodCacheKey(id, projectId)
+	return domainGood, nil
+}
 
-	err = g.inMemoryStorage.Delete(goodCacheKey)
+func (g *goodServiceImpl) Restore(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, actor domain.Actor, expectedVersion *domain.GoodVersion) (*Good, error) {
+	ctx, span := tracer.Start(ctx, "GoodService.Restore")
+	defer span.End()
+
+	domainGood, err := g.goodStorage.Restore(ctx, id, projectId, actor, expectedVersion)
+	if err != nil {
+		if errors.Is(err, storage.ErrPostgresParentRemoved) {
+			return nil, ErrParentRemoved
+		}
+		return nil, g.mapWriteError(ctx, id, projectId, err)
+	}
+
+	err = g.inMemoryStorage.Delete(ctx, g.getGoodCacheKeysWithVariants(domainGood)...)
 	if err != nil {
 		if !errors.Is(err, storage.ErrRedisKeyNotFound) {
 			return nil, err
@@ -100,54 +199,185 @@ func (g *goodServiceImpl) Delete(id domain.GoodId, projectId domain.ProjectId) (
 	return domainGood, nil
 }
 
-func (g *goodServiceImpl) List(limit, offset int) ([]*Good, error) {
-	goodListCacheKey := g.getGoodListCacheKey(limit, offset)
+func (g *goodServiceImpl) Purge(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, actor domain.Actor, expectedVersion *domain.GoodVersion) (*Good, error) {
+	ctx, span := tracer.Start(ctx, "GoodService.Purge")
+	defer span.End()
 
-	cachedDomainGoods, err := g.getCachedGoods(goodListCacheKey)
-	if err == nil {
-		return cachedDomainGoods, nil
+	domainGood, err := g.goodStorage.Purge(ctx, id, projectId, actor, expectedVersion)
+	if err != nil {
+		return nil, g.mapWriteError(ctx, id, projectId, err)
 	}
+
+	// The attachments of the good are purged along with it. Their blobs are deleted right away if possible;
+	// the rest stay queued for DeleteQueuedBlobs.
+	g.tryDeleteQueuedBlobs(ctx)
+
+	err = g.inMemoryStorage.Delete(ctx, g.getGoodCacheKeysWithVariants(domainGood)...)
 	if err != nil {
 		if !errors.Is(err, storage.ErrRedisKeyNotFound) {
 			return nil, err
 		}
 	}
 
-	domainGoods, err := g.goodStorage.List(limit, offset)
+	return domainGood, nil
+}
+
+// PurgeRemoved permanently deletes goods removed longer than retention ago in batches of batchSize
+// and returns how many goods were purged.
+func (g *goodServiceImpl) PurgeRemoved(ctx context.Context, retention time.Duration, batchSize int, actor domain.Actor) (int, error) {
+	ctx, span := tracer.Start(ctx, "GoodService.PurgeRemoved")
+	defer span.End()
+
+	var purged int
+
+	for {
+		domainGoods, err := g.goodStorage.PurgeRemoved(ctx, retention, batchSize, actor)
+		if err != nil {
+			return purged, err
+		}
+		purged += len(domainGoods)
+
+		if len(domainGoods) > 0 {
+			err = g.inMemoryStorage.Delete(ctx, g.getGoodCacheKeys(domainGoods)...)
+			if err != nil {
+				if !errors.Is(err, storage.ErrRedisKeyNotFound) {
+					return purged, err
+				}
+			}
+		}
+
+		if len(domainGoods) < batchSize {
+			return purged, nil
+		}
+	}
+}
+
+// Get returns the good translated into the first of the locales it has a translation for.
+// The good and its translations are cached apart, so that a write to either leaves the other cached.
+func (g *goodServiceImpl) Get(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, locales []domain.Locale) (*Good, error) {
+	ctx, span := tracer.Start(ctx, "GoodService.Get")
+	defer span.End()
+
+	domainGood, err := g.getGood(ctx, id, projectId)
 	if err != nil {
 		return nil, err
 	}
 
-	domainGoodsBytes, err := json.Marshal(&domainGoods)
+	if err = g.localizeGood(ctx, domainGood, locales); err != nil {
+		return nil, err
+	}
+
+	return domainGood, nil
+}
+
+func (g *goodServiceImpl) getGood(ctx context.Context, id domain.GoodId, projectId domain.ProjectId) (*Good, error) {
+	goodCacheKey := g.getGoodCacheKey(id, projectId)
+
+	cachedDomainGood, err := g.getCachedGood(ctx, goodCacheKey)
+	if err == nil {
+		metrics.CacheRequestsTotal.WithLabelValues(goodCacheName, metrics.CacheResultHit).Inc()
+		return cachedDomainGood, nil
+	}
+	if !errors.Is(err, storage.ErrRedisKeyNotFound) {
+		return nil, err
+	}
+	metrics.CacheRequestsTotal.WithLabelValues(goodCacheName, metrics.CacheResultMiss).Inc()
+
+	domainGood, err := g.goodStorage.Get(ctx, id, projectId)
 	if err != nil {
+		if errors.Is(err, storage.ErrPostgresGoodNotFound) {
+			return nil, ErrGoodNotFound
+		}
 		return nil, err
 	}
 
-	err = g.inMemoryStorage.Set(goodListCacheKey, string(domainGoodsBytes), goodListCacheExpireTime)
+	domainGoodBytes, err := json.Marshal(domainGood)
 	if err != nil {
 		return nil, err
 	}
 
-	return domainGoods, nil
+	err = g.inMemoryStorage.Set(ctx, goodCacheKey, string(domainGoodBytes), goodCacheExpireTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return domainGood, nil
 }
 
-func (g *goodServiceImpl) ChangePriority(id domain.GoodId, projectId domain.ProjectId, newPriority domain.GoodPriority) ([]*Good, error) {
-	exists, err := g.goodStorage.IsExists(id, projectId)
+// List lists the goods translated like by Get. The translated page is cached per preferred locales.
+func (g *goodServiceImpl) List(ctx context.Context, filter *ListFilter, limit, offset int, locales []domain.Locale) ([]*Good, error) {
+	ctx, span := tracer.Start(ctx, "GoodService.List")
+	defer span.End()
+
+	locales = lookupLocales(locales)
+
+	generation, err := g.getGoodListGeneration(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
-	if !exists {
-		return nil, ErrGoodNotFound
+
+	goodListCacheKey := g.getGoodListCacheKey(filter, generation, limit, offset, locales)
+
+	cachedDomainGoods, err := g.getCachedGoods(ctx, goodListCacheKey)
+	if err == nil {
+		metrics.CacheRequestsTotal.WithLabelValues(goodListCacheName, metrics.CacheResultHit).Inc()
+		return cachedDomainGoods, nil
+	}
+	if err != nil {
+		if !errors.Is(err, storage.ErrRedisKeyNotFound) {
+			return nil, err
+		}
+	}
+	metrics.CacheRequestsTotal.WithLabelValues(goodListCacheName, metrics.CacheResultMiss).Inc()
+
+	domainGoods, err := g.goodStorage.List(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = g.localizeGoods(ctx, domainGoods, locales); err != nil {
+		return nil, err
+	}
+
+	domainGoodsBytes, err := json.Marshal(&domainGoods)
+	if err != nil {
+		return nil, err
 	}
 
-	domainGoods, err := g.goodStorage.ChangePriority(id, projectId, newPriority)
+	err = g.inMemoryStorage.Set(ctx, goodListCacheKey, string(domainGoodsBytes), goodListCacheExpireTime)
 	if err != nil {
 		return nil, err
 	}
 
+	return domainGoods, nil
+}
+
+// Export passes the goods matching filter to fn one by one in id order, stopping at the first error of fn.
+// The goods are read from storage as they are consumed and never cached.
+func (g *goodServiceImpl) Export(ctx context.Context, filter *ListFilter, fn func(*Good) error) error {
+	ctx, span := tracer.Start(ctx, "GoodService.Export")
+	defer span.End()
+
+	return g.goodStorage.Export(ctx, filter, fn)
+}
+
+func (g *goodServiceImpl) ChangePriority(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, newPriority domain.GoodPriority, actor domain.Actor, expectedVersion *domain.GoodVersion) ([]*Good, error) {
+	ctx, span := tracer.Start(ctx, "GoodService.ChangePriority")
+	defer span.End()
+
+	var domainGoods []*Good
+
+	err := g.transition(ctx, id, projectId, expectedVersion, rejectRemoved, func(version *domain.GoodVersion) (err error) {
+		domainGoods, err = g.goodStorage.ChangePriority(ctx, id, projectId, newPriority, actor, version)
+		return err
+	})
+	if err != nil {
+		return nil, g.mapWriteError(ctx, id, projectId, err)
+	}
+
 	goodCacheKeys := g.getGoodCacheKeys(domainGoods)
 
-	err = g.inMemoryStorage.Delete(goodCacheKeys...)
+	err = g.inMemoryStorage.Delete(ctx, goodCacheKeys...)
 	if err != nil {
 		if !errors.Is(err, storage.ErrRedisKeyNotFound) {
 			return nil, err
@@ -157,8 +387,44 @@ func (g *goodServiceImpl) ChangePriority(id domain.GoodId, projectId domain.Proj
 	return domainGoods, nil
 }
 
-func (g *goodServiceImpl) getCachedGoods(key string) ([]*Good, error) {
-	cachedGoodsStr, err := g.inMemoryStorage.Get(key)
+// mapWriteError translates storage errors of the write operations. On a version mismatch the cached good
+// is dropped as well, so that a client re-reading it after a stale cache fill gets the current version.
+func (g *goodServiceImpl) mapWriteError(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, err error) error {
+	switch {
+	case errors.Is(err, storage.ErrPostgresGoodNotFound):
+		return ErrGoodNotFound
+	case errors.Is(err, storage.ErrPostgresGoodNotRemoved):
+		return ErrGoodNotRemoved
+	case errors.Is(err, storage.ErrPostgresGoodRemoved):
+		return ErrGoodRemoved
+	case errors.Is(err, storage.ErrPostgresGoodVersionMismatch):
+		if err := g.inMemoryStorage.Delete(ctx, g.getGoodCacheKey(id, projectId)); err != nil && !errors.Is(err, storage.ErrRedisKeyNotFound) {
+			return err
+		}
+		return ErrGoodVersionMismatch
+	default:
+		return err
+	}
+}
+
+func (g *goodServiceImpl) getCachedGood(ctx context.Context, key string) (*Good, error) {
+	cachedGoodStr, err := g.inMemoryStorage.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var domainGood Good
+
+	err = json.Unmarshal([]byte(cachedGoodStr), &domainGood)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domainGood, nil
+}
+
+func (g *goodServiceImpl) getCachedGoods(ctx context.Context, key string) ([]*Good, error) {
+	cachedGoodsStr, err := g.inMemoryStorage.Get(ctx, key)
 	if err != nil {
 		return nil, err
 	}
@@ -188,9 +454,83 @@ func (g *goodServiceImpl) getGoodCacheKey(id domain.GoodId, projectId domain.Pro
 	return goodCacheKey
 }
 
-func (g *goodServiceImpl) getGoodListCacheKey(limit, offset int) string {
+// getGoodListGeneration returns the generation of the cached lists that filter selects. Lists filtered
+// by project share the generation of the project, the other lists share one generation for all projects.
+func (g *goodServiceImpl) getGoodListGeneration(ctx context.Context, filter *ListFilter) (string, error) {
+	var projectId *domain.ProjectId
+	if filter != nil {
+		projectId = filter.ProjectId
+	}
+
+	generation, err := g.inMemoryStorage.Get(ctx, g.getGoodListGenerationKey(projectId))
+	if err != nil {
+		if !errors.Is(err, storage.ErrRedisKeyNotFound) {
+			return "", err
+		}
+		return "0", nil
+	}
+	return generation, nil
+}
+
+// invalidateGoodLists bumps the list generations of the projects and of all projects, so the lists
+// cached before are no longer read and expire on their own.
+func (g *goodServiceImpl) invalidateGoodLists(ctx context.Context, projectIds ...domain.ProjectId) error {
+	generationKeys := []string{g.getGoodListGenerationKey(nil)}
+	for i := range projectIds {
+		generationKeys = append(generationKeys, g.getGoodListGenerationKey(&projectIds[i]))
+	}
+
+	for _, generationKey := range generationKeys {
+		if _, err := g.inMemoryStorage.Increment(ctx, generationKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *goodServiceImpl) getGoodListGenerationKey(projectId *domain.ProjectId) string {
+	if projectId == nil {
+		return goodListKey + generationKey + "all"
+	}
+	return goodListKey + generationKey + projectKey + strconv.FormatInt(projectId.Int64(), 10)
+}
+
+func (g *goodServiceImpl) getGoodListCacheKey(filter *ListFilter, generation string, limit, offset int, locales []domain.Locale) string {
 	limitStr := strconv.Itoa(limit)
 	offsetStr := strconv.Itoa(offset)
-	goodListCacheKey := goodListKey + limitKey + limitStr + "_" + offsetKey + offsetStr
+	goodListCacheKey := goodListKey + generationKey + generation + "_" + limitKey + limitStr + "_" + offsetKey + offsetStr
+	if len(locales) > 0 {
+		localeStrs := make([]string, len(locales))
+		for i := range locales {
+			localeStrs[i] = locales[i].String()
+		}
+		// The order of preference matters, so the locales are not sorted.
+		goodListCacheKey += "_" + localesKey + strings.Join(localeStrs, ",")
+	}
+	if filter == nil {
+		return goodListCacheKey
+	}
+	if filter.ProjectId != nil {
+		goodListCacheKey += "_" + projectKey + strconv.FormatInt(filter.ProjectId.Int64(), 10)
+	}
+	if filter.Removed != nil {
+		goodListCacheKey += "_" + removedKey + strconv.FormatBool(*filter.Removed)
+	}
+	if len(filter.Tags) > 0 {
+		tags := make([]string, len(filter.Tags))
+		for i := range filter.Tags {
+			tags[i] = filter.Tags[i].String()
+		}
+		slices.Sort(tags)
+		goodListCacheKey += "_" + tagsKey + strings.Join(tags, ",")
+	}
+	if len(filter.Attributes) > 0 {
+		// Object keys are marshaled in sorted order, so equal filters share the key.
+		attributes, _ := json.Marshal(filter.Attributes)
+		goodListCacheKey += "_" + attributesKey + string(attributes)
+	}
+	if filter.TopLevel {
+		goodListCacheKey += "_" + topLevelKey
+	}
 	return goodListCacheKey
 }
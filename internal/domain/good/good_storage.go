@@ -1,12 +1,54 @@
 package good
 
-import "github.com/vaberof/hezzl-backend/pkg/domain"
+import (
+	"context"
+	"encoding/json"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"time"
+)
 
 type GoodStorage interface {
-	Create(projectId domain.ProjectId, name domain.GoodName) (*Good, error)
-	Update(id domain.GoodId, projectId domain.ProjectId, name domain.GoodName, description *domain.GoodDescription) (*Good, error)
-	Delete(id domain.GoodId, projectId domain.ProjectId) (*Good, error)
-	List(limit, offset int) ([]*Good, error)
-	ChangePriority(id domain.GoodId, projectId domain.ProjectId, newPriority domain.GoodPriority) ([]*Good, error)
-	IsExists(id domain.GoodId, projectId domain.ProjectId) (bool, error)
+	Create(ctx context.Context, projectId domain.ProjectId, name domain.GoodName, attributes domain.GoodAttributes, actor domain.Actor) (*Good, error)
+	Update(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, name domain.GoodName, description *domain.GoodDescription, attributes domain.GoodAttributes, actor domain.Actor, expectedVersion *domain.GoodVersion) (*Good, error)
+	Delete(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, actor domain.Actor, expectedVersion *domain.GoodVersion) (*Good, error)
+	Restore(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, actor domain.Actor, expectedVersion *domain.GoodVersion) (*Good, error)
+	Purge(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, actor domain.Actor, expectedVersion *domain.GoodVersion) (*Good, error)
+	PurgeRemoved(ctx context.Context, retention time.Duration, limit int, actor domain.Actor) ([]*Good, error)
+	Get(ctx context.Context, id domain.GoodId, projectId domain.ProjectId) (*Good, error)
+	GetBatch(ctx context.Context, ids []domain.GoodId) ([]*Good, error)
+	CreateBatch(ctx context.Context, items []*CreateItem, actor domain.Actor) ([]*Good, error)
+	UpdateBatch(ctx context.Context, items []*UpdateItem, actor domain.Actor) ([]*BatchResult, error)
+	DeleteBatch(ctx context.Context, items []*RemoveItem, actor domain.Actor) ([]*BatchResult, error)
+	UpsertBatch(ctx context.Context, projectId domain.ProjectId, rows []*ImportRow, actor domain.Actor) ([]*UpsertResult, error)
+	List(ctx context.Context, filter *ListFilter, limit, offset int) ([]*Good, error)
+	Export(ctx context.Context, filter *ListFilter, fn func(*Good) error) error
+	Search(ctx context.Context, projectId domain.ProjectId, text string, limit, offset int) ([]*SearchResult, error)
+	AddTags(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, tags []domain.TagName, actor domain.Actor) ([]domain.TagName, error)
+	RemoveTags(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, tags []domain.TagName, actor domain.Actor) ([]domain.TagName, error)
+	GetTags(ctx context.Context, id domain.GoodId, projectId domain.ProjectId) ([]domain.TagName, error)
+	ListTags(ctx context.Context, projectId domain.ProjectId) ([]*Tag, error)
+	GetAttributeSchema(ctx context.Context, projectId domain.ProjectId) (*AttributeSchema, error)
+	SetAttributeSchema(ctx context.Context, projectId domain.ProjectId, schema json.RawMessage, actor domain.Actor) (*AttributeSchema, error)
+	DeleteAttributeSchema(ctx context.Context, projectId domain.ProjectId) error
+	CreateAttachment(ctx context.Context, attachment *Attachment, actor domain.Actor) (*Attachment, error)
+	ListAttachments(ctx context.Context, id domain.GoodId, projectId domain.ProjectId) ([]*Attachment, error)
+	GetAttachment(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, attachmentId domain.AttachmentId) (*Attachment, error)
+	DeleteAttachment(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, attachmentId domain.AttachmentId) (*Attachment, bool, error)
+	QueuedBlobDeletions(ctx context.Context, limit int) ([]string, error)
+	CompleteBlobDeletions(ctx context.Context, storageKeys []string) error
+	SetPrice(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, price *Price, actor domain.Actor, expectedVersion *domain.GoodVersion) (*Good, error)
+	MoveStock(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, kind StockMovementKind, quantity int64, reason string, actor domain.Actor) (*StockMovement, error)
+	ListStockMovements(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, limit, offset int) ([]*StockMovement, error)
+	CreateVariant(ctx context.Context, parentId domain.GoodId, projectId domain.ProjectId, name domain.GoodName, attributes domain.GoodAttributes, actor domain.Actor) (*Good, error)
+	ListVariants(ctx context.Context, parentIds []domain.GoodId) ([]*Good, error)
+	GetTranslation(ctx context.Context, id domain.GoodId, locale domain.Locale) (*Translation, error)
+	FindTranslations(ctx context.Context, ids []domain.GoodId, locales []domain.Locale) ([]*Translation, error)
+	ListTranslations(ctx context.Context, id domain.GoodId, projectId domain.ProjectId) ([]*Translation, error)
+	SetTranslation(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, translation *Translation, actor domain.Actor) (*Translation, error)
+	RemoveTranslation(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, locale domain.Locale, actor domain.Actor) (*Translation, error)
+	GetDefaultLocale(ctx context.Context, projectId domain.ProjectId) (domain.Locale, error)
+	SetDefaultLocale(ctx context.Context, projectId domain.ProjectId, locale domain.Locale) (domain.Locale, error)
+	Move(ctx context.Context, id domain.GoodId, projectId, targetProjectId domain.ProjectId, options *TransferOptions, actor domain.Actor, expectedVersion *domain.GoodVersion) (*Good, error)
+	Copy(ctx context.Context, id domain.GoodId, projectId, targetProjectId domain.ProjectId, options *TransferOptions, actor domain.Actor) (*Good, error)
+	ChangePriority(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, newPriority domain.GoodPriority, actor domain.Actor, expectedVersion *domain.GoodVersion) ([]*Good, error)
 }
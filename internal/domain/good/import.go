@@ -0,0 +1,48 @@
+package good
+
+import (
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"strconv"
+)
+
+// ImportRow is a good to be created or updated by an import, identified by its external key within the project.
+type ImportRow struct {
+	// Line is the position of the row in the uploaded file, used in the import report.
+	Line        int
+	ExternalKey domain.GoodExternalKey
+	Name        domain.GoodName
+	Description *domain.GoodDescription
+}
+
+// ImportRowReader streams the rows of an uploaded file. Next returns io.EOF after the last row.
+// A row that cannot be parsed is reported as an *ImportRowError and reading may continue after it;
+// any other error aborts the import.
+type ImportRowReader interface {
+	Next() (*ImportRow, error)
+}
+
+type ImportRowError struct {
+	Line        int    `json:"line"`
+	ExternalKey string `json:"externalKey,omitempty"`
+	Reason      string `json:"reason"`
+}
+
+func (e *ImportRowError) Error() string {
+	return "line " + strconv.Itoa(e.Line) + ": " + e.Reason
+}
+
+type ImportReport struct {
+	Created  int               `json:"created"`
+	Updated  int               `json:"updated"`
+	Failed   int               `json:"failed"`
+	Failures []*ImportRowError `json:"failures"`
+	// FailuresTruncated is set if there were more failed rows than are listed in Failures.
+	FailuresTruncated bool `json:"failuresTruncated,omitempty"`
+}
+
+// UpsertResult is the outcome of a single import row: the created or updated good or the reason it was skipped.
+type UpsertResult struct {
+	Good    *Good
+	Created bool
+	Err     error
+}
@@ -0,0 +1,156 @@
+package good
+
+import (
+	"context"
+	"errors"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	importChunkSize           = 500
+	importMaxReportedFailures = 1000
+	maxExternalKeyLength      = 255
+)
+
+var ErrProjectNotFound = errors.New("project not found")
+
+// Import creates or updates the goods of the project read from rows, matching them by external key.
+// Rows are written in chunks, each chunk in a single statement; invalid rows and rows of removed goods
+// are reported as failed and do not stop the import. Rows carry no attributes, so created goods get empty
+// attributes and updated goods keep theirs; the import is refused if the attribute schema of the project
+// does not accept empty attributes. The report is returned along with an error that
+// aborted the import, so that the caller knows which rows were imported before.
+func (g *goodServiceImpl) Import(ctx context.Context, projectId domain.ProjectId, rows ImportRowReader, actor domain.Actor) (*ImportReport, error) {
+	ctx, span := tracer.Start(ctx, "GoodService.Import")
+	defer span.End()
+
+	if err := g.validateAttributes(ctx, projectId, nil); err != nil {
+		return nil, err
+	}
+
+	report := &ImportReport{Failures: make([]*ImportRowError, 0)}
+
+	chunk := make([]*ImportRow, 0, importChunkSize)
+	chunkKeys := make(map[domain.GoodExternalKey]struct{}, importChunkSize)
+
+	flush := func() error {
+		if len(chunk) == 0 {
+			return nil
+		}
+
+		err := g.importChunk(ctx, projectId, chunk, actor, report)
+
+		chunk = chunk[:0]
+		clear(chunkKeys)
+
+		return err
+	}
+
+	for {
+		row, err := rows.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var rowErr *ImportRowError
+			if errors.As(err, &rowErr) {
+				report.fail(rowErr)
+				continue
+			}
+			return report, err
+		}
+
+		if rowErr := validateImportRow(row); rowErr != nil {
+			report.fail(rowErr)
+			continue
+		}
+
+		// A statement cannot update the same good twice, so a repeated key starts a new chunk.
+		if _, ok := chunkKeys[row.ExternalKey]; ok {
+			if err = flush(); err != nil {
+				return report, err
+			}
+		}
+
+		chunk = append(chunk, row)
+		chunkKeys[row.ExternalKey] = struct{}{}
+
+		if len(chunk) == importChunkSize {
+			if err = flush(); err != nil {
+				return report, err
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		return report, err
+	}
+
+	return report, nil
+}
+
+func (g *goodServiceImpl) importChunk(ctx context.Context, projectId domain.ProjectId, chunk []*ImportRow, actor domain.Actor, report *ImportReport) error {
+	results, err := g.goodStorage.UpsertBatch(ctx, projectId, chunk, actor)
+	if err != nil {
+		if errors.Is(err, storage.ErrPostgresProjectNotFound) {
+			return ErrProjectNotFound
+		}
+		return err
+	}
+
+	var updatedGoodCacheKeys []string
+
+	for i, result := range results {
+		switch {
+		case result.Err != nil:
+			reason := result.Err.Error()
+			if errors.Is(result.Err, storage.ErrPostgresGoodRemoved) {
+				reason = ErrGoodRemoved.Error()
+			}
+			report.fail(&ImportRowError{Line: chunk[i].Line, ExternalKey: chunk[i].ExternalKey.String(), Reason: reason})
+		case result.Created:
+			report.Created++
+		default:
+			report.Updated++
+			updatedGoodCacheKeys = append(updatedGoodCacheKeys, g.getGoodCacheKey(result.Good.Id, result.Good.ProjectId))
+		}
+	}
+
+	if len(updatedGoodCacheKeys) > 0 {
+		err = g.inMemoryStorage.Delete(ctx, updatedGoodCacheKeys...)
+		if err != nil && !errors.Is(err, storage.ErrRedisKeyNotFound) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateImportRow(row *ImportRow) *ImportRowError {
+	externalKey := row.ExternalKey.String()
+
+	switch {
+	case strings.TrimSpace(externalKey) == "":
+		return &ImportRowError{Line: row.Line, Reason: "external key is required"}
+	case utf8.RuneCountInString(externalKey) > maxExternalKeyLength:
+		return &ImportRowError{Line: row.Line, Reason: "external key is too long"}
+	case strings.TrimSpace(row.Name.String()) == "":
+		return &ImportRowError{Line: row.Line, ExternalKey: externalKey, Reason: "name is required"}
+	}
+
+	return nil
+}
+
+func (r *ImportReport) fail(rowErr *ImportRowError) {
+	r.Failed++
+
+	if len(r.Failures) < importMaxReportedFailures {
+		r.Failures = append(r.Failures, rowErr)
+	} else {
+		r.FailuresTruncated = true
+	}
+}
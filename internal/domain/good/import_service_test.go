@@ -0,0 +1,201 @@
+package good
+
+import (
+	"context"
+	"errors"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// fakeImportStorage upserts goods by external key like the Postgres storage and records the chunks it is given.
+// A statement cannot touch a good twice, so a chunk repeating a key fails the test.
+type fakeImportStorage struct {
+	GoodStorage
+
+	t       *testing.T
+	goods   map[domain.GoodExternalKey]*Good
+	removed map[domain.GoodExternalKey]bool
+	chunks  [][]domain.GoodExternalKey
+}
+
+func newFakeImportStorage(t *testing.T) *fakeImportStorage {
+	return &fakeImportStorage{
+		t:       t,
+		goods:   make(map[domain.GoodExternalKey]*Good),
+		removed: make(map[domain.GoodExternalKey]bool),
+	}
+}
+
+func (s *fakeImportStorage) GetAttributeSchema(ctx context.Context, projectId domain.ProjectId) (*AttributeSchema, error) {
+	return nil, storage.ErrPostgresAttributeSchemaNotFound
+}
+
+func (s *fakeImportStorage) UpsertBatch(ctx context.Context, projectId domain.ProjectId, rows []*ImportRow, actor domain.Actor) ([]*UpsertResult, error) {
+	chunkKeys := make([]domain.GoodExternalKey, 0, len(rows))
+	results := make([]*UpsertResult, 0, len(rows))
+
+	for _, row := range rows {
+		for _, chunkKey := range chunkKeys {
+			if chunkKey == row.ExternalKey {
+				s.t.Errorf("chunk repeats external key %q", row.ExternalKey)
+			}
+		}
+		chunkKeys = append(chunkKeys, row.ExternalKey)
+
+		if s.removed[row.ExternalKey] {
+			results = append(results, &UpsertResult{Err: storage.ErrPostgresGoodRemoved})
+			continue
+		}
+
+		good, ok := s.goods[row.ExternalKey]
+		if !ok {
+			good = &Good{Id: domain.GoodId(len(s.goods) + 1), ProjectId: projectId, ExternalKey: row.ExternalKey}
+			s.goods[row.ExternalKey] = good
+		}
+		good.Name = row.Name
+
+		results = append(results, &UpsertResult{Good: good, Created: !ok})
+	}
+
+	s.chunks = append(s.chunks, chunkKeys)
+
+	return results, nil
+}
+
+// fakeImportRows serves rows and then io.EOF. A nil row is served as a parse error of its line.
+type fakeImportRows struct {
+	rows []*ImportRow
+	next int
+}
+
+func (r *fakeImportRows) Next() (*ImportRow, error) {
+	if r.next == len(r.rows) {
+		return nil, io.EOF
+	}
+	r.next++
+
+	row := r.rows[r.next-1]
+	if row == nil {
+		return nil, &ImportRowError{Line: r.next, Reason: "malformed row"}
+	}
+	row.Line = r.next
+	return row, nil
+}
+
+func TestImportValidatesRows(t *testing.T) {
+	goodStorage := newFakeImportStorage(t)
+	goodStorage.removed["removed"] = true
+	goodService := NewGoodService(goodStorage, fakeInMemoryStorage{}, nil)
+
+	rows := &fakeImportRows{rows: []*ImportRow{
+		{ExternalKey: "a", Name: "a"},
+		{ExternalKey: " ", Name: "blank key"},
+		{ExternalKey: domain.GoodExternalKey(strings.Repeat("k", maxExternalKeyLength+1)), Name: "long key"},
+		{ExternalKey: "no-name", Name: " "},
+		nil,
+		{ExternalKey: "removed", Name: "removed"},
+		{ExternalKey: "b", Name: "b"},
+	}}
+
+	report, err := goodService.Import(context.Background(), 1, rows, "tester")
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+
+	if report.Created != 2 || report.Updated != 0 || report.Failed != 5 {
+		t.Fatalf("got %d created, %d updated, %d failed, want 2, 0, 5", report.Created, report.Updated, report.Failed)
+	}
+
+	wantFailures := []ImportRowError{
+		{Line: 2, Reason: "external key is required"},
+		{Line: 3, Reason: "external key is too long"},
+		{Line: 4, ExternalKey: "no-name", Reason: "name is required"},
+		{Line: 5, Reason: "malformed row"},
+		{Line: 6, ExternalKey: "removed", Reason: ErrGoodRemoved.Error()},
+	}
+	if len(report.Failures) != len(wantFailures) {
+		t.Fatalf("got %d failures, want %d", len(report.Failures), len(wantFailures))
+	}
+	for i, failure := range report.Failures {
+		if *failure != wantFailures[i] {
+			t.Errorf("failure %d: got %+v, want %+v", i, *failure, wantFailures[i])
+		}
+	}
+}
+
+func TestImportStartsNewChunkOnRepeatedKey(t *testing.T) {
+	goodStorage := newFakeImportStorage(t)
+	goodService := NewGoodService(goodStorage, fakeInMemoryStorage{}, nil)
+
+	rows := &fakeImportRows{rows: []*ImportRow{
+		{ExternalKey: "a", Name: "first"},
+		{ExternalKey: "b", Name: "b"},
+		{ExternalKey: "a", Name: "second"},
+		{ExternalKey: "c", Name: "c"},
+	}}
+
+	report, err := goodService.Import(context.Background(), 1, rows, "tester")
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+
+	if report.Created != 3 || report.Updated != 1 || report.Failed != 0 {
+		t.Fatalf("got %d created, %d updated, %d failed, want 3, 1, 0", report.Created, report.Updated, report.Failed)
+	}
+	if len(goodStorage.chunks) != 2 || len(goodStorage.chunks[0]) != 2 || len(goodStorage.chunks[1]) != 2 {
+		t.Errorf("got chunks %v, want [[a b] [a c]]", goodStorage.chunks)
+	}
+	if name := goodStorage.goods["a"].Name; name != "second" {
+		t.Errorf("got name %q, want the last row of the key to win", name)
+	}
+}
+
+func TestImportSplitsRowsIntoChunks(t *testing.T) {
+	goodStorage := newFakeImportStorage(t)
+	goodService := NewGoodService(goodStorage, fakeInMemoryStorage{}, nil)
+
+	rows := &fakeImportRows{}
+	for i := 0; i < importChunkSize*2+1; i++ {
+		key := strconv.Itoa(i)
+		rows.rows = append(rows.rows, &ImportRow{ExternalKey: domain.GoodExternalKey(key), Name: domain.GoodName(key)})
+	}
+
+	report, err := goodService.Import(context.Background(), 1, rows, "tester")
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+
+	if report.Created != len(rows.rows) {
+		t.Errorf("got %d created, want %d", report.Created, len(rows.rows))
+	}
+	if len(goodStorage.chunks) != 3 || len(goodStorage.chunks[2]) != 1 {
+		t.Errorf("got %d chunks, want 2 full chunks and one of a single row", len(goodStorage.chunks))
+	}
+}
+
+func TestImportAbortsOnReadError(t *testing.T) {
+	goodStorage := newFakeImportStorage(t)
+	goodService := NewGoodService(goodStorage, fakeInMemoryStorage{}, nil)
+
+	readErr := errors.New("connection reset")
+
+	report, err := goodService.Import(context.Background(), 1, failingImportRows{err: readErr}, "tester")
+	if !errors.Is(err, readErr) {
+		t.Fatalf("got error %v, want %v", err, readErr)
+	}
+	if report == nil {
+		t.Error("report is not returned along with the error")
+	}
+}
+
+type failingImportRows struct {
+	err error
+}
+
+func (r failingImportRows) Next() (*ImportRow, error) {
+	return nil, r.err
+}
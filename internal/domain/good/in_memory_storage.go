@@ -1,11 +1,13 @@
 package good
 
 import (
+	"context"
 	"time"
 )
 
 type InMemoryStorage interface {
-	Set(key, value string, exp time.Duration) error
-	Get(key string) (string, error)
-	Delete(keys ...string) error
+	Set(ctx context.Context, key, value string, exp time.Duration) error
+	Get(ctx context.Context, key string) (string, error)
+	Delete(ctx context.Context, keys ...string) error
+	Increment(ctx context.Context, key string) (int64, error)
 }
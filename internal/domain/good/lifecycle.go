@@ -0,0 +1,52 @@
+package good
+
+import (
+	"context"
+	"errors"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+)
+
+// maxTransitionAttempts bounds how many times a transition is retried when the good changes
+// between the lifecycle check and the write and the caller did not ask for a specific version.
+const maxTransitionAttempts = 3
+
+// Lifecycle rules of a good:
+//   - an active good may be updated, reprioritized, removed and purged;
+//   - a removed good is read-only, it may only be restored or purged.
+
+func rejectRemoved(domainGood *Good) error {
+	if domainGood.Removed {
+		return ErrGoodRemoved
+	}
+	return nil
+}
+
+// transition loads the good, checks that the transition is allowed by check and performs write
+// conditioned on the version that was checked, so that the good cannot change state in between.
+// If expectedVersion is set it is used for the write instead and a concurrent change is reported
+// to the caller, otherwise the transition is retried against the fresh state of the good.
+func (g *goodServiceImpl) transition(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, expectedVersion *domain.GoodVersion, check func(domainGood *Good) error, write func(version *domain.GoodVersion) error) error {
+	for attempt := 1; ; attempt++ {
+		domainGood, err := g.goodStorage.Get(ctx, id, projectId)
+		if err != nil {
+			return err
+		}
+
+		if err = check(domainGood); err != nil {
+			return err
+		}
+
+		version := expectedVersion
+		if version == nil {
+			version = &domainGood.Version
+		}
+
+		err = write(version)
+		if errors.Is(err, storage.ErrPostgresGoodVersionMismatch) && expectedVersion == nil && attempt < maxTransitionAttempts {
+			continue
+		}
+
+		return err
+	}
+}
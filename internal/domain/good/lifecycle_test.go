@@ -0,0 +1,242 @@
+package good
+
+import (
+	"context"
+	"errors"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"testing"
+	"time"
+)
+
+const (
+	testGoodId    domain.GoodId    = 1
+	testProjectId domain.ProjectId = 1
+)
+
+// fakeGoodStorage keeps a single good and implements the writes of its lifecycle like the Postgres storage does.
+// conflicts is the number of the following writes that fail as if the good was changed concurrently.
+type fakeGoodStorage struct {
+	GoodStorage
+
+	good      Good
+	conflicts int
+	writes    int
+}
+
+func (s *fakeGoodStorage) Get(ctx context.Context, id domain.GoodId, projectId domain.ProjectId) (*Good, error) {
+	if id != s.good.Id || projectId != s.good.ProjectId {
+		return nil, storage.ErrPostgresGoodNotFound
+	}
+	good := s.good
+	return &good, nil
+}
+
+func (s *fakeGoodStorage) Update(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, name domain.GoodName, description *domain.GoodDescription, attributes domain.GoodAttributes, actor domain.Actor, expectedVersion *domain.GoodVersion) (*Good, error) {
+	return s.write(expectedVersion, func(good *Good) error {
+		if good.Removed {
+			return storage.ErrPostgresGoodRemoved
+		}
+		good.Name = name
+		return nil
+	})
+}
+
+func (s *fakeGoodStorage) Delete(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, actor domain.Actor, expectedVersion *domain.GoodVersion) (*Good, error) {
+	return s.write(expectedVersion, func(good *Good) error {
+		if good.Removed {
+			return storage.ErrPostgresGoodRemoved
+		}
+		good.Removed = true
+		return nil
+	})
+}
+
+func (s *fakeGoodStorage) Restore(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, actor domain.Actor, expectedVersion *domain.GoodVersion) (*Good, error) {
+	return s.write(expectedVersion, func(good *Good) error {
+		if !good.Removed {
+			return storage.ErrPostgresGoodNotRemoved
+		}
+		good.Removed = false
+		return nil
+	})
+}
+
+func (s *fakeGoodStorage) ChangePriority(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, newPriority domain.GoodPriority, actor domain.Actor, expectedVersion *domain.GoodVersion) ([]*Good, error) {
+	good, err := s.write(expectedVersion, func(good *Good) error {
+		if good.Removed {
+			return storage.ErrPostgresGoodRemoved
+		}
+		good.Priority = newPriority
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []*Good{good}, nil
+}
+
+func (s *fakeGoodStorage) write(expectedVersion *domain.GoodVersion, apply func(good *Good) error) (*Good, error) {
+	s.writes++
+
+	if s.conflicts > 0 {
+		s.conflicts--
+		s.good.Version++
+		return nil, storage.ErrPostgresGoodVersionMismatch
+	}
+	if expectedVersion != nil && *expectedVersion != s.good.Version {
+		return nil, storage.ErrPostgresGoodVersionMismatch
+	}
+
+	if err := apply(&s.good); err != nil {
+		return nil, err
+	}
+	s.good.Version++
+
+	good := s.good
+	return &good, nil
+}
+
+type fakeInMemoryStorage struct{}
+
+func (fakeInMemoryStorage) Set(ctx context.Context, key, value string, exp time.Duration) error {
+	return nil
+}
+
+func (fakeInMemoryStorage) Get(ctx context.Context, key string) (string, error) {
+	return "", storage.ErrRedisKeyNotFound
+}
+
+func (fakeInMemoryStorage) Delete(ctx context.Context, keys ...string) error {
+	return nil
+}
+
+func (fakeInMemoryStorage) Increment(ctx context.Context, key string) (int64, error) {
+	return 1, nil
+}
+
+func TestGoodLifecycle(t *testing.T) {
+	update := func(goodService GoodService, expectedVersion *domain.GoodVersion) error {
+		_, err := goodService.Update(context.Background(), testGoodId, testProjectId, "name", nil, nil, "tester", expectedVersion)
+		return err
+	}
+	reprioritize := func(goodService GoodService, expectedVersion *domain.GoodVersion) error {
+		_, err := goodService.ChangePriority(context.Background(), testGoodId, testProjectId, 2, "tester", expectedVersion)
+		return err
+	}
+	remove := func(goodService GoodService, expectedVersion *domain.GoodVersion) error {
+		_, err := goodService.Delete(context.Background(), testGoodId, testProjectId, "tester", expectedVersion)
+		return err
+	}
+	restore := func(goodService GoodService, expectedVersion *domain.GoodVersion) error {
+		_, err := goodService.Restore(context.Background(), testGoodId, testProjectId, "tester", expectedVersion)
+		return err
+	}
+
+	staleVersion := domain.GoodVersion(0)
+
+	tests := []struct {
+		name            string
+		removed         domain.GoodRemoved
+		conflicts       int
+		expectedVersion *domain.GoodVersion
+		transition      func(goodService GoodService, expectedVersion *domain.GoodVersion) error
+		wantErr         error
+		wantRemoved     domain.GoodRemoved
+		wantWrites      int
+	}{
+		{name: "update active", transition: update, wantWrites: 1},
+		{name: "update removed", removed: true, transition: update, wantErr: ErrGoodRemoved, wantRemoved: true},
+		{name: "reprioritize active", transition: reprioritize, wantWrites: 1},
+		{name: "reprioritize removed", removed: true, transition: reprioritize, wantErr: ErrGoodRemoved, wantRemoved: true},
+		{name: "delete active", transition: remove, wantRemoved: true, wantWrites: 1},
+		{name: "delete removed", removed: true, transition: remove, wantErr: ErrGoodRemoved, wantRemoved: true},
+		{name: "restore removed", removed: true, transition: restore, wantWrites: 1},
+		{name: "restore active", transition: restore, wantErr: ErrGoodNotRemoved, wantWrites: 1},
+		{
+			name:       "update retried after concurrent changes",
+			conflicts:  maxTransitionAttempts - 1,
+			transition: update,
+			wantWrites: maxTransitionAttempts,
+		},
+		{
+			name:       "update gives up after maxTransitionAttempts",
+			conflicts:  maxTransitionAttempts,
+			transition: update,
+			wantErr:    ErrGoodVersionMismatch,
+			wantWrites: maxTransitionAttempts,
+		},
+		{
+			name:       "delete gives up after maxTransitionAttempts",
+			conflicts:  maxTransitionAttempts,
+			transition: remove,
+			wantErr:    ErrGoodVersionMismatch,
+			wantWrites: maxTransitionAttempts,
+		},
+		{
+			name:            "update with stale If-Match is not retried",
+			expectedVersion: &staleVersion,
+			transition:      update,
+			wantErr:         ErrGoodVersionMismatch,
+			wantWrites:      1,
+		},
+		{
+			name:            "reprioritize with stale If-Match is not retried",
+			expectedVersion: &staleVersion,
+			transition:      reprioritize,
+			wantErr:         ErrGoodVersionMismatch,
+			wantWrites:      1,
+		},
+		{
+			name:            "delete with stale If-Match is not retried",
+			expectedVersion: &staleVersion,
+			transition:      remove,
+			wantErr:         ErrGoodVersionMismatch,
+			wantWrites:      1,
+		},
+		{
+			name:            "restore with stale If-Match",
+			removed:         true,
+			expectedVersion: &staleVersion,
+			transition:      restore,
+			wantErr:         ErrGoodVersionMismatch,
+			wantRemoved:     true,
+			wantWrites:      1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			goodStorage := &fakeGoodStorage{
+				good:      Good{Id: testGoodId, ProjectId: testProjectId, Removed: test.removed, Version: 1},
+				conflicts: test.conflicts,
+			}
+			goodService := NewGoodService(goodStorage, fakeInMemoryStorage{}, nil)
+
+			err := test.transition(goodService, test.expectedVersion)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("got error %v, want %v", err, test.wantErr)
+			}
+			if goodStorage.good.Removed != test.wantRemoved {
+				t.Errorf("got removed %v, want %v", goodStorage.good.Removed, test.wantRemoved)
+			}
+			if goodStorage.writes != test.wantWrites {
+				t.Errorf("got %d writes, want %d", goodStorage.writes, test.wantWrites)
+			}
+		})
+	}
+}
+
+func TestGoodDeletedTwice(t *testing.T) {
+	goodStorage := &fakeGoodStorage{good: Good{Id: testGoodId, ProjectId: testProjectId, Version: 1}}
+	goodService := NewGoodService(goodStorage, fakeInMemoryStorage{}, nil)
+
+	if _, err := goodService.Delete(context.Background(), testGoodId, testProjectId, "tester", nil); err != nil {
+		t.Fatalf("first delete: %v", err)
+	}
+
+	_, err := goodService.Delete(context.Background(), testGoodId, testProjectId, "tester", nil)
+	if !errors.Is(err, ErrGoodRemoved) {
+		t.Fatalf("second delete: got error %v, want %v", err, ErrGoodRemoved)
+	}
+}
@@ -0,0 +1,15 @@
+package good
+
+import "github.com/vaberof/hezzl-backend/pkg/domain"
+
+// ListFilter narrows the goods returned by List and Export; nil fields do not filter.
+type ListFilter struct {
+	ProjectId *domain.ProjectId
+	Removed   *bool
+	// Tags keeps the goods that have all of the tags.
+	Tags []domain.TagName
+	// Attributes keeps the goods whose attributes contain all of these attributes.
+	Attributes domain.GoodAttributes
+	// TopLevel keeps the goods that are not variants.
+	TopLevel bool
+}
@@ -0,0 +1,9 @@
+package good
+
+// Price is an amount of money in the minor units of its currency, e.g. 1999 EUR is 19.99 euros.
+// Amounts are integers, so that prices are never subject to rounding.
+type Price struct {
+	Amount int64
+	// Currency is an ISO 4217 alphabetic code.
+	Currency string
+}
@@ -0,0 +1,59 @@
+package good
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+)
+
+var ErrInvalidPrice = errors.New("invalid price")
+
+// SetPrice sets the price of the good, or removes it if price is nil.
+func (g *goodServiceImpl) SetPrice(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, price *Price, actor domain.Actor, expectedVersion *domain.GoodVersion) (*Good, error) {
+	ctx, span := tracer.Start(ctx, "GoodService.SetPrice")
+	defer span.End()
+
+	if price != nil {
+		if err := validatePrice(price); err != nil {
+			return nil, err
+		}
+	}
+
+	var domainGood *Good
+
+	err := g.transition(ctx, id, projectId, expectedVersion, rejectRemoved, func(version *domain.GoodVersion) (err error) {
+		domainGood, err = g.goodStorage.SetPrice(ctx, id, projectId, price, actor, version)
+		return err
+	})
+	if err != nil {
+		return nil, g.mapWriteError(ctx, id, projectId, err)
+	}
+
+	err = g.inMemoryStorage.Delete(ctx, g.getGoodCacheKey(id, projectId))
+	if err != nil {
+		if !errors.Is(err, storage.ErrRedisKeyNotFound) {
+			return nil, err
+		}
+	}
+
+	return domainGood, nil
+}
+
+func validatePrice(price *Price) error {
+	if price.Amount < 0 {
+		return fmt.Errorf("%w: amount must not be negative", ErrInvalidPrice)
+	}
+
+	if len(price.Currency) != 3 {
+		return fmt.Errorf("%w: currency must be an ISO 4217 code", ErrInvalidPrice)
+	}
+	for i := 0; i < len(price.Currency); i++ {
+		if price.Currency[i] < 'A' || price.Currency[i] > 'Z' {
+			return fmt.Errorf("%w: currency must be an ISO 4217 code", ErrInvalidPrice)
+		}
+	}
+
+	return nil
+}
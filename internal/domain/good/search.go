@@ -0,0 +1,12 @@
+package good
+
+// SearchResult is a good matched by a search along with its relevance and the matched parts of its text.
+type SearchResult struct {
+	Good *Good
+	// Rank orders the results; it is higher for better matches.
+	Rank float64
+	// NameHighlight and DescriptionHighlight are the name and a snippet of the description
+	// HTML-escaped, with the matched words wrapped in <mark> tags; they are empty if only a fuzzy match was found.
+	NameHighlight        string
+	DescriptionHighlight string
+}
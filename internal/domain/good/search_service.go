@@ -0,0 +1,15 @@
+package good
+
+import (
+	"context"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+)
+
+// Search finds the goods of the project whose name or description match text, best matches first.
+// Removed goods are not searched. Results are not cached since queries rarely repeat.
+func (g *goodServiceImpl) Search(ctx context.Context, projectId domain.ProjectId, text string, limit, offset int) ([]*SearchResult, error) {
+	ctx, span := tracer.Start(ctx, "GoodService.Search")
+	defer span.End()
+
+	return g.goodStorage.Search(ctx, projectId, text, limit, offset)
+}
@@ -0,0 +1,32 @@
+package good
+
+import (
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"time"
+)
+
+type StockMovementKind string
+
+const (
+	// StockMovementReserve reserves a quantity of the available stock, e.g. for an order.
+	StockMovementReserve StockMovementKind = "reserve"
+	// StockMovementRelease returns a reserved quantity to the available stock.
+	StockMovementRelease StockMovementKind = "release"
+	// StockMovementAdjust adds a quantity to the stock, or removes it if the quantity is negative.
+	StockMovementAdjust StockMovementKind = "adjust"
+)
+
+// StockMovement is an entry of the stock ledger of a good. Stock and Reserved are the quantities
+// of the good after the movement.
+type StockMovement struct {
+	Id        domain.StockMovementId
+	GoodId    domain.GoodId
+	ProjectId domain.ProjectId
+	Kind      StockMovementKind
+	Quantity  int64
+	Stock     int64
+	Reserved  int64
+	Reason    string
+	CreatedAt time.Time
+	CreatedBy domain.Actor
+}
@@ -0,0 +1,94 @@
+package good
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"unicode/utf8"
+)
+
+const maxStockMovementReasonLength = 255
+
+var (
+	ErrInvalidStockMovement = errors.New("invalid stock movement")
+	ErrInsufficientStock    = errors.New("insufficient stock")
+)
+
+// ReserveStock reserves quantity of the stock of the good that is not reserved yet.
+func (g *goodServiceImpl) ReserveStock(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, quantity int64, reason string, actor domain.Actor) (*StockMovement, error) {
+	ctx, span := tracer.Start(ctx, "GoodService.ReserveStock")
+	defer span.End()
+
+	if quantity <= 0 {
+		return nil, fmt.Errorf("%w: quantity must be positive", ErrInvalidStockMovement)
+	}
+
+	return g.moveStock(ctx, id, projectId, StockMovementReserve, quantity, reason, actor)
+}
+
+// ReleaseStock releases quantity of the reserved stock of the good.
+func (g *goodServiceImpl) ReleaseStock(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, quantity int64, reason string, actor domain.Actor) (*StockMovement, error) {
+	ctx, span := tracer.Start(ctx, "GoodService.ReleaseStock")
+	defer span.End()
+
+	if quantity <= 0 {
+		return nil, fmt.Errorf("%w: quantity must be positive", ErrInvalidStockMovement)
+	}
+
+	return g.moveStock(ctx, id, projectId, StockMovementRelease, quantity, reason, actor)
+}
+
+// AdjustStock changes the stock of the good by delta, e.g. after a delivery or a stocktaking.
+// The stock cannot drop below the reserved stock.
+func (g *goodServiceImpl) AdjustStock(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, delta int64, reason string, actor domain.Actor) (*StockMovement, error) {
+	ctx, span := tracer.Start(ctx, "GoodService.AdjustStock")
+	defer span.End()
+
+	if delta == 0 {
+		return nil, fmt.Errorf("%w: quantity must not be zero", ErrInvalidStockMovement)
+	}
+
+	return g.moveStock(ctx, id, projectId, StockMovementAdjust, delta, reason, actor)
+}
+
+func (g *goodServiceImpl) ListStockMovements(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, limit, offset int) ([]*StockMovement, error) {
+	ctx, span := tracer.Start(ctx, "GoodService.ListStockMovements")
+	defer span.End()
+
+	stockMovements, err := g.goodStorage.ListStockMovements(ctx, id, projectId, limit, offset)
+	if err != nil {
+		if errors.Is(err, storage.ErrPostgresGoodNotFound) {
+			return nil, ErrGoodNotFound
+		}
+		return nil, err
+	}
+
+	return stockMovements, nil
+}
+
+// moveStock records the movement and applies it to the good in one transaction of the storage.
+// Stock is part of the good's data, so the stock of a removed good cannot be moved.
+func (g *goodServiceImpl) moveStock(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, kind StockMovementKind, quantity int64, reason string, actor domain.Actor) (*StockMovement, error) {
+	if utf8.RuneCountInString(reason) > maxStockMovementReasonLength {
+		return nil, fmt.Errorf("%w: reason is longer than %d characters", ErrInvalidStockMovement, maxStockMovementReasonLength)
+	}
+
+	stockMovement, err := g.goodStorage.MoveStock(ctx, id, projectId, kind, quantity, reason, actor)
+	if err != nil {
+		if errors.Is(err, storage.ErrPostgresInsufficientStock) {
+			return nil, ErrInsufficientStock
+		}
+		return nil, g.mapWriteError(ctx, id, projectId, err)
+	}
+
+	err = g.inMemoryStorage.Delete(ctx, g.getGoodCacheKey(id, projectId))
+	if err != nil {
+		if !errors.Is(err, storage.ErrRedisKeyNotFound) {
+			return nil, err
+		}
+	}
+
+	return stockMovement, nil
+}
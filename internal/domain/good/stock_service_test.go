@@ -0,0 +1,148 @@
+package good
+
+import (
+	"context"
+	"errors"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"strings"
+	"testing"
+)
+
+// fakeStockStorage moves the stock of a single good and rejects the movements that violate the checks
+// of the goods table: stock and reserved stock are not negative and no more than the stock is reserved.
+type fakeStockStorage struct {
+	GoodStorage
+
+	good  Good
+	moves int
+}
+
+func (s *fakeStockStorage) MoveStock(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, kind StockMovementKind, quantity int64, reason string, actor domain.Actor) (*StockMovement, error) {
+	s.moves++
+
+	if id != s.good.Id || projectId != s.good.ProjectId {
+		return nil, storage.ErrPostgresGoodNotFound
+	}
+	if s.good.Removed {
+		return nil, storage.ErrPostgresGoodRemoved
+	}
+
+	stock, reserved := s.good.Stock, s.good.Reserved
+
+	switch kind {
+	case StockMovementReserve:
+		reserved += quantity
+	case StockMovementRelease:
+		reserved -= quantity
+	case StockMovementAdjust:
+		stock += quantity
+	}
+
+	if stock < 0 || reserved < 0 || reserved > stock {
+		return nil, storage.ErrPostgresInsufficientStock
+	}
+	s.good.Stock, s.good.Reserved = stock, reserved
+
+	return &StockMovement{GoodId: id, ProjectId: projectId, Kind: kind, Quantity: quantity, Stock: stock, Reserved: reserved, Reason: reason}, nil
+}
+
+func TestStockNeverGoesNegative(t *testing.T) {
+	ctx := context.Background()
+
+	goodStorage := &fakeStockStorage{good: Good{Id: testGoodId, ProjectId: testProjectId}}
+	goodService := NewGoodService(goodStorage, fakeInMemoryStorage{}, nil)
+
+	reserve := func(quantity int64) error {
+		_, err := goodService.ReserveStock(ctx, testGoodId, testProjectId, quantity, "", "tester")
+		return err
+	}
+	release := func(quantity int64) error {
+		_, err := goodService.ReleaseStock(ctx, testGoodId, testProjectId, quantity, "", "tester")
+		return err
+	}
+	adjust := func(delta int64) error {
+		_, err := goodService.AdjustStock(ctx, testGoodId, testProjectId, delta, "", "tester")
+		return err
+	}
+
+	steps := []struct {
+		name         string
+		move         func(quantity int64) error
+		quantity     int64
+		wantErr      error
+		wantStock    int64
+		wantReserved int64
+	}{
+		{"reserve without stock", reserve, 1, ErrInsufficientStock, 0, 0},
+		{"adjust below zero", adjust, -1, ErrInsufficientStock, 0, 0},
+		{"deliver", adjust, 5, nil, 5, 0},
+		{"reserve", reserve, 3, nil, 5, 3},
+		{"reserve more than available", reserve, 3, ErrInsufficientStock, 5, 3},
+		{"adjust below reserved", adjust, -3, ErrInsufficientStock, 5, 3},
+		{"release more than reserved", release, 4, ErrInsufficientStock, 5, 3},
+		{"release", release, 3, nil, 5, 0},
+		{"sell out", adjust, -5, nil, 0, 0},
+		{"adjust sold out below zero", adjust, -1, ErrInsufficientStock, 0, 0},
+	}
+
+	for _, step := range steps {
+		err := step.move(step.quantity)
+		if !errors.Is(err, step.wantErr) {
+			t.Fatalf("%s: got error %v, want %v", step.name, err, step.wantErr)
+		}
+		if goodStorage.good.Stock != step.wantStock || goodStorage.good.Reserved != step.wantReserved {
+			t.Fatalf("%s: got stock %d reserved %d, want %d and %d", step.name, goodStorage.good.Stock, goodStorage.good.Reserved, step.wantStock, step.wantReserved)
+		}
+	}
+}
+
+func TestInvalidStockMovementsAreRejected(t *testing.T) {
+	ctx := context.Background()
+
+	goodStorage := &fakeStockStorage{good: Good{Id: testGoodId, ProjectId: testProjectId, Stock: 5}}
+	goodService := NewGoodService(goodStorage, fakeInMemoryStorage{}, nil)
+
+	longReason := strings.Repeat("r", maxStockMovementReasonLength+1)
+
+	moves := map[string]func() error{
+		"reserve zero": func() error {
+			_, err := goodService.ReserveStock(ctx, testGoodId, testProjectId, 0, "", "tester")
+			return err
+		},
+		"reserve negative": func() error {
+			_, err := goodService.ReserveStock(ctx, testGoodId, testProjectId, -1, "", "tester")
+			return err
+		},
+		"release negative": func() error {
+			_, err := goodService.ReleaseStock(ctx, testGoodId, testProjectId, -1, "", "tester")
+			return err
+		},
+		"adjust zero": func() error {
+			_, err := goodService.AdjustStock(ctx, testGoodId, testProjectId, 0, "", "tester")
+			return err
+		},
+		"reason too long": func() error {
+			_, err := goodService.AdjustStock(ctx, testGoodId, testProjectId, 1, longReason, "tester")
+			return err
+		},
+	}
+
+	for name, move := range moves {
+		if err := move(); !errors.Is(err, ErrInvalidStockMovement) {
+			t.Errorf("%s: got error %v, want %v", name, err, ErrInvalidStockMovement)
+		}
+	}
+	if goodStorage.moves != 0 {
+		t.Errorf("got %d stock movements stored, want none", goodStorage.moves)
+	}
+
+	goodStorage.good.Removed = true
+
+	if _, err := goodService.AdjustStock(ctx, testGoodId, testProjectId, 1, "", "tester"); !errors.Is(err, ErrGoodRemoved) {
+		t.Errorf("got error %v, want %v", err, ErrGoodRemoved)
+	}
+	if goodStorage.good.Stock != 5 {
+		t.Errorf("stock of a removed good changed to %d", goodStorage.good.Stock)
+	}
+}
@@ -0,0 +1,9 @@
+package good
+
+import "github.com/vaberof/hezzl-backend/pkg/domain"
+
+// Tag is a category of goods within a project; GoodsCount counts the tagged goods that are not removed.
+type Tag struct {
+	Name       domain.TagName
+	GoodsCount int
+}
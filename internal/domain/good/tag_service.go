@@ -0,0 +1,111 @@
+package good
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxTagsPerRequest = 50
+	maxTagNameLength  = 64
+)
+
+var ErrInvalidTags = errors.New("invalid tags")
+
+// AddTags tags the good, creating the tags of the project that do not exist yet, and returns all tags of the good.
+// Tags are part of the good's data, so a removed good cannot be tagged.
+func (g *goodServiceImpl) AddTags(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, tags []domain.TagName, actor domain.Actor) ([]domain.TagName, error) {
+	ctx, span := tracer.Start(ctx, "GoodService.AddTags")
+	defer span.End()
+
+	tags, err := normalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	goodTags, err := g.goodStorage.AddTags(ctx, id, projectId, tags, actor)
+	if err != nil {
+		return nil, g.mapWriteError(ctx, id, projectId, err)
+	}
+
+	return goodTags, nil
+}
+
+// RemoveTags untags the good and returns its remaining tags; tags the good does not have are ignored.
+func (g *goodServiceImpl) RemoveTags(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, tags []domain.TagName, actor domain.Actor) ([]domain.TagName, error) {
+	ctx, span := tracer.Start(ctx, "GoodService.RemoveTags")
+	defer span.End()
+
+	tags, err := normalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	goodTags, err := g.goodStorage.RemoveTags(ctx, id, projectId, tags, actor)
+	if err != nil {
+		return nil, g.mapWriteError(ctx, id, projectId, err)
+	}
+
+	return goodTags, nil
+}
+
+func (g *goodServiceImpl) GetTags(ctx context.Context, id domain.GoodId, projectId domain.ProjectId) ([]domain.TagName, error) {
+	ctx, span := tracer.Start(ctx, "GoodService.GetTags")
+	defer span.End()
+
+	goodTags, err := g.goodStorage.GetTags(ctx, id, projectId)
+	if err != nil {
+		if errors.Is(err, storage.ErrPostgresGoodNotFound) {
+			return nil, ErrGoodNotFound
+		}
+		return nil, err
+	}
+
+	return goodTags, nil
+}
+
+func (g *goodServiceImpl) ListTags(ctx context.Context, projectId domain.ProjectId) ([]*Tag, error) {
+	ctx, span := tracer.Start(ctx, "GoodService.ListTags")
+	defer span.End()
+
+	return g.goodStorage.ListTags(ctx, projectId)
+}
+
+// normalizeTags trims the tag names and drops duplicates, keeping the order of the first occurrences.
+func normalizeTags(tags []domain.TagName) ([]domain.TagName, error) {
+	if len(tags) == 0 {
+		return nil, fmt.Errorf("%w: no tags given", ErrInvalidTags)
+	}
+	if len(tags) > maxTagsPerRequest {
+		return nil, fmt.Errorf("%w: at most %d tags may be given at once", ErrInvalidTags, maxTagsPerRequest)
+	}
+
+	normalizedTags := make([]domain.TagName, 0, len(tags))
+	seen := make(map[domain.TagName]struct{}, len(tags))
+
+	for _, tag := range tags {
+		tag = domain.TagName(strings.TrimSpace(tag.String()))
+
+		switch {
+		case tag == "":
+			return nil, fmt.Errorf("%w: tag must not be empty", ErrInvalidTags)
+		case utf8.RuneCountInString(tag.String()) > maxTagNameLength:
+			return nil, fmt.Errorf("%w: tag %q is longer than %d characters", ErrInvalidTags, tag, maxTagNameLength)
+		case strings.Contains(tag.String(), ","):
+			return nil, fmt.Errorf("%w: tag %q must not contain commas", ErrInvalidTags, tag)
+		}
+
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		normalizedTags = append(normalizedTags, tag)
+	}
+
+	return normalizedTags, nil
+}
@@ -0,0 +1,61 @@
+package good
+
+import (
+	"errors"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tooMany := make([]domain.TagName, maxTagsPerRequest+1)
+	for i := range tooMany {
+		tooMany[i] = "tag"
+	}
+
+	tests := []struct {
+		name    string
+		tags    []domain.TagName
+		want    []domain.TagName
+		wantErr bool
+	}{
+		{name: "trimmed", tags: []domain.TagName{" sale ", "new"}, want: []domain.TagName{"sale", "new"}},
+		{name: "duplicates dropped in order", tags: []domain.TagName{"b", "a", " b", "a"}, want: []domain.TagName{"b", "a"}},
+		{name: "no tags", tags: nil, wantErr: true},
+		{name: "too many tags", tags: tooMany, wantErr: true},
+		{name: "blank tag", tags: []domain.TagName{"sale", "  "}, wantErr: true},
+		{name: "too long tag", tags: []domain.TagName{domain.TagName(strings.Repeat("т", maxTagNameLength+1))}, wantErr: true},
+		{name: "tag with comma", tags: []domain.TagName{"a,b"}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := normalizeTags(test.tags)
+			if test.wantErr {
+				if !errors.Is(err, ErrInvalidTags) {
+					t.Fatalf("got error %v, want %v", err, ErrInvalidTags)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("normalize tags: %v", err)
+			}
+			if !slices.Equal(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestGoodListCacheKeyIgnoresTagOrder(t *testing.T) {
+	goodService := &goodServiceImpl{}
+
+	key := goodService.getGoodListCacheKey(&ListFilter{Tags: []domain.TagName{"sale", "new"}}, "0", 10, 0, nil)
+	if reordered := goodService.getGoodListCacheKey(&ListFilter{Tags: []domain.TagName{"new", "sale"}}, "0", 10, 0, nil); reordered != key {
+		t.Errorf("got key %q for reordered tags, want %q", reordered, key)
+	}
+	if other := goodService.getGoodListCacheKey(&ListFilter{Tags: []domain.TagName{"sale"}}, "0", 10, 0, nil); other == key {
+		t.Errorf("filters by different tags share the key %q", key)
+	}
+}
@@ -0,0 +1,8 @@
+package good
+
+// TransferOptions select what is transferred to another project along with a good and its variants.
+// Translations always follow the good; tags are recreated in the target project by name.
+type TransferOptions struct {
+	Tags        bool
+	Attachments bool
+}
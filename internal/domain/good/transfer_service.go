@@ -0,0 +1,141 @@
+package good
+
+import (
+	"context"
+	"errors"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+)
+
+var (
+	ErrInvalidTransfer     = errors.New("good cannot be transferred to its own project")
+	ErrVariantTransfer     = errors.New("variants are transferred with their parent")
+	ErrExternalKeyConflict = errors.New("external key is taken in the target project")
+)
+
+// Move reassigns the good and its variants to the target project, where the good is prioritized last.
+// Tags and attachments are kept only if options ask for them; the blobs of dropped attachments are deleted.
+// The goods must satisfy the attribute schema of the target project.
+func (g *goodServiceImpl) Move(ctx context.Context, id domain.GoodId, projectId, targetProjectId domain.ProjectId, options *TransferOptions, actor domain.Actor, expectedVersion *domain.GoodVersion) (*Good, error) {
+	ctx, span := tracer.Start(ctx, "GoodService.Move")
+	defer span.End()
+
+	if targetProjectId == projectId {
+		return nil, ErrInvalidTransfer
+	}
+
+	var domainGood *Good
+
+	err := g.transition(ctx, id, projectId, expectedVersion, func(domainGood *Good) error {
+		return g.checkTransfer(ctx, domainGood, targetProjectId)
+	}, func(version *domain.GoodVersion) (err error) {
+		domainGood, err = g.goodStorage.Move(ctx, id, projectId, targetProjectId, options, actor, version)
+		return err
+	})
+	if err != nil {
+		return nil, g.mapTransferError(ctx, id, projectId, err)
+	}
+
+	if !options.Attachments {
+		g.tryDeleteQueuedBlobs(ctx)
+	}
+
+	// The good is cached under its project in both projects' key spaces.
+	goodCacheKeys := g.getGoodCacheKeysWithVariants(domainGood)
+	for _, domainVariant := range append([]*Good{domainGood}, domainGood.Variants...) {
+		goodCacheKeys = append(goodCacheKeys, g.getGoodCacheKey(domainVariant.Id, projectId))
+	}
+
+	err = g.inMemoryStorage.Delete(ctx, goodCacheKeys...)
+	if err != nil {
+		if !errors.Is(err, storage.ErrRedisKeyNotFound) {
+			return nil, err
+		}
+	}
+
+	// The good leaves the lists of the source project and joins those of the target project.
+	if err = g.invalidateGoodLists(ctx, projectId, targetProjectId); err != nil {
+		return nil, err
+	}
+
+	return domainGood, nil
+}
+
+// Copy duplicates the good and its variants that are not removed into the target project, where the copy
+// is prioritized last. The copies start without stock and without external keys, which identify the original.
+// Copied attachments share the blobs of the originals.
+func (g *goodServiceImpl) Copy(ctx context.Context, id domain.GoodId, projectId, targetProjectId domain.ProjectId, options *TransferOptions, actor domain.Actor) (*Good, error) {
+	ctx, span := tracer.Start(ctx, "GoodService.Copy")
+	defer span.End()
+
+	if targetProjectId == projectId {
+		return nil, ErrInvalidTransfer
+	}
+
+	domainGood, err := g.goodStorage.Get(ctx, id, projectId)
+	if err != nil {
+		return nil, g.mapTransferError(ctx, id, projectId, err)
+	}
+
+	if err = g.checkTransfer(ctx, domainGood, targetProjectId); err != nil {
+		return nil, err
+	}
+
+	domainCopy, err := g.goodStorage.Copy(ctx, id, projectId, targetProjectId, options, actor)
+	if err != nil {
+		return nil, g.mapTransferError(ctx, id, projectId, err)
+	}
+
+	// The source is left as it is; only the new keys of the target project are cleared.
+	err = g.inMemoryStorage.Delete(ctx, g.getGoodCacheKeysWithVariants(domainCopy)...)
+	if err != nil {
+		if !errors.Is(err, storage.ErrRedisKeyNotFound) {
+			return nil, err
+		}
+	}
+
+	if err = g.invalidateGoodLists(ctx, targetProjectId); err != nil {
+		return nil, err
+	}
+
+	return domainCopy, nil
+}
+
+// checkTransfer checks that the good is an active good that is not a variant and that the attributes
+// of the good and its variants satisfy the attribute schema of the target project.
+func (g *goodServiceImpl) checkTransfer(ctx context.Context, domainGood *Good, targetProjectId domain.ProjectId) error {
+	if err := rejectRemoved(domainGood); err != nil {
+		return err
+	}
+	if domainGood.ParentId != nil {
+		return ErrVariantTransfer
+	}
+
+	variants, err := g.goodStorage.ListVariants(ctx, []domain.GoodId{domainGood.Id})
+	if err != nil {
+		return err
+	}
+
+	attributeSchemas := g.newAttributeSchemas()
+
+	for _, transferredGood := range append([]*Good{domainGood}, variants...) {
+		if err = attributeSchemas.validate(ctx, targetProjectId, transferredGood.Attributes); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (g *goodServiceImpl) mapTransferError(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, err error) error {
+	switch {
+	case errors.Is(err, storage.ErrPostgresProjectNotFound):
+		return ErrProjectNotFound
+	case errors.Is(err, storage.ErrPostgresVariantTransfer):
+		return ErrVariantTransfer
+	case errors.Is(err, storage.ErrPostgresExternalKeyConflict):
+		return ErrExternalKeyConflict
+	default:
+		return g.mapWriteError(ctx, id, projectId, err)
+	}
+}
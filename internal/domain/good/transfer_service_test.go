@@ -0,0 +1,227 @@
+package good
+
+import (
+	"context"
+	"errors"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeTransferStorage moves goods between projects on top of fakeAttachmentStorage. Attachments that are not
+// kept by a move are deleted, and their blobs are queued once no other attachment refers to them.
+type fakeTransferStorage struct {
+	*fakeAttachmentStorage
+}
+
+func (s fakeTransferStorage) Move(ctx context.Context, id domain.GoodId, projectId, targetProjectId domain.ProjectId, options *TransferOptions, actor domain.Actor, expectedVersion *domain.GoodVersion) (*Good, error) {
+	good := s.goods[id]
+	if *expectedVersion != good.Version {
+		return nil, storage.ErrPostgresGoodVersionMismatch
+	}
+	good.ProjectId = targetProjectId
+	good.Version++
+
+	for _, attachment := range s.attachmentsOf(id) {
+		if options.Attachments {
+			attachment.ProjectId = targetProjectId
+			continue
+		}
+		if _, _, err := s.DeleteAttachment(ctx, id, projectId, attachment.Id); err != nil {
+			return nil, err
+		}
+	}
+
+	moved := *good
+	return &moved, nil
+}
+
+func (s fakeTransferStorage) List(ctx context.Context, filter *ListFilter, limit, offset int) ([]*Good, error) {
+	var goods []*Good
+	for _, good := range s.goods {
+		if filter != nil && filter.ProjectId != nil && good.ProjectId != *filter.ProjectId {
+			continue
+		}
+		copied := *good
+		goods = append(goods, &copied)
+	}
+	slices.SortFunc(goods, func(a, b *Good) int { return int(a.Id - b.Id) })
+	return goods, nil
+}
+
+func (s fakeTransferStorage) GetDefaultLocale(ctx context.Context, projectId domain.ProjectId) (domain.Locale, error) {
+	return "en", nil
+}
+
+// mapInMemoryStorage is an in-memory storage that keeps values until they are deleted.
+type mapInMemoryStorage struct {
+	values map[string]string
+}
+
+func newMapInMemoryStorage() *mapInMemoryStorage {
+	return &mapInMemoryStorage{values: make(map[string]string)}
+}
+
+func (s *mapInMemoryStorage) Set(ctx context.Context, key, value string, exp time.Duration) error {
+	s.values[key] = value
+	return nil
+}
+
+func (s *mapInMemoryStorage) Get(ctx context.Context, key string) (string, error) {
+	value, ok := s.values[key]
+	if !ok {
+		return "", storage.ErrRedisKeyNotFound
+	}
+	return value, nil
+}
+
+func (s *mapInMemoryStorage) Delete(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		delete(s.values, key)
+	}
+	return nil
+}
+
+func (s *mapInMemoryStorage) Increment(ctx context.Context, key string) (int64, error) {
+	value, _ := strconv.ParseInt(s.values[key], 10, 64)
+	value++
+	s.values[key] = strconv.FormatInt(value, 10)
+	return value, nil
+}
+
+func TestMoveAndCopyInvalidateCachedListsOfBothProjects(t *testing.T) {
+	ctx := context.Background()
+
+	goodStorage := fakeTransferStorage{newFakeAttachmentStorage()}
+	goodService := NewGoodService(goodStorage, newMapInMemoryStorage(), nil)
+
+	moved := goodStorage.addGood(1)
+	kept := goodStorage.addGood(1)
+
+	listIds := func(projectId *domain.ProjectId) []domain.GoodId {
+		t.Helper()
+
+		goods, err := goodService.List(ctx, &ListFilter{ProjectId: projectId}, 10, 0, nil)
+		if err != nil {
+			t.Fatalf("list: %v", err)
+		}
+		var ids []domain.GoodId
+		for _, good := range goods {
+			ids = append(ids, good.Id)
+		}
+		return ids
+	}
+
+	source, target, copyTarget := domain.ProjectId(1), domain.ProjectId(2), domain.ProjectId(3)
+
+	// The lists are cached before the transfers.
+	listIds(&source)
+	listIds(&target)
+	listIds(&copyTarget)
+	listIds(nil)
+
+	if _, err := goodService.Move(ctx, moved.Id, source, target, &TransferOptions{}, "tester", nil); err != nil {
+		t.Fatalf("move: %v", err)
+	}
+
+	if ids := listIds(&source); !slices.Equal(ids, []domain.GoodId{kept.Id}) {
+		t.Errorf("got source list %v after move, want %v", ids, []domain.GoodId{kept.Id})
+	}
+	if ids := listIds(&target); !slices.Equal(ids, []domain.GoodId{moved.Id}) {
+		t.Errorf("got target list %v after move, want %v", ids, []domain.GoodId{moved.Id})
+	}
+
+	copied, err := goodService.Copy(ctx, moved.Id, target, copyTarget, &TransferOptions{}, "tester")
+	if err != nil {
+		t.Fatalf("copy: %v", err)
+	}
+
+	if ids := listIds(&copyTarget); !slices.Equal(ids, []domain.GoodId{copied.Id}) {
+		t.Errorf("got target list %v after copy, want %v", ids, []domain.GoodId{copied.Id})
+	}
+	if ids := listIds(nil); len(ids) != 3 {
+		t.Errorf("got list of all projects %v after copy, want 3 goods", ids)
+	}
+}
+
+func TestMoveDeletesOnlyBlobsNoCopyRefersTo(t *testing.T) {
+	ctx := context.Background()
+
+	goodStorage := fakeTransferStorage{newFakeAttachmentStorage()}
+	blobStore := &fakeBlobStore{blobs: make(map[string][]byte)}
+	goodService := NewGoodService(goodStorage, fakeInMemoryStorage{}, blobStore)
+
+	original := goodStorage.addGood(1)
+
+	attachment, err := goodService.AddAttachment(ctx, original.Id, original.ProjectId, "photo.txt", "text/plain", 5, strings.NewReader("photo"), "tester")
+	if err != nil {
+		t.Fatalf("add attachment: %v", err)
+	}
+
+	copied, err := goodService.Copy(ctx, original.Id, original.ProjectId, 2, &TransferOptions{Attachments: true}, "tester")
+	if err != nil {
+		t.Fatalf("copy: %v", err)
+	}
+
+	if _, err = goodService.Move(ctx, original.Id, original.ProjectId, 3, &TransferOptions{}, "tester", nil); err != nil {
+		t.Fatalf("move original without attachments: %v", err)
+	}
+	if _, ok := blobStore.blobs[attachment.StorageKey]; !ok {
+		t.Fatal("blob shared with the copy is deleted by moving the original without attachments")
+	}
+
+	if _, err = goodService.Move(ctx, copied.Id, copied.ProjectId, 3, &TransferOptions{}, "tester", nil); err != nil {
+		t.Fatalf("move copy without attachments: %v", err)
+	}
+	if _, ok := blobStore.blobs[attachment.StorageKey]; ok {
+		t.Error("blob is kept after the last attachment referring to it was dropped")
+	}
+	if len(goodStorage.blobQueue) != 0 {
+		t.Errorf("got %d queued blob deletions, want none", len(goodStorage.blobQueue))
+	}
+}
+
+func TestTransferRejectsInvalidGoods(t *testing.T) {
+	ctx := context.Background()
+
+	goodStorage := fakeTransferStorage{newFakeAttachmentStorage()}
+	goodService := NewGoodService(goodStorage, fakeInMemoryStorage{}, nil)
+
+	parent := goodStorage.addGood(1)
+	variant := goodStorage.addGood(1)
+	variant.ParentId = &parent.Id
+	removed := goodStorage.addGood(1)
+	removed.Removed = true
+
+	tests := []struct {
+		name    string
+		id      domain.GoodId
+		target  domain.ProjectId
+		wantErr error
+	}{
+		{"to its own project", parent.Id, 1, ErrInvalidTransfer},
+		{"variant", variant.Id, 2, ErrVariantTransfer},
+		{"removed good", removed.Id, 2, ErrGoodRemoved},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := goodService.Move(ctx, test.id, 1, test.target, &TransferOptions{}, "tester", nil); !errors.Is(err, test.wantErr) {
+				t.Errorf("move: got error %v, want %v", err, test.wantErr)
+			}
+			if _, err := goodService.Copy(ctx, test.id, 1, test.target, &TransferOptions{}, "tester"); !errors.Is(err, test.wantErr) {
+				t.Errorf("copy: got error %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+
+	for _, good := range goodStorage.goods {
+		if good.ProjectId != 1 {
+			t.Errorf("good %d was transferred to project %d", good.Id, good.ProjectId)
+		}
+	}
+}
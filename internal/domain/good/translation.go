@@ -0,0 +1,17 @@
+package good
+
+import (
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"time"
+)
+
+// Translation is the name and description of a good in a locale other than the default locale of its project.
+// An empty Description leaves the description of the good untranslated.
+type Translation struct {
+	GoodId      domain.GoodId
+	Locale      domain.Locale
+	Name        domain.GoodName
+	Description domain.GoodDescription
+	UpdatedAt   time.Time
+	UpdatedBy   domain.Actor
+}
@@ -0,0 +1,378 @@
+package good
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"github.com/vaberof/hezzl-backend/pkg/metrics"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	localeKey        = "locale_"
+	localesKey       = "locales_"
+	defaultLocaleKey = "default_locale_"
+)
+
+const (
+	translationCacheExpireTime   = 1 * time.Minute
+	defaultLocaleCacheExpireTime = 1 * time.Minute
+)
+
+const (
+	translationCacheName   = "translation"
+	defaultLocaleCacheName = "default_locale"
+)
+
+// noTranslation is cached for the locales a good is not translated to.
+const noTranslation = "null"
+
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{1,8})*$`)
+
+var (
+	ErrInvalidLocale       = errors.New("invalid locale")
+	ErrInvalidTranslation  = errors.New("invalid translation")
+	ErrTranslationNotFound = errors.New("translation not found")
+)
+
+// SetTranslation creates or replaces the translation of the good into the locale. The default locale of the project
+// cannot be translated to, since the name and description of the good are in that locale.
+func (g *goodServiceImpl) SetTranslation(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, locale domain.Locale, name domain.GoodName, description domain.GoodDescription, actor domain.Actor) (*Translation, error) {
+	ctx, span := tracer.Start(ctx, "GoodService.SetTranslation")
+	defer span.End()
+
+	locale, err := NormalizeLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+
+	name = domain.GoodName(strings.TrimSpace(name.String()))
+	if name == "" {
+		return nil, fmt.Errorf("%w: name must not be empty", ErrInvalidTranslation)
+	}
+
+	defaultLocale, err := g.getProjectDefaultLocale(ctx, projectId)
+	if err != nil {
+		if errors.Is(err, ErrProjectNotFound) {
+			return nil, ErrGoodNotFound
+		}
+		return nil, err
+	}
+	if locale == defaultLocale {
+		return nil, fmt.Errorf("%w: %q is the default locale of the project, update the good instead", ErrInvalidTranslation, locale)
+	}
+
+	translation, err := g.goodStorage.SetTranslation(ctx, id, projectId, &Translation{Locale: locale, Name: name, Description: description}, actor)
+	if err != nil {
+		return nil, g.mapWriteError(ctx, id, projectId, err)
+	}
+
+	if err = g.deleteCachedTranslation(ctx, id, projectId, locale); err != nil {
+		return nil, err
+	}
+
+	return translation, nil
+}
+
+// RemoveTranslation removes the translation of the good into the locale and returns it.
+func (g *goodServiceImpl) RemoveTranslation(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, locale domain.Locale, actor domain.Actor) (*Translation, error) {
+	ctx, span := tracer.Start(ctx, "GoodService.RemoveTranslation")
+	defer span.End()
+
+	locale, err := NormalizeLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+
+	translation, err := g.goodStorage.RemoveTranslation(ctx, id, projectId, locale, actor)
+	if err != nil {
+		if errors.Is(err, storage.ErrPostgresTranslationNotFound) {
+			return nil, ErrTranslationNotFound
+		}
+		return nil, g.mapWriteError(ctx, id, projectId, err)
+	}
+
+	if err = g.deleteCachedTranslation(ctx, id, projectId, locale); err != nil {
+		return nil, err
+	}
+
+	return translation, nil
+}
+
+func (g *goodServiceImpl) ListTranslations(ctx context.Context, id domain.GoodId, projectId domain.ProjectId) ([]*Translation, error) {
+	ctx, span := tracer.Start(ctx, "GoodService.ListTranslations")
+	defer span.End()
+
+	translations, err := g.goodStorage.ListTranslations(ctx, id, projectId)
+	if err != nil {
+		if errors.Is(err, storage.ErrPostgresGoodNotFound) {
+			return nil, ErrGoodNotFound
+		}
+		return nil, err
+	}
+
+	return translations, nil
+}
+
+func (g *goodServiceImpl) GetDefaultLocale(ctx context.Context, projectId domain.ProjectId) (domain.Locale, error) {
+	ctx, span := tracer.Start(ctx, "GoodService.GetDefaultLocale")
+	defer span.End()
+
+	return g.getProjectDefaultLocale(ctx, projectId)
+}
+
+// SetDefaultLocale changes the locale the names and descriptions of the goods of the project are written in.
+// Existing translations into the new default locale are kept, but no longer served.
+func (g *goodServiceImpl) SetDefaultLocale(ctx context.Context, projectId domain.ProjectId, locale domain.Locale) (domain.Locale, error) {
+	ctx, span := tracer.Start(ctx, "GoodService.SetDefaultLocale")
+	defer span.End()
+
+	locale, err := NormalizeLocale(locale)
+	if err != nil {
+		return "", err
+	}
+
+	locale, err = g.goodStorage.SetDefaultLocale(ctx, projectId, locale)
+	if err != nil {
+		if errors.Is(err, storage.ErrPostgresProjectNotFound) {
+			return "", ErrProjectNotFound
+		}
+		return "", err
+	}
+
+	err = g.inMemoryStorage.Delete(ctx, g.getDefaultLocaleCacheKey(projectId))
+	if err != nil && !errors.Is(err, storage.ErrRedisKeyNotFound) {
+		return "", err
+	}
+
+	return locale, nil
+}
+
+// localizeGood translates the good into the first of the locales it has a translation for.
+// The locales are looked up in order of preference; reaching the default locale of the project ends the lookup.
+func (g *goodServiceImpl) localizeGood(ctx context.Context, domainGood *Good, locales []domain.Locale) error {
+	defaultLocale, err := g.getProjectDefaultLocale(ctx, domainGood.ProjectId)
+	if err != nil {
+		return err
+	}
+
+	domainGood.Locale = defaultLocale
+
+	for _, locale := range lookupLocales(locales) {
+		if locale == defaultLocale {
+			return nil
+		}
+
+		translation, err := g.getGoodTranslation(ctx, domainGood.Id, domainGood.ProjectId, locale)
+		if err != nil {
+			return err
+		}
+		if translation != nil {
+			applyTranslation(domainGood, translation)
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// localizeGoods is localizeGood for many goods, reading their translations from storage at once.
+func (g *goodServiceImpl) localizeGoods(ctx context.Context, domainGoods []*Good, locales []domain.Locale) error {
+	defaultLocales := make(map[domain.ProjectId]domain.Locale)
+	ids := make([]domain.GoodId, len(domainGoods))
+
+	for i, domainGood := range domainGoods {
+		if _, ok := defaultLocales[domainGood.ProjectId]; !ok {
+			defaultLocale, err := g.getProjectDefaultLocale(ctx, domainGood.ProjectId)
+			if err != nil {
+				return err
+			}
+			defaultLocales[domainGood.ProjectId] = defaultLocale
+		}
+
+		domainGood.Locale = defaultLocales[domainGood.ProjectId]
+		ids[i] = domainGood.Id
+	}
+
+	locales = lookupLocales(locales)
+	if len(locales) == 0 || len(ids) == 0 {
+		return nil
+	}
+
+	translations, err := g.goodStorage.FindTranslations(ctx, ids, locales)
+	if err != nil {
+		return err
+	}
+
+	goodTranslations := make(map[domain.GoodId]map[domain.Locale]*Translation)
+	for _, translation := range translations {
+		if goodTranslations[translation.GoodId] == nil {
+			goodTranslations[translation.GoodId] = make(map[domain.Locale]*Translation)
+		}
+		goodTranslations[translation.GoodId][translation.Locale] = translation
+	}
+
+	for _, domainGood := range domainGoods {
+		for _, locale := range locales {
+			if locale == domainGood.Locale {
+				break
+			}
+			if translation, ok := goodTranslations[domainGood.Id][locale]; ok {
+				applyTranslation(domainGood, translation)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// getGoodTranslation returns the cached translation of the good into the locale or nil if there is none.
+func (g *goodServiceImpl) getGoodTranslation(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, locale domain.Locale) (*Translation, error) {
+	translationCacheKey := g.getTranslationCacheKey(id, projectId, locale)
+
+	cachedTranslation, err := g.inMemoryStorage.Get(ctx, translationCacheKey)
+	switch {
+	case err == nil:
+		metrics.CacheRequestsTotal.WithLabelValues(translationCacheName, metrics.CacheResultHit).Inc()
+
+		if cachedTranslation == noTranslation {
+			return nil, nil
+		}
+
+		var translation Translation
+		if err = json.Unmarshal([]byte(cachedTranslation), &translation); err != nil {
+			return nil, err
+		}
+
+		return &translation, nil
+	case errors.Is(err, storage.ErrRedisKeyNotFound):
+		metrics.CacheRequestsTotal.WithLabelValues(translationCacheName, metrics.CacheResultMiss).Inc()
+	default:
+		return nil, err
+	}
+
+	translation, err := g.goodStorage.GetTranslation(ctx, id, locale)
+	if err != nil && !errors.Is(err, storage.ErrPostgresTranslationNotFound) {
+		return nil, err
+	}
+
+	cachedTranslation = noTranslation
+	if translation != nil {
+		translationBytes, err := json.Marshal(translation)
+		if err != nil {
+			return nil, err
+		}
+		cachedTranslation = string(translationBytes)
+	}
+
+	err = g.inMemoryStorage.Set(ctx, translationCacheKey, cachedTranslation, translationCacheExpireTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return translation, nil
+}
+
+// getProjectDefaultLocale returns the cached default locale of the project.
+func (g *goodServiceImpl) getProjectDefaultLocale(ctx context.Context, projectId domain.ProjectId) (domain.Locale, error) {
+	defaultLocaleCacheKey := g.getDefaultLocaleCacheKey(projectId)
+
+	cachedLocale, err := g.inMemoryStorage.Get(ctx, defaultLocaleCacheKey)
+	switch {
+	case err == nil:
+		metrics.CacheRequestsTotal.WithLabelValues(defaultLocaleCacheName, metrics.CacheResultHit).Inc()
+
+		return domain.Locale(cachedLocale), nil
+	case errors.Is(err, storage.ErrRedisKeyNotFound):
+		metrics.CacheRequestsTotal.WithLabelValues(defaultLocaleCacheName, metrics.CacheResultMiss).Inc()
+	default:
+		return "", err
+	}
+
+	defaultLocale, err := g.goodStorage.GetDefaultLocale(ctx, projectId)
+	if err != nil {
+		if errors.Is(err, storage.ErrPostgresProjectNotFound) {
+			return "", ErrProjectNotFound
+		}
+		return "", err
+	}
+
+	err = g.inMemoryStorage.Set(ctx, defaultLocaleCacheKey, defaultLocale.String(), defaultLocaleCacheExpireTime)
+	if err != nil {
+		return "", err
+	}
+
+	return defaultLocale, nil
+}
+
+func (g *goodServiceImpl) deleteCachedTranslation(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, locale domain.Locale) error {
+	err := g.inMemoryStorage.Delete(ctx, g.getTranslationCacheKey(id, projectId, locale))
+	if err != nil && !errors.Is(err, storage.ErrRedisKeyNotFound) {
+		return err
+	}
+	return nil
+}
+
+// getTranslationCacheKey extends the cache key of the good, so that the translations of a good are cached per locale.
+func (g *goodServiceImpl) getTranslationCacheKey(id domain.GoodId, projectId domain.ProjectId, locale domain.Locale) string {
+	return g.getGoodCacheKey(id, projectId) + "_" + localeKey + locale.String()
+}
+
+func (g *goodServiceImpl) getDefaultLocaleCacheKey(projectId domain.ProjectId) string {
+	return defaultLocaleKey + strconv.FormatInt(projectId.Int64(), 10)
+}
+
+// NormalizeLocale lower-cases the locale and replaces underscores by hyphens, e.g. "pt_BR" becomes "pt-br".
+func NormalizeLocale(locale domain.Locale) (domain.Locale, error) {
+	normalizedLocale := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale.String()), "_", "-"))
+	if !localePattern.MatchString(normalizedLocale) {
+		return "", fmt.Errorf("%w: %q is not a language tag", ErrInvalidLocale, locale)
+	}
+	return domain.Locale(normalizedLocale), nil
+}
+
+// lookupLocales expands the locales in order of preference with their less specific forms,
+// e.g. "pt-br, en" becomes "pt-br, pt, en", dropping the invalid and repeated ones.
+func lookupLocales(locales []domain.Locale) []domain.Locale {
+	var lookup []domain.Locale
+
+	seen := make(map[domain.Locale]struct{})
+
+	for _, locale := range locales {
+		locale, err := NormalizeLocale(locale)
+		if err != nil {
+			continue
+		}
+
+		for {
+			if _, ok := seen[locale]; !ok {
+				seen[locale] = struct{}{}
+				lookup = append(lookup, locale)
+			}
+
+			i := strings.LastIndexByte(locale.String(), '-')
+			if i < 0 {
+				break
+			}
+			locale = locale[:i]
+		}
+	}
+
+	return lookup
+}
+
+func applyTranslation(domainGood *Good, translation *Translation) {
+	domainGood.Locale = translation.Locale
+	domainGood.TranslatedAt = translation.UpdatedAt
+	domainGood.Name = translation.Name
+	if translation.Description != "" {
+		domainGood.Description = translation.Description
+	}
+}
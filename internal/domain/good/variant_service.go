@@ -0,0 +1,78 @@
+package good
+
+import (
+	"context"
+	"errors"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+)
+
+var (
+	ErrNestedVariant = errors.New("variants cannot have variants")
+	ErrParentRemoved = errors.New("parent good is removed")
+)
+
+// CreateVariant creates a variant of the parent good in the project of the parent.
+// Only goods that are not variants themselves can have variants.
+func (g *goodServiceImpl) CreateVariant(ctx context.Context, parentId domain.GoodId, projectId domain.ProjectId, name domain.GoodName, attributes domain.GoodAttributes, actor domain.Actor) (*Good, error) {
+	ctx, span := tracer.Start(ctx, "GoodService.CreateVariant")
+	defer span.End()
+
+	if err := g.validateAttributes(ctx, projectId, attributes); err != nil {
+		return nil, err
+	}
+
+	domainGood, err := g.goodStorage.CreateVariant(ctx, parentId, projectId, name, attributes, actor)
+	if err != nil {
+		if errors.Is(err, storage.ErrPostgresNestedVariant) {
+			return nil, ErrNestedVariant
+		}
+		return nil, g.mapWriteError(ctx, parentId, projectId, err)
+	}
+
+	return domainGood, nil
+}
+
+// ExpandVariants loads the variants of the goods that are not variants themselves, translated like by Get.
+// Variants are read from storage on every call, so goods served from cache never carry stale variants.
+func (g *goodServiceImpl) ExpandVariants(ctx context.Context, domainGoods []*Good, locales []domain.Locale) error {
+	ctx, span := tracer.Start(ctx, "GoodService.ExpandVariants")
+	defer span.End()
+
+	parents := make(map[domain.GoodId]*Good, len(domainGoods))
+	parentIds := make([]domain.GoodId, 0, len(domainGoods))
+
+	for _, domainGood := range domainGoods {
+		if domainGood.ParentId != nil {
+			continue
+		}
+		domainGood.Variants = []*Good{}
+		parents[domainGood.Id] = domainGood
+		parentIds = append(parentIds, domainGood.Id)
+	}
+
+	if len(parentIds) == 0 {
+		return nil
+	}
+
+	variants, err := g.goodStorage.ListVariants(ctx, parentIds)
+	if err != nil {
+		return err
+	}
+
+	if err = g.localizeGoods(ctx, variants, locales); err != nil {
+		return err
+	}
+
+	for _, variant := range variants {
+		parent := parents[*variant.ParentId]
+		parent.Variants = append(parent.Variants, variant)
+	}
+
+	return nil
+}
+
+// getGoodCacheKeysWithVariants returns the cache keys of the good and of the variants written along with it.
+func (g *goodServiceImpl) getGoodCacheKeysWithVariants(domainGood *Good) []string {
+	return append([]string{g.getGoodCacheKey(domainGood.Id, domainGood.ProjectId)}, g.getGoodCacheKeys(domainGood.Variants)...)
+}
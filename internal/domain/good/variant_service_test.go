@@ -0,0 +1,172 @@
+package good
+
+import (
+	"context"
+	"errors"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"slices"
+	"testing"
+)
+
+// fakeVariantStorage removes and restores goods with their variants like the Postgres storage: removing a parent
+// removes its active variants, and restoring it restores only the variants that were removed along with it.
+type fakeVariantStorage struct {
+	GoodStorage
+
+	goods     map[domain.GoodId]*Good
+	removedAt map[domain.GoodId]int
+	removals  int
+}
+
+func newFakeVariantStorage(goods ...*Good) *fakeVariantStorage {
+	s := &fakeVariantStorage{goods: make(map[domain.GoodId]*Good), removedAt: make(map[domain.GoodId]int)}
+	for _, good := range goods {
+		good.ProjectId = testProjectId
+		good.Version = 1
+		s.goods[good.Id] = good
+	}
+	return s
+}
+
+func (s *fakeVariantStorage) Get(ctx context.Context, id domain.GoodId, projectId domain.ProjectId) (*Good, error) {
+	good, ok := s.goods[id]
+	if !ok || good.ProjectId != projectId {
+		return nil, storage.ErrPostgresGoodNotFound
+	}
+	copied := *good
+	return &copied, nil
+}
+
+func (s *fakeVariantStorage) Delete(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, actor domain.Actor, expectedVersion *domain.GoodVersion) (*Good, error) {
+	good := s.goods[id]
+	if expectedVersion != nil && *expectedVersion != good.Version {
+		return nil, storage.ErrPostgresGoodVersionMismatch
+	}
+
+	s.removals++
+	s.remove(good)
+
+	removed := *good
+	for _, variant := range s.variantsOf(id) {
+		if !variant.Removed {
+			s.remove(variant)
+			removed.Variants = append(removed.Variants, variant)
+		}
+	}
+	return &removed, nil
+}
+
+func (s *fakeVariantStorage) Restore(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, actor domain.Actor, expectedVersion *domain.GoodVersion) (*Good, error) {
+	good := s.goods[id]
+	if good.ParentId != nil && s.goods[*good.ParentId].Removed {
+		return nil, storage.ErrPostgresParentRemoved
+	}
+	if !good.Removed {
+		return nil, storage.ErrPostgresGoodNotRemoved
+	}
+
+	removedAt := s.removedAt[id]
+	s.restore(good)
+
+	restored := *good
+	for _, variant := range s.variantsOf(id) {
+		if variant.Removed && s.removedAt[variant.Id] == removedAt {
+			s.restore(variant)
+			restored.Variants = append(restored.Variants, variant)
+		}
+	}
+	return &restored, nil
+}
+
+func (s *fakeVariantStorage) remove(good *Good) {
+	good.Removed = true
+	good.Version++
+	s.removedAt[good.Id] = s.removals
+}
+
+func (s *fakeVariantStorage) restore(good *Good) {
+	good.Removed = false
+	good.Version++
+	delete(s.removedAt, good.Id)
+}
+
+func (s *fakeVariantStorage) variantsOf(parentId domain.GoodId) []*Good {
+	var variants []*Good
+	for _, good := range s.goods {
+		if good.ParentId != nil && *good.ParentId == parentId {
+			variants = append(variants, good)
+		}
+	}
+	slices.SortFunc(variants, func(a, b *Good) int { return int(a.Id - b.Id) })
+	return variants
+}
+
+// recordingInMemoryStorage records the deleted keys and caches nothing.
+type recordingInMemoryStorage struct {
+	fakeInMemoryStorage
+
+	deleted []string
+}
+
+func (s *recordingInMemoryStorage) Delete(ctx context.Context, keys ...string) error {
+	s.deleted = append(s.deleted, keys...)
+	return nil
+}
+
+func TestVariantsAreRemovedAndRestoredWithTheirParent(t *testing.T) {
+	ctx := context.Background()
+
+	parentId := domain.GoodId(1)
+	parent := &Good{Id: parentId}
+	removedEarlier := &Good{Id: 2, ParentId: &parentId}
+	active := &Good{Id: 3, ParentId: &parentId}
+
+	goodStorage := newFakeVariantStorage(parent, removedEarlier, active)
+	inMemoryStorage := &recordingInMemoryStorage{}
+	goodService := NewGoodService(goodStorage, inMemoryStorage, nil)
+
+	wantClearedKeys := []string{
+		(&goodServiceImpl{}).getGoodCacheKey(parentId, testProjectId),
+		(&goodServiceImpl{}).getGoodCacheKey(active.Id, testProjectId),
+	}
+
+	if _, err := goodService.Delete(ctx, removedEarlier.Id, testProjectId, "tester", nil); err != nil {
+		t.Fatalf("remove variant: %v", err)
+	}
+
+	inMemoryStorage.deleted = nil
+
+	if _, err := goodService.Delete(ctx, parentId, testProjectId, "tester", nil); err != nil {
+		t.Fatalf("remove parent: %v", err)
+	}
+	if !active.Removed {
+		t.Fatal("active variant is kept after its parent was removed")
+	}
+	if !slices.Equal(inMemoryStorage.deleted, wantClearedKeys) {
+		t.Errorf("got cleared cache keys %v, want %v", inMemoryStorage.deleted, wantClearedKeys)
+	}
+
+	if _, err := goodService.Restore(ctx, active.Id, testProjectId, "tester", nil); !errors.Is(err, ErrParentRemoved) {
+		t.Fatalf("restore variant of removed parent: got error %v, want %v", err, ErrParentRemoved)
+	}
+
+	inMemoryStorage.deleted = nil
+
+	restored, err := goodService.Restore(ctx, parentId, testProjectId, "tester", nil)
+	if err != nil {
+		t.Fatalf("restore parent: %v", err)
+	}
+	if active.Removed {
+		t.Error("variant removed with its parent is not restored with it")
+	}
+	if !removedEarlier.Removed {
+		t.Error("variant removed before its parent is restored with it")
+	}
+	if len(restored.Variants) != 1 || restored.Variants[0].Id != active.Id {
+		t.Errorf("got restored variants %v, want only %d", restored.Variants, active.Id)
+	}
+	if !slices.Equal(inMemoryStorage.deleted, wantClearedKeys) {
+		t.Errorf("got cleared cache keys %v, want %v", inMemoryStorage.deleted, wantClearedKeys)
+	}
+}
@@ -0,0 +1,97 @@
+package natsconn
+
+import (
+	"context"
+	"fmt"
+	"github.com/nats-io/nats.go"
+	"github.com/vaberof/hezzl-backend/pkg/retry"
+	"log/slog"
+	"time"
+)
+
+type Options struct {
+	Name          string
+	Host          string
+	Port          int
+	MaxReconnects int
+	ReconnectWait time.Duration
+	Retry         *retry.Config
+	// OnReconnect is called after the connection to the server has been restored.
+	OnReconnect func(nc *nats.Conn)
+}
+
+// Connect dials NATS retrying the initial connection with backoff,
+// and logs disconnects, reconnects and the final close of the connection.
+func Connect(ctx context.Context, options *Options) (*nats.Conn, error) {
+	natsOptions := []nats.Option{
+		nats.Name(options.Name),
+		nats.DisconnectErrHandler(func(nc *nats.Conn, err error) {
+			slog.Warn("nats connection lost", "connection", options.Name, "error", err)
+		}),
+		nats.ReconnectHandler(func(nc *nats.Conn) {
+			slog.Info("nats connection restored", "connection", options.Name, "url", nc.ConnectedUrlRedacted())
+			if options.OnReconnect != nil {
+				options.OnReconnect(nc)
+			}
+		}),
+		nats.ClosedHandler(func(nc *nats.Conn) {
+			slog.Info("nats connection closed", "connection", options.Name)
+		}),
+		nats.ErrorHandler(func(nc *nats.Conn, sub *nats.Subscription, err error) {
+			subject := ""
+			if sub != nil {
+				subject = sub.Subject
+			}
+			slog.Error("nats async error", "connection", options.Name, "subject", subject, "error", err)
+		}),
+	}
+
+	// Zero values keep the nats.go defaults.
+	if options.MaxReconnects != 0 {
+		natsOptions = append(natsOptions, nats.MaxReconnects(options.MaxReconnects))
+	}
+	if options.ReconnectWait > 0 {
+		natsOptions = append(natsOptions, nats.ReconnectWait(options.ReconnectWait))
+	}
+
+	var nc *nats.Conn
+
+	err := retry.Do(ctx, options.Retry, "connect to nats "+options.Name, func(ctx context.Context) error {
+		var err error
+		nc, err = nats.Connect(fmt.Sprintf("%s:%d", options.Host, options.Port), natsOptions...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nc, nil
+}
+
+const drainPollInterval = 50 * time.Millisecond
+
+// Drain drains the connection and waits until it is closed or ctx is done.
+// Pending outgoing messages are flushed and subscriptions finish processing buffered messages.
+func Drain(ctx context.Context, nc *nats.Conn) error {
+	if nc.IsClosed() {
+		return nil
+	}
+
+	if err := nc.Drain(); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+
+	for !nc.IsClosed() {
+		select {
+		case <-ctx.Done():
+			nc.Close()
+			return fmt.Errorf("nats drain interrupted: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+
+	return nil
+}
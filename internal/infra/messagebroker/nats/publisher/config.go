@@ -1,6 +1,14 @@
 package publisher
 
+import (
+	"github.com/vaberof/hezzl-backend/pkg/retry"
+	"time"
+)
+
 type Config struct {
-	Host string `yaml:"host"`
-	Port int    `yaml:"port"`
+	Host          string        `yaml:"host"`
+	Port          int           `yaml:"port"`
+	MaxReconnects int           `yaml:"max_reconnects"`
+	ReconnectWait time.Duration `yaml:"reconnect_wait"`
+	Retry         retry.Config  `yaml:"retry"`
 }
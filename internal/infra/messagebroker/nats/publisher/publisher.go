@@ -1,54 +1,170 @@
 package publisher
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/nats-io/nats.go"
+	"github.com/vaberof/hezzl-backend/internal/infra/messagebroker/nats/natsconn"
+	"github.com/vaberof/hezzl-backend/pkg/metrics"
+	"github.com/vaberof/hezzl-backend/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 	"time"
 )
 
-const goodLogsSubject = "good.logs"
+const (
+	goodLogsSubject       = "good.logs"
+	stockMovementsSubject = "good.stock.movements"
+)
+
+const defaultFlushTimeout = 5 * time.Second
+
+const (
+	GoodEventCreated       = "created"
+	GoodEventUpdated       = "updated"
+	GoodEventReprioritized = "reprioritized"
+	GoodEventRemoved       = "removed"
+	GoodEventRestored      = "restored"
+	GoodEventPurged        = "purged"
+	GoodEventTagged        = "tagged"
+	GoodEventUntagged      = "untagged"
+	GoodEventRepriced      = "repriced"
+	GoodEventTranslated    = "translated"
+	GoodEventUntranslated  = "untranslated"
+	GoodEventMovedOut      = "moved_out"
+	GoodEventMovedIn       = "moved_in"
+	GoodEventCopiedOut     = "copied_out"
+	GoodEventCopiedIn      = "copied_in"
+)
+
+var tracer = tracing.Tracer("github.com/vaberof/hezzl-backend/internal/infra/messagebroker/nats/publisher")
 
 type Publisher interface {
-	PublishGoodLog(id, projectId int64, name, description string, priority int, removed bool, eventTime time.Time) error
+	Ping(ctx context.Context) error
+	Drain(ctx context.Context) error
+	PublishGoodLog(ctx context.Context, goodLog *GoodLog) error
+	PublishGoodLogs(ctx context.Context, goodLogs []*GoodLog) error
+	PublishStockMovement(ctx context.Context, stockMovement *StockMovement) error
 }
 
 type publisherImpl struct {
 	natsConn *nats.Conn
 }
 
-func New(config *Config) (Publisher, error) {
-	nc, err := nats.Connect(fmt.Sprintf("%s:%d", config.Host, config.Port))
+func New(ctx context.Context, config *Config) (Publisher, error) {
+	nc, err := natsconn.Connect(ctx, &natsconn.Options{
+		Name:          "publisher",
+		Host:          config.Host,
+		Port:          config.Port,
+		MaxReconnects: config.MaxReconnects,
+		ReconnectWait: config.ReconnectWait,
+		Retry:         &config.Retry,
+	})
 	if err != nil {
 		return nil, err
 	}
 	return &publisherImpl{natsConn: nc}, nil
 }
 
-func (p *publisherImpl) PublishGoodLog(id, projectId int64, name, description string, priority int, removed bool, eventTime time.Time) error {
-	data, err := json.Marshal(&GoodLog{
-		Id:          id,
-		ProjectId:   projectId,
-		Name:        name,
-		Description: description,
-		Priority:    priority,
-		Removed:     removed,
-		EventTime:   eventTime,
-	})
+func (p *publisherImpl) PublishGoodLog(ctx context.Context, goodLog *GoodLog) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(goodLog)
 	if err != nil {
 		return err
 	}
-	err = p.natsConn.Publish(goodLogsSubject, data)
+	return p.Publish(ctx, goodLogsSubject, data)
+}
+
+func (p *publisherImpl) PublishStockMovement(ctx context.Context, stockMovement *StockMovement) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(stockMovement)
 	if err != nil {
 		return err
 	}
+	return p.Publish(ctx, stockMovementsSubject, data)
+}
+
+// PublishGoodLogs publishes the good logs one message each without waiting for the server in between
+// and then flushes the connection once, so that a batch costs a single round trip.
+func (p *publisherImpl) PublishGoodLogs(ctx context.Context, goodLogs []*GoodLog) error {
+	ctx, span := tracer.Start(ctx, goodLogsSubject+" publish batch",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "nats"),
+			attribute.String("messaging.destination.name", goodLogsSubject),
+			attribute.Int("messaging.batch.message_count", len(goodLogs)),
+		),
+	)
+	defer span.End()
+
+	for _, goodLog := range goodLogs {
+		data, err := json.Marshal(goodLog)
+		if err != nil {
+			tracing.RecordError(span, err)
+			return err
+		}
+		if err = p.Publish(ctx, goodLogsSubject, data); err != nil {
+			return err
+		}
+	}
+
+	// Flushing requires a deadline.
+	flushCtx := ctx
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		flushCtx, cancel = context.WithTimeout(ctx, defaultFlushTimeout)
+		defer cancel()
+	}
+
+	err := p.natsConn.FlushWithContext(flushCtx)
+	if err != nil {
+		metrics.NatsPublishFailuresTotal.WithLabelValues(goodLogsSubject).Inc()
+		tracing.RecordError(span, err)
+		return err
+	}
 	return nil
 }
 
-func (p *publisherImpl) Publish(subject string, data []byte) error {
-	err := p.natsConn.Publish(subject, data)
+func (p *publisherImpl) Publish(ctx context.Context, subject string, data []byte) error {
+	ctx, span := tracer.Start(ctx, subject+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(attribute.String("messaging.system", "nats"), attribute.String("messaging.destination.name", subject)),
+	)
+	defer span.End()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	msg := nats.NewMsg(subject)
+	msg.Data = data
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(msg.Header))
+
+	err := p.natsConn.PublishMsg(msg)
 	if err != nil {
+		metrics.NatsPublishFailuresTotal.WithLabelValues(subject).Inc()
+		tracing.RecordError(span, err)
 		return err
 	}
 	return nil
 }
+
+func (p *publisherImpl) Ping(ctx context.Context) error {
+	if status := p.natsConn.Status(); status != nats.CONNECTED {
+		return fmt.Errorf("nats connection is %s", status)
+	}
+	return p.natsConn.FlushWithContext(ctx)
+}
+
+func (p *publisherImpl) Drain(ctx context.Context) error {
+	return natsconn.Drain(ctx, p.natsConn)
+}
@@ -0,0 +1,16 @@
+package publisher
+
+import "time"
+
+type StockMovement struct {
+	Id        int64     `json:"id"`
+	GoodId    int64     `json:"goodId"`
+	ProjectId int64     `json:"projectId"`
+	Kind      string    `json:"kind"`
+	Quantity  int64     `json:"quantity"`
+	Stock     int64     `json:"stock"`
+	Reserved  int64     `json:"reserved"`
+	Reason    string    `json:"reason"`
+	Actor     string    `json:"actor"`
+	EventTime time.Time `json:"eventTime"`
+}
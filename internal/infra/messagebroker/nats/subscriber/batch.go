@@ -0,0 +1,85 @@
+package subscriber
+
+import (
+	"context"
+	"github.com/vaberof/hezzl-backend/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// batch collects the messages of a subject and inserts them once defaultBatchSize of them arrived.
+type batch[T any] struct {
+	subject       string
+	mu            sync.Mutex
+	items         []T
+	spanLinks     []trace.Link
+	lastMsgCtx    context.Context
+	insertFn      func(ctx context.Context, items []T) error
+	insertTimeout time.Duration
+}
+
+func newBatch[T any](subject string, insertTimeout time.Duration, insertFn func(ctx context.Context, items []T) error) *batch[T] {
+	return &batch[T]{
+		subject:       subject,
+		items:         make([]T, 0, defaultBatchSize),
+		spanLinks:     make([]trace.Link, 0, defaultBatchSize),
+		insertFn:      insertFn,
+		insertTimeout: insertTimeout,
+	}
+}
+
+func (b *batch[T]) add(msgCtx context.Context, item T) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.items = append(b.items, item)
+	b.spanLinks = append(b.spanLinks, trace.LinkFromContext(msgCtx))
+	b.lastMsgCtx = msgCtx
+
+	if len(b.items) >= defaultBatchSize {
+		if err := b.insert(msgCtx); err != nil {
+			slog.ErrorContext(msgCtx, "failed to insert batch, will retry with the next message", "subject", b.subject, "size", len(b.items), "error", err)
+		}
+	}
+}
+
+func (b *batch[T]) flush(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.items) == 0 {
+		return nil
+	}
+
+	// Keep the trace of the last message but obey the deadline of the caller.
+	flushCtx := trace.ContextWithSpanContext(ctx, trace.SpanContextFromContext(b.lastMsgCtx))
+
+	return b.insert(flushCtx)
+}
+
+// insert must be called with mu held. The batch insert continues the trace of the message
+// that completed the batch and links the traces of all the other messages it contains.
+func (b *batch[T]) insert(ctx context.Context) error {
+	insertCtx, insertSpan := tracer.Start(ctx, b.subject+" process",
+		trace.WithAttributes(messagingSystemAttribute, attribute.Int("messaging.batch.message_count", len(b.items))),
+		trace.WithLinks(b.spanLinks...),
+	)
+	defer insertSpan.End()
+
+	insertCtx, cancel := context.WithTimeout(insertCtx, b.insertTimeout)
+	defer cancel()
+
+	err := b.insertFn(insertCtx, b.items)
+	if err != nil {
+		tracing.RecordError(insertSpan, err)
+		return err
+	}
+
+	b.items = make([]T, 0, defaultBatchSize)
+	b.spanLinks = make([]trace.Link, 0, defaultBatchSize)
+
+	return nil
+}
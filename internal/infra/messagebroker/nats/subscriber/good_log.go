@@ -1,15 +1,29 @@
 package subscriber
 
 import (
+	"encoding/json"
 	"time"
 )
 
 type GoodLog struct {
+	Event       string    `json:"event"`
 	Id          int64     `json:"id"`
 	ProjectId   int64     `json:"projectId"`
 	Name        string    `json:"name"`
 	Description string    `json:"description"`
 	Priority    int       `json:"priority"`
 	Removed     bool      `json:"removed"`
+	Actor       string    `json:"actor"`
 	EventTime   time.Time `json:"eventTime"`
+	// Tags are the tags added or removed by a tag event.
+	Tags []string `json:"tags,omitempty"`
+	// Attributes are the attributes of the good, a JSON object.
+	Attributes json.RawMessage `json:"attributes,omitempty"`
+	// PriceAmount and PriceCurrency are the price of the good, if it has one.
+	PriceAmount   *int64 `json:"priceAmount,omitempty"`
+	PriceCurrency string `json:"priceCurrency,omitempty"`
+	// ParentId is the good the good is a variant of.
+	ParentId *int64 `json:"parentId,omitempty"`
+	// Locale is the locale of a translation event, whose name and description are the translated ones.
+	Locale string `json:"locale,omitempty"`
 }
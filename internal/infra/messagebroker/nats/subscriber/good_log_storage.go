@@ -1,7 +1,10 @@
 package subscriber
 
-import "github.com/vaberof/hezzl-backend/internal/infra/storage/clickhouse/chgoodlog"
+import (
+	"context"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage/clickhouse/chgoodlog"
+)
 
 type GoodLogStorage interface {
-	Insert(goodLogs []*chgoodlog.GoodLog) error
+	Insert(ctx context.Context, goodLogs []*chgoodlog.GoodLog) error
 }
@@ -0,0 +1,10 @@
+package subscriber
+
+import (
+	"context"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage/clickhouse/chstockmovement"
+)
+
+type StockMovementStorage interface {
+	Insert(ctx context.Context, stockMovements []*chstockmovement.StockMovement) error
+}
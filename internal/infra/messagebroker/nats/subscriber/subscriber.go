@@ -1,55 +1,208 @@
 package subscriber
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/nats-io/nats.go"
+	"github.com/vaberof/hezzl-backend/internal/infra/messagebroker/nats/natsconn"
 	"github.com/vaberof/hezzl-backend/internal/infra/storage/clickhouse/chgoodlog"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage/clickhouse/chstockmovement"
+	"github.com/vaberof/hezzl-backend/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"log/slog"
+	"sync"
+	"time"
 )
 
-const goodLogsSubject = "good.logs"
+const (
+	goodLogsSubject       = "good.logs"
+	stockMovementsSubject = "good.stock.movements"
+)
+
+var (
+	tracer                   = tracing.Tracer("github.com/vaberof/hezzl-backend/internal/infra/messagebroker/nats/subscriber")
+	messagingSystemAttribute = attribute.String("messaging.system", "nats")
+)
 
-const defaultBatchSize = 10
+const (
+	defaultBatchSize     = 10
+	defaultInsertTimeout = 5 * time.Second
+)
 
 type Subscriber interface {
-	SubscribeOnGoodLogsSubject(goodLogStorage GoodLogStorage)
+	Ping(ctx context.Context) error
+	Drain(ctx context.Context) error
+	SubscribeOnGoodLogsSubject(ctx context.Context, goodLogStorage GoodLogStorage) error
+	SubscribeOnStockMovementsSubject(ctx context.Context, stockMovementStorage StockMovementStorage) error
 }
 
 type subscriberImpl struct {
-	natsConn *nats.Conn
+	natsConn            *nats.Conn
+	insertTimeout       time.Duration
+	goodLogsBatch       *batch[*GoodLog]
+	stockMovementsBatch *batch[*StockMovement]
+
+	mu            sync.Mutex
+	subscriptions map[string]*subscription
+}
+
+type subscription struct {
+	natsSubscription *nats.Subscription
+	handler          nats.MsgHandler
 }
 
-func New(config *Config) (Subscriber, error) {
-	nc, err := nats.Connect(fmt.Sprintf("%s:%d", config.Host, config.Port))
+func New(ctx context.Context, config *Config) (Subscriber, error) {
+	insertTimeout := config.InsertTimeout
+	if insertTimeout <= 0 {
+		insertTimeout = defaultInsertTimeout
+	}
+
+	s := &subscriberImpl{
+		insertTimeout: insertTimeout,
+		subscriptions: make(map[string]*subscription),
+	}
+
+	nc, err := natsconn.Connect(ctx, &natsconn.Options{
+		Name:          "subscriber",
+		Host:          config.Host,
+		Port:          config.Port,
+		MaxReconnects: config.MaxReconnects,
+		ReconnectWait: config.ReconnectWait,
+		Retry:         &config.Retry,
+		OnReconnect:   s.resubscribe,
+	})
 	if err != nil {
 		return nil, err
 	}
-	return &subscriberImpl{natsConn: nc}, nil
+	s.natsConn = nc
+
+	return s, nil
 }
 
-func (s *subscriberImpl) SubscribeOnGoodLogsSubject(goodLogStorage GoodLogStorage) {
-	goodLogs := make([]*GoodLog, 0, defaultBatchSize)
+func (s *subscriberImpl) SubscribeOnGoodLogsSubject(ctx context.Context, goodLogStorage GoodLogStorage) error {
+	s.goodLogsBatch = newBatch(goodLogsSubject, s.insertTimeout, func(ctx context.Context, goodLogs []*GoodLog) error {
+		return goodLogStorage.Insert(ctx, buildCHGoodLogs(goodLogs))
+	})
+
+	return s.subscribe(goodLogsSubject, func(msg *nats.Msg) {
+		msgCtx := otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(msg.Header))
+
+		msgCtx, span := tracer.Start(msgCtx, goodLogsSubject+" receive",
+			trace.WithSpanKind(trace.SpanKindConsumer),
+			trace.WithAttributes(messagingSystemAttribute, attribute.String("messaging.destination.name", goodLogsSubject)),
+		)
+		defer span.End()
 
-	s.natsConn.Subscribe(goodLogsSubject, func(msg *nats.Msg) {
 		var goodLog GoodLog
 
 		err := json.Unmarshal(msg.Data, &goodLog)
 		if err != nil {
+			tracing.RecordError(span, err)
+			slog.ErrorContext(msgCtx, "failed to decode good log", "subject", msg.Subject, "error", err)
 			return
 		}
 
-		goodLogs = append(goodLogs, &goodLog)
+		s.goodLogsBatch.add(msgCtx, &goodLog)
+	})
+}
+
+func (s *subscriberImpl) SubscribeOnStockMovementsSubject(ctx context.Context, stockMovementStorage StockMovementStorage) error {
+	s.stockMovementsBatch = newBatch(stockMovementsSubject, s.insertTimeout, func(ctx context.Context, stockMovements []*StockMovement) error {
+		return stockMovementStorage.Insert(ctx, buildCHStockMovements(stockMovements))
+	})
+
+	return s.subscribe(stockMovementsSubject, func(msg *nats.Msg) {
+		msgCtx := otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(msg.Header))
+
+		msgCtx, span := tracer.Start(msgCtx, stockMovementsSubject+" receive",
+			trace.WithSpanKind(trace.SpanKindConsumer),
+			trace.WithAttributes(messagingSystemAttribute, attribute.String("messaging.destination.name", stockMovementsSubject)),
+		)
+		defer span.End()
 
-		if len(goodLogs) >= defaultBatchSize {
-			err = goodLogStorage.Insert(buildCHGoodLogs(goodLogs))
-			if err != nil {
-				return
-			}
-			goodLogs = make([]*GoodLog, 0, defaultBatchSize)
+		var stockMovement StockMovement
+
+		err := json.Unmarshal(msg.Data, &stockMovement)
+		if err != nil {
+			tracing.RecordError(span, err)
+			slog.ErrorContext(msgCtx, "failed to decode stock movement", "subject", msg.Subject, "error", err)
+			return
 		}
+
+		s.stockMovementsBatch.add(msgCtx, &stockMovement)
 	})
 }
 
+// Drain stops receiving new messages, lets the subscriptions process the buffered ones
+// and inserts the last incomplete batches.
+func (s *subscriberImpl) Drain(ctx context.Context) error {
+	if err := natsconn.Drain(ctx, s.natsConn); err != nil {
+		return err
+	}
+
+	var errs []error
+
+	if s.goodLogsBatch != nil {
+		errs = append(errs, s.goodLogsBatch.flush(ctx))
+	}
+	if s.stockMovementsBatch != nil {
+		errs = append(errs, s.stockMovementsBatch.flush(ctx))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *subscriberImpl) subscribe(subject string, handler nats.MsgHandler) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	natsSubscription, err := s.natsConn.Subscribe(subject, handler)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe on %s: %w", subject, err)
+	}
+
+	s.subscriptions[subject] = &subscription{
+		natsSubscription: natsSubscription,
+		handler:          handler,
+	}
+
+	return nil
+}
+
+// resubscribe restores subscriptions the server dropped while the connection was down.
+// Subscriptions that survived the reconnect are replayed by nats.go itself and left untouched.
+func (s *subscriberImpl) resubscribe(nc *nats.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for subject, sub := range s.subscriptions {
+		if sub.natsSubscription.IsValid() {
+			continue
+		}
+
+		natsSubscription, err := nc.Subscribe(subject, sub.handler)
+		if err != nil {
+			slog.Error("failed to resubscribe after reconnect", "subject", subject, "error", err)
+			continue
+		}
+		sub.natsSubscription = natsSubscription
+
+		slog.Info("resubscribed after reconnect", "subject", subject)
+	}
+}
+
+func (s *subscriberImpl) Ping(ctx context.Context) error {
+	if status := s.natsConn.Status(); status != nats.CONNECTED {
+		return fmt.Errorf("nats connection is %s", status)
+	}
+	return s.natsConn.FlushWithContext(ctx)
+}
+
 func buildCHGoodLogs(goodLogs []*GoodLog) []*chgoodlog.GoodLog {
 	chGoodLogs := make([]*chgoodlog.GoodLog, len(goodLogs))
 	for i := range goodLogs {
@@ -60,12 +213,39 @@ func buildCHGoodLogs(goodLogs []*GoodLog) []*chgoodlog.GoodLog {
 
 func buildCHGoodLog(goodLog *GoodLog) *chgoodlog.GoodLog {
 	return &chgoodlog.GoodLog{
-		Id:          goodLog.Id,
-		ProjectId:   goodLog.ProjectId,
-		Name:        goodLog.Name,
-		Description: goodLog.Description,
-		Priority:    goodLog.Priority,
-		Removed:     goodLog.Removed,
-		EventTime:   goodLog.EventTime,
+		Id:            goodLog.Id,
+		ProjectId:     goodLog.ProjectId,
+		Name:          goodLog.Name,
+		Description:   goodLog.Description,
+		Priority:      goodLog.Priority,
+		Removed:       goodLog.Removed,
+		EventTime:     goodLog.EventTime,
+		Actor:         goodLog.Actor,
+		Event:         goodLog.Event,
+		Tags:          goodLog.Tags,
+		Attributes:    string(goodLog.Attributes),
+		PriceAmount:   goodLog.PriceAmount,
+		PriceCurrency: goodLog.PriceCurrency,
+		ParentId:      goodLog.ParentId,
+		Locale:        goodLog.Locale,
+	}
+}
+
+func buildCHStockMovements(stockMovements []*StockMovement) []*chstockmovement.StockMovement {
+	chStockMovements := make([]*chstockmovement.StockMovement, len(stockMovements))
+	for i, stockMovement := range stockMovements {
+		chStockMovements[i] = &chstockmovement.StockMovement{
+			Id:        stockMovement.Id,
+			GoodId:    stockMovement.GoodId,
+			ProjectId: stockMovement.ProjectId,
+			Kind:      stockMovement.Kind,
+			Quantity:  stockMovement.Quantity,
+			Stock:     stockMovement.Stock,
+			Reserved:  stockMovement.Reserved,
+			Reason:    stockMovement.Reason,
+			Actor:     stockMovement.Actor,
+			EventTime: stockMovement.EventTime,
+		}
 	}
+	return chStockMovements
 }
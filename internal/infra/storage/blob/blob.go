@@ -0,0 +1,51 @@
+package blob
+
+import (
+	"context"
+	"fmt"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage/blob/fsblob"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage/blob/s3blob"
+	"io"
+	"log/slog"
+)
+
+const (
+	DriverFS = "fs"
+	DriverS3 = "s3"
+)
+
+type Config struct {
+	// Driver selects where blobs are stored, either "fs" or "s3".
+	Driver string        `yaml:"driver"`
+	FS     fsblob.Config `yaml:"fs"`
+	S3     s3blob.Config `yaml:"s3"`
+}
+
+// LogValue logs only the config of the selected driver.
+func (config Config) LogValue() slog.Value {
+	switch config.Driver {
+	case DriverS3:
+		return slog.GroupValue(slog.String("driver", config.Driver), slog.Any("s3", config.S3))
+	default:
+		return slog.GroupValue(slog.String("driver", config.Driver), slog.String("rootDir", config.FS.RootDir))
+	}
+}
+
+type Store interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	Ping(ctx context.Context) error
+}
+
+// New creates the blob store of the configured driver; the file system is used if no driver is set.
+func New(config *Config) (Store, error) {
+	switch config.Driver {
+	case DriverFS, "":
+		return fsblob.New(&config.FS)
+	case DriverS3:
+		return s3blob.New(&config.S3)
+	default:
+		return nil, fmt.Errorf("unknown blob store driver %q", config.Driver)
+	}
+}
@@ -0,0 +1,158 @@
+package fsblob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage"
+	"github.com/vaberof/hezzl-backend/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+var (
+	tracer              = tracing.Tracer("github.com/vaberof/hezzl-backend/internal/infra/storage/blob/fsblob")
+	blobSystemAttribute = attribute.String("blob.system", "fs")
+)
+
+var errInvalidKey = errors.New("invalid blob key")
+
+type Config struct {
+	// RootDir is the directory the blobs are stored in; it is created if it does not exist.
+	RootDir string `yaml:"root_dir"`
+}
+
+// FSBlobStore stores every blob in a file named by its key below the root directory.
+type FSBlobStore struct {
+	rootDir string
+}
+
+func New(config *Config) (*FSBlobStore, error) {
+	if config.RootDir == "" {
+		return nil, errors.New("fsblob: root_dir must be set")
+	}
+
+	if err := os.MkdirAll(config.RootDir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create blob root directory: %w", err)
+	}
+
+	return &FSBlobStore{rootDir: config.RootDir}, nil
+}
+
+// Put writes the blob to a temporary file first and renames it into place,
+// so that readers never see a partially written blob.
+func (bs *FSBlobStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	ctx, span := tracer.Start(ctx, "FSBlobStore.Put", trace.WithAttributes(blobSystemAttribute, attribute.Int64("blob.size", size)))
+	defer span.End()
+
+	path, err := bs.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		tracing.RecordError(span, err)
+		return fmt.Errorf("failed to create blob directory: %w", err)
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		tracing.RecordError(span, err)
+		return fmt.Errorf("failed to create blob file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	_, err = io.Copy(tmpFile, &contextReader{ctx: ctx, r: r})
+	if err == nil {
+		err = tmpFile.Sync()
+	}
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		tracing.RecordError(span, err)
+		return fmt.Errorf("failed to write blob: %w", err)
+	}
+
+	if err = os.Rename(tmpFile.Name(), path); err != nil {
+		tracing.RecordError(span, err)
+		return fmt.Errorf("failed to write blob: %w", err)
+	}
+
+	return nil
+}
+
+func (bs *FSBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	_, span := tracer.Start(ctx, "FSBlobStore.Get", trace.WithAttributes(blobSystemAttribute))
+	defer span.End()
+
+	path, err := bs.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, storage.ErrBlobNotFound
+		}
+		tracing.RecordError(span, err)
+		return nil, fmt.Errorf("failed to open blob: %w", err)
+	}
+
+	return file, nil
+}
+
+func (bs *FSBlobStore) Delete(ctx context.Context, key string) error {
+	_, span := tracer.Start(ctx, "FSBlobStore.Delete", trace.WithAttributes(blobSystemAttribute))
+	defer span.End()
+
+	path, err := bs.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err = os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		tracing.RecordError(span, err)
+		return fmt.Errorf("failed to delete blob: %w", err)
+	}
+
+	return nil
+}
+
+// Ping checks that the root directory is still accessible.
+func (bs *FSBlobStore) Ping(ctx context.Context) error {
+	info, err := os.Stat(bs.rootDir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("blob root %s is not a directory", bs.rootDir)
+	}
+	return nil
+}
+
+// path maps the key to a file below the root directory, rejecting keys that would escape it.
+func (bs *FSBlobStore) path(key string) (string, error) {
+	if !filepath.IsLocal(key) {
+		return "", fmt.Errorf("%w: %q", errInvalidKey, key)
+	}
+	return filepath.Join(bs.rootDir, filepath.FromSlash(key)), nil
+}
+
+// contextReader stops a copy once ctx is done, as reading a file or a request body does not observe ctx.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (cr *contextReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cr.r.Read(p)
+}
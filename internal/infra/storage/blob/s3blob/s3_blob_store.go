@@ -0,0 +1,243 @@
+package s3blob
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage"
+	"github.com/vaberof/hezzl-backend/pkg/logging"
+	"github.com/vaberof/hezzl-backend/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var (
+	tracer              = tracing.Tracer("github.com/vaberof/hezzl-backend/internal/infra/storage/blob/s3blob")
+	blobSystemAttribute = attribute.String("blob.system", "s3")
+)
+
+const (
+	defaultRegion  = "us-east-1"
+	defaultTimeout = 5 * time.Minute
+
+	// maxErrorBodyBytes limits how much of an error response is read to describe the error.
+	maxErrorBodyBytes = 4096
+)
+
+type Config struct {
+	// Endpoint is the base URL of the S3 compatible service, e.g. https://s3.eu-central-1.amazonaws.com
+	// or http://localhost:9000 for a local MinIO.
+	Endpoint string `yaml:"endpoint"`
+	Region   string `yaml:"region"`
+	Bucket   string `yaml:"bucket"`
+	// PathStyle addresses the bucket in the path instead of the host name, as most S3 stand-ins require.
+	PathStyle       bool          `yaml:"path_style"`
+	AccessKeyId     string        `yaml:"access_key_id"`
+	SecretAccessKey string        `yaml:"secret_access_key"`
+	Timeout         time.Duration `yaml:"timeout"`
+}
+
+// LogValue hides the secret access key when the config is logged.
+func (config Config) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("endpoint", config.Endpoint),
+		slog.String("region", config.Region),
+		slog.String("bucket", config.Bucket),
+		slog.Bool("pathStyle", config.PathStyle),
+		slog.String("accessKeyId", config.AccessKeyId),
+		slog.String("secretAccessKey", logging.RedactedString(config.SecretAccessKey)),
+		slog.Duration("timeout", config.Timeout),
+	)
+}
+
+// S3BlobStore stores blobs as objects of a bucket of an S3 compatible service.
+// Requests are signed with AWS Signature Version 4.
+type S3BlobStore struct {
+	endpoint   *url.URL
+	bucket     string
+	pathStyle  bool
+	signer     *signer
+	httpClient *http.Client
+}
+
+func New(config *Config) (*S3BlobStore, error) {
+	if config.Bucket == "" {
+		return nil, errors.New("s3blob: bucket must be set")
+	}
+
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("s3blob: invalid endpoint %q", config.Endpoint)
+	}
+
+	region := config.Region
+	if region == "" {
+		region = defaultRegion
+	}
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	return &S3BlobStore{
+		endpoint:  endpoint,
+		bucket:    config.Bucket,
+		pathStyle: config.PathStyle,
+		signer: &signer{
+			accessKeyId:     config.AccessKeyId,
+			secretAccessKey: config.SecretAccessKey,
+			region:          region,
+			service:         "s3",
+		},
+		httpClient: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Put uploads the object in a single request; the payload is streamed and left unsigned,
+// so the size must be known up front.
+func (bs *S3BlobStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	ctx, span := tracer.Start(ctx, "S3BlobStore.Put", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(blobSystemAttribute, attribute.Int64("blob.size", size)))
+	defer span.End()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPut, bs.objectURL(key), io.NopCloser(r))
+	if err != nil {
+		return err
+	}
+	request.ContentLength = size
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+
+	response, err := bs.do(request, unsignedPayload)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return fmt.Errorf("failed to put blob: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		err = responseError(response)
+		tracing.RecordError(span, err)
+		return fmt.Errorf("failed to put blob: %w", err)
+	}
+
+	return nil
+}
+
+func (bs *S3BlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	ctx, span := tracer.Start(ctx, "S3BlobStore.Get", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(blobSystemAttribute))
+	defer span.End()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, bs.objectURL(key), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := bs.do(request, emptyPayloadHash)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, fmt.Errorf("failed to get blob: %w", err)
+	}
+
+	switch response.StatusCode {
+	case http.StatusOK:
+		return response.Body, nil
+	case http.StatusNotFound:
+		response.Body.Close()
+		return nil, storage.ErrBlobNotFound
+	default:
+		defer response.Body.Close()
+		err = responseError(response)
+		tracing.RecordError(span, err)
+		return nil, fmt.Errorf("failed to get blob: %w", err)
+	}
+}
+
+func (bs *S3BlobStore) Delete(ctx context.Context, key string) error {
+	ctx, span := tracer.Start(ctx, "S3BlobStore.Delete", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(blobSystemAttribute))
+	defer span.End()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodDelete, bs.objectURL(key), nil)
+	if err != nil {
+		return err
+	}
+
+	response, err := bs.do(request, emptyPayloadHash)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return fmt.Errorf("failed to delete blob: %w", err)
+	}
+	defer response.Body.Close()
+
+	// S3 answers 204 for missing objects as well, some stand-ins answer 404.
+	if response.StatusCode != http.StatusNoContent && response.StatusCode != http.StatusOK && response.StatusCode != http.StatusNotFound {
+		err = responseError(response)
+		tracing.RecordError(span, err)
+		return fmt.Errorf("failed to delete blob: %w", err)
+	}
+
+	return nil
+}
+
+// Ping checks that the bucket exists and is accessible with the configured credentials.
+func (bs *S3BlobStore) Ping(ctx context.Context) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodHead, bs.bucketURL(), nil)
+	if err != nil {
+		return err
+	}
+
+	response, err := bs.do(request, emptyPayloadHash)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("bucket %s is not accessible: %s", bs.bucket, response.Status)
+	}
+
+	return nil
+}
+
+func (bs *S3BlobStore) do(request *http.Request, payloadHash string) (*http.Response, error) {
+	bs.signer.sign(request, payloadHash, time.Now())
+	return bs.httpClient.Do(request)
+}
+
+func (bs *S3BlobStore) bucketURL() string {
+	endpoint := *bs.endpoint
+	if bs.pathStyle {
+		endpoint.Path = strings.TrimSuffix(endpoint.Path, "/") + "/" + bs.bucket
+	} else {
+		endpoint.Host = bs.bucket + "." + endpoint.Host
+	}
+	return endpoint.String()
+}
+
+func (bs *S3BlobStore) objectURL(key string) string {
+	return bs.bucketURL() + "/" + encodePath(key)
+}
+
+type s3Error struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+// responseError describes a failed request by the S3 error code if the response carries one.
+func responseError(response *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(response.Body, maxErrorBodyBytes))
+
+	var errResponse s3Error
+	if xml.Unmarshal(body, &errResponse) == nil && errResponse.Code != "" {
+		return fmt.Errorf("%s: %s: %s", response.Status, errResponse.Code, errResponse.Message)
+	}
+
+	return errors.New(response.Status)
+}
@@ -0,0 +1,149 @@
+package s3blob
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	signingAlgorithm = "AWS4-HMAC-SHA256"
+	unsignedPayload  = "UNSIGNED-PAYLOAD"
+	// emptyPayloadHash is the hex encoded SHA-256 of an empty payload.
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+	amzDateFormat   = "20060102T150405Z"
+	amzDayFormat    = "20060102"
+	amzDateHeader   = "X-Amz-Date"
+	amzSha256Header = "X-Amz-Content-Sha256"
+)
+
+// signer signs requests with AWS Signature Version 4 in the Authorization header.
+// All headers set on the request when it is signed are signed along with the host.
+type signer struct {
+	accessKeyId     string
+	secretAccessKey string
+	region          string
+	service         string
+}
+
+func (s *signer) sign(request *http.Request, payloadHash string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format(amzDateFormat)
+	scope := now.Format(amzDayFormat) + "/" + s.region + "/" + s.service + "/aws4_request"
+
+	request.Header.Set(amzDateHeader, amzDate)
+	request.Header.Set(amzSha256Header, payloadHash)
+
+	signedHeaders, canonicalHeaders := canonicalizeHeaders(request)
+
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		encodePath(request.URL.Path),
+		canonicalQuery(request),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	stringToSign := strings.Join([]string{
+		signingAlgorithm,
+		amzDate,
+		scope,
+		hexSha256(canonicalRequest),
+	}, "\n")
+
+	signingKey := hmacSha256([]byte("AWS4"+s.secretAccessKey), now.Format(amzDayFormat))
+	signingKey = hmacSha256(signingKey, s.region)
+	signingKey = hmacSha256(signingKey, s.service)
+	signingKey = hmacSha256(signingKey, "aws4_request")
+
+	signature := hex.EncodeToString(hmacSha256(signingKey, stringToSign))
+
+	request.Header.Set("Authorization", signingAlgorithm+
+		" Credential="+s.accessKeyId+"/"+scope+
+		", SignedHeaders="+signedHeaders+
+		", Signature="+signature)
+}
+
+func canonicalizeHeaders(request *http.Request) (string, string) {
+	headers := map[string]string{"host": request.URL.Host}
+	for name, values := range request.Header {
+		trimmed := make([]string, len(values))
+		for i, value := range values {
+			trimmed[i] = strings.Join(strings.Fields(value), " ")
+		}
+		headers[strings.ToLower(name)] = strings.Join(trimmed, ",")
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+
+	return strings.Join(names, ";"), canonicalHeaders.String()
+}
+
+func canonicalQuery(request *http.Request) string {
+	query := request.URL.Query()
+
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, key := range keys {
+		values := query[key]
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, encodeComponent(key)+"="+encodeComponent(value))
+		}
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+// encodePath percent-encodes every byte of the path except the unreserved characters and slashes.
+func encodePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i := range segments {
+		segments[i] = encodeComponent(segments[i])
+	}
+	return strings.Join(segments, "/")
+}
+
+func encodeComponent(s string) string {
+	var encoded strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			encoded.WriteByte(c)
+			continue
+		}
+		encoded.WriteString("%" + strings.ToUpper(hex.EncodeToString([]byte{c})))
+	}
+	return encoded.String()
+}
+
+func hexSha256(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSha256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -10,4 +10,15 @@ type GoodLog struct {
 	Priority    int
 	Removed     bool
 	EventTime   time.Time
+	Actor       string
+	Event       string
+	Tags        []string
+	Attributes  string
+	// PriceAmount is nil if the good has no price.
+	PriceAmount   *int64
+	PriceCurrency string
+	// ParentId is nil if the good is not a variant.
+	ParentId *int64
+	// Locale is empty unless the event is about a translation.
+	Locale string
 }
@@ -3,9 +3,16 @@ package chgoodlog
 import (
 	"context"
 	"github.com/ClickHouse/clickhouse-go/v2"
-	"log"
+	"github.com/vaberof/hezzl-backend/pkg/metrics"
+	"github.com/vaberof/hezzl-backend/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"log/slog"
+	"time"
 )
 
+var tracer = tracing.Tracer("github.com/vaberof/hezzl-backend/internal/infra/storage/clickhouse/chgoodlog")
+
 type ClickHouseGoodLogStorage struct {
 	chConn clickhouse.Conn
 }
@@ -14,13 +21,20 @@ func NewCHGoodLogStorage(chConn clickhouse.Conn) *ClickHouseGoodLogStorage {
 	return &ClickHouseGoodLogStorage{chConn: chConn}
 }
 
-func (ch *ClickHouseGoodLogStorage) Insert(goodLogs []*GoodLog) error {
+func (ch *ClickHouseGoodLogStorage) Insert(ctx context.Context, goodLogs []*GoodLog) error {
+	ctx, span := tracer.Start(ctx, "ClickHouseGoodLogStorage.Insert",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("db.system", "clickhouse"), attribute.Int("db.batch_size", len(goodLogs))),
+	)
+	defer span.End()
+
 	query := `
 		INSERT INTO good_logs
 	`
 
-	batch, err := ch.chConn.PrepareBatch(context.Background(), query)
+	batch, err := ch.chConn.PrepareBatch(ctx, query)
 	if err != nil {
+		tracing.RecordError(span, err)
 		return err
 	}
 
@@ -33,17 +47,31 @@ func (ch *ClickHouseGoodLogStorage) Insert(goodLogs []*GoodLog) error {
 			&goodLogs[i].Priority,
 			&goodLogs[i].Removed,
 			&goodLogs[i].EventTime,
+			&goodLogs[i].Actor,
+			&goodLogs[i].Event,
+			&goodLogs[i].Tags,
+			&goodLogs[i].Attributes,
+			goodLogs[i].PriceAmount,
+			&goodLogs[i].PriceCurrency,
+			goodLogs[i].ParentId,
+			&goodLogs[i].Locale,
 		)
 		if err != nil {
+			tracing.RecordError(span, err)
 			return err
 		}
 	}
 
+	metrics.ClickHouseBatchSize.Observe(float64(len(goodLogs)))
+
+	sentAt := time.Now()
 	err = batch.Send()
+	metrics.ClickHouseFlushDuration.WithLabelValues(metrics.Status(err)).Observe(time.Since(sentAt).Seconds())
 	if err != nil {
-		log.Println("Failed to send a batch to clickhouse", err)
+		tracing.RecordError(span, err)
+		slog.ErrorContext(ctx, "failed to send a batch to clickhouse", "size", len(goodLogs), "error", err)
 	} else {
-		log.Println("Sent a batch to clickhouse successfully")
+		slog.DebugContext(ctx, "sent a batch to clickhouse", "size", len(goodLogs))
 	}
 
 	return err
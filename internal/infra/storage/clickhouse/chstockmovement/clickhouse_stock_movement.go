@@ -0,0 +1,16 @@
+package chstockmovement
+
+import "time"
+
+type StockMovement struct {
+	Id        int64
+	GoodId    int64
+	ProjectId int64
+	Kind      string
+	Quantity  int64
+	Stock     int64
+	Reserved  int64
+	Reason    string
+	Actor     string
+	EventTime time.Time
+}
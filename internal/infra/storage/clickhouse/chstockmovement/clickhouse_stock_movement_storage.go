@@ -0,0 +1,73 @@
+package chstockmovement
+
+import (
+	"context"
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/vaberof/hezzl-backend/pkg/metrics"
+	"github.com/vaberof/hezzl-backend/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"log/slog"
+	"time"
+)
+
+var tracer = tracing.Tracer("github.com/vaberof/hezzl-backend/internal/infra/storage/clickhouse/chstockmovement")
+
+type ClickHouseStockMovementStorage struct {
+	chConn clickhouse.Conn
+}
+
+func NewCHStockMovementStorage(chConn clickhouse.Conn) *ClickHouseStockMovementStorage {
+	return &ClickHouseStockMovementStorage{chConn: chConn}
+}
+
+func (ch *ClickHouseStockMovementStorage) Insert(ctx context.Context, stockMovements []*StockMovement) error {
+	ctx, span := tracer.Start(ctx, "ClickHouseStockMovementStorage.Insert",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("db.system", "clickhouse"), attribute.Int("db.batch_size", len(stockMovements))),
+	)
+	defer span.End()
+
+	query := `
+		INSERT INTO stock_movements
+	`
+
+	batch, err := ch.chConn.PrepareBatch(ctx, query)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return err
+	}
+
+	for i := range stockMovements {
+		err = batch.Append(
+			&stockMovements[i].Id,
+			&stockMovements[i].GoodId,
+			&stockMovements[i].ProjectId,
+			&stockMovements[i].Kind,
+			&stockMovements[i].Quantity,
+			&stockMovements[i].Stock,
+			&stockMovements[i].Reserved,
+			&stockMovements[i].Reason,
+			&stockMovements[i].Actor,
+			&stockMovements[i].EventTime,
+		)
+		if err != nil {
+			tracing.RecordError(span, err)
+			return err
+		}
+	}
+
+	metrics.ClickHouseBatchSize.Observe(float64(len(stockMovements)))
+
+	sentAt := time.Now()
+	err = batch.Send()
+	metrics.ClickHouseFlushDuration.WithLabelValues(metrics.Status(err)).Observe(time.Since(sentAt).Seconds())
+	if err != nil {
+		tracing.RecordError(span, err)
+		slog.ErrorContext(ctx, "failed to send a batch to clickhouse", "size", len(stockMovements), "error", err)
+	} else {
+		slog.DebugContext(ctx, "sent a batch to clickhouse", "size", len(stockMovements))
+	}
+
+	return err
+}
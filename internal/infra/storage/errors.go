@@ -3,7 +3,21 @@ package storage
 import "errors"
 
 var (
-	ErrPostgresGoodNotFound = errors.New("good not found")
+	ErrPostgresGoodNotFound            = errors.New("good not found")
+	ErrPostgresGoodVersionMismatch     = errors.New("good version mismatch")
+	ErrPostgresGoodNotRemoved          = errors.New("good is not removed")
+	ErrPostgresGoodRemoved             = errors.New("good is removed")
+	ErrPostgresProjectNotFound         = errors.New("project not found")
+	ErrPostgresAttributeSchemaNotFound = errors.New("attribute schema not found")
+	ErrPostgresAttachmentNotFound      = errors.New("attachment not found")
+	ErrPostgresInsufficientStock       = errors.New("insufficient stock")
+	ErrPostgresNestedVariant           = errors.New("variants cannot have variants")
+	ErrPostgresParentRemoved           = errors.New("parent good is removed")
+	ErrPostgresTranslationNotFound     = errors.New("translation not found")
+	ErrPostgresVariantTransfer         = errors.New("variants are transferred with their parent")
+	ErrPostgresExternalKeyConflict     = errors.New("external key is taken in the target project")
 
 	ErrRedisKeyNotFound = errors.New("key not found")
+
+	ErrBlobNotFound = errors.New("blob not found")
 )
@@ -1,6 +1,7 @@
 package pggood
 
 import (
+	"encoding/json"
 	"github.com/vaberof/hezzl-backend/internal/domain/good"
 	"github.com/vaberof/hezzl-backend/pkg/domain"
 )
@@ -14,6 +15,21 @@ func toDomainGoods(postgresGoods []*Good) []*good.Good {
 }
 
 func toDomainGood(postgresGood *Good) *good.Good {
+	// The column is a JSONB object, so it always decodes.
+	var attributes domain.GoodAttributes
+	_ = json.Unmarshal(postgresGood.Attributes, &attributes)
+
+	var price *good.Price
+	if postgresGood.PriceAmount.Valid {
+		price = &good.Price{Amount: postgresGood.PriceAmount.Int64, Currency: postgresGood.PriceCurrency.String}
+	}
+
+	var parentId *domain.GoodId
+	if postgresGood.ParentId.Valid {
+		id := domain.GoodId(postgresGood.ParentId.Int64)
+		parentId = &id
+	}
+
 	return &good.Good{
 		Id:          domain.GoodId(postgresGood.Id),
 		ProjectId:   domain.ProjectId(postgresGood.ProjectId),
@@ -22,5 +38,14 @@ func toDomainGood(postgresGood *Good) *good.Good {
 		Priority:    domain.GoodPriority(postgresGood.Priority),
 		Removed:     domain.GoodRemoved(postgresGood.Removed),
 		CreatedAt:   domain.GoodCreatedAt(postgresGood.CreatedAt),
+		CreatedBy:   domain.Actor(postgresGood.CreatedBy.String),
+		UpdatedBy:   domain.Actor(postgresGood.UpdatedBy.String),
+		Version:     domain.GoodVersion(postgresGood.Version),
+		ExternalKey: domain.GoodExternalKey(postgresGood.ExternalKey.String),
+		Attributes:  attributes,
+		Price:       price,
+		Stock:       postgresGood.Stock,
+		Reserved:    postgresGood.Reserved,
+		ParentId:    parentId,
 	}
 }
@@ -2,6 +2,7 @@ package pggood
 
 import (
 	"database/sql"
+	"strings"
 	"time"
 )
 
@@ -13,4 +14,92 @@ type Good struct {
 	Priority    int
 	Removed     bool
 	CreatedAt   time.Time
+	CreatedBy   sql.NullString
+	UpdatedBy   sql.NullString
+	Version     int64
+	ExternalKey sql.NullString
+	Attributes  []byte
+	// PriceAmount and PriceCurrency are either both set or both null.
+	PriceAmount   sql.NullInt64
+	PriceCurrency sql.NullString
+	Stock         int64
+	Reserved      int64
+	// ParentId is set for variants.
+	ParentId sql.NullInt64
+}
+
+// goodColumnNames are the columns of a good in the order goodScanDest scans them.
+var goodColumnNames = []string{
+	"id",
+	"project_id",
+	"name",
+	"description",
+	"priority",
+	"removed",
+	"created_at",
+	"created_by",
+	"updated_by",
+	"version",
+	"external_key",
+	"attributes",
+	"price_amount",
+	"price_currency",
+	"stock",
+	"reserved",
+	"parent_id",
+}
+
+// goodColumns lists the columns of a good for SELECT and RETURNING clauses that read a single table.
+var goodColumns = strings.Join(goodColumnNames, ", ")
+
+// qualifiedGoodColumns lists the columns of a good qualified with table, for queries that join other tables.
+func qualifiedGoodColumns(table string) string {
+	columns := make([]string, len(goodColumnNames))
+	for i, column := range goodColumnNames {
+		columns[i] = table + "." + column
+	}
+	return strings.Join(columns, ", ")
+}
+
+// goodScanDest returns the fields of postgresGood that the columns of goodColumns are scanned into.
+// Queries that select more columns after them append their own destinations.
+func goodScanDest(postgresGood *Good) []any {
+	return []any{
+		&postgresGood.Id,
+		&postgresGood.ProjectId,
+		&postgresGood.Name,
+		&postgresGood.Description,
+		&postgresGood.Priority,
+		&postgresGood.Removed,
+		&postgresGood.CreatedAt,
+		&postgresGood.CreatedBy,
+		&postgresGood.UpdatedBy,
+		&postgresGood.Version,
+		&postgresGood.ExternalKey,
+		&postgresGood.Attributes,
+		&postgresGood.PriceAmount,
+		&postgresGood.PriceCurrency,
+		&postgresGood.Stock,
+		&postgresGood.Reserved,
+		&postgresGood.ParentId,
+	}
+}
+
+func scanGoods(rows *sql.Rows) ([]*Good, error) {
+	defer rows.Close()
+
+	var postgresGoods []*Good
+
+	for rows.Next() {
+		var postgresGood Good
+
+		err := rows.Scan(goodScanDest(&postgresGood)...)
+		if err != nil {
+			return nil, err
+		}
+
+		postgresGoods = append(postgresGoods, &postgresGood)
+	}
+
+	return postgresGoods, rows.Err()
 }
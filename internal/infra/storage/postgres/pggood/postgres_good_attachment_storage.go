@@ -0,0 +1,304 @@
+package pggood
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"github.com/vaberof/hezzl-backend/pkg/metrics"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const attachmentColumns = `
+			    id,
+			    good_id,
+			    project_id,
+			    name,
+			    content_type,
+			    size,
+			    checksum,
+			    storage_key,
+			    created_at,
+			    created_by
+`
+
+// CreateAttachment records the attachment of a good that is not removed.
+func (gs *PgGoodStorage) CreateAttachment(ctx context.Context, attachment *good.Attachment, actor domain.Actor) (*good.Attachment, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.CreateAttachment", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("CreateAttachment").ObserveDuration()
+
+	tx, err := gs.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction while creating attachment: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err = shareActiveGood(ctx, tx, attachment.GoodId, attachment.ProjectId); err != nil {
+		return nil, fmt.Errorf("failed to create attachment: %w", err)
+	}
+
+	query := `
+			INSERT INTO attachments(
+			                        good_id,
+			                        project_id,
+			                        name,
+			                        content_type,
+			                        size,
+			                        checksum,
+			                        storage_key,
+			                        created_by
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING ` + attachmentColumns
+
+	createdAttachment, err := scanAttachment(tx.QueryRowContext(ctx, query,
+		attachment.GoodId,
+		attachment.ProjectId,
+		attachment.Name,
+		attachment.ContentType,
+		attachment.Size,
+		attachment.Checksum,
+		attachment.StorageKey,
+		actor,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create attachment in database: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction while creating attachment: %w", err)
+	}
+
+	return createdAttachment, nil
+}
+
+// ListAttachments lists the attachments of the good in the order they were added.
+func (gs *PgGoodStorage) ListAttachments(ctx context.Context, id domain.GoodId, projectId domain.ProjectId) ([]*good.Attachment, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.ListAttachments", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("ListAttachments").ObserveDuration()
+
+	// The good is joined, so that a good without attachments is told apart from a missing one.
+	query := `
+			SELECT
+			    attachments.id,
+			    goods.id,
+			    goods.project_id,
+			    attachments.name,
+			    attachments.content_type,
+			    attachments.size,
+			    attachments.checksum,
+			    attachments.storage_key,
+			    attachments.created_at,
+			    attachments.created_by
+			FROM goods
+			LEFT JOIN attachments ON attachments.good_id = goods.id
+			WHERE goods.id = $1 AND goods.project_id = $2
+			ORDER BY attachments.id
+	`
+
+	rows, err := gs.db.QueryContext(ctx, query, id, projectId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+	defer rows.Close()
+
+	var found bool
+	attachments := make([]*good.Attachment, 0)
+
+	for rows.Next() {
+		found = true
+
+		var (
+			attachmentId sql.NullInt64
+			goodId       int64
+			goodProject  int64
+			name         sql.NullString
+			contentType  sql.NullString
+			size         sql.NullInt64
+			checksum     sql.NullString
+			storageKey   sql.NullString
+			createdAt    sql.NullTime
+			createdBy    sql.NullString
+		)
+		if err = rows.Scan(&attachmentId, &goodId, &goodProject, &name, &contentType, &size, &checksum, &storageKey, &createdAt, &createdBy); err != nil {
+			return nil, fmt.Errorf("failed to scan while listing attachments: %w", err)
+		}
+
+		if !attachmentId.Valid {
+			continue
+		}
+
+		attachments = append(attachments, &good.Attachment{
+			Id:          domain.AttachmentId(attachmentId.Int64),
+			GoodId:      domain.GoodId(goodId),
+			ProjectId:   domain.ProjectId(goodProject),
+			Name:        name.String,
+			ContentType: contentType.String,
+			Size:        size.Int64,
+			Checksum:    checksum.String,
+			StorageKey:  storageKey.String,
+			CreatedAt:   createdAt.Time,
+			CreatedBy:   domain.Actor(createdBy.String),
+		})
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+
+	if !found {
+		return nil, storage.ErrPostgresGoodNotFound
+	}
+
+	return attachments, nil
+}
+
+func (gs *PgGoodStorage) GetAttachment(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, attachmentId domain.AttachmentId) (*good.Attachment, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.GetAttachment", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("GetAttachment").ObserveDuration()
+
+	query := `
+			SELECT ` + attachmentColumns + `
+			FROM attachments
+			WHERE id=$1 AND good_id=$2 AND project_id=$3
+	`
+
+	attachment, err := scanAttachment(gs.db.QueryRowContext(ctx, query, attachmentId, id, projectId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to get attachment: %w", storage.ErrPostgresAttachmentNotFound)
+		}
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+
+	return attachment, nil
+}
+
+// DeleteAttachment deletes the attachment of a good that is not removed. The storage key of the attachment
+// is queued for deletion by a trigger in the same transaction, unless copies of the attachment still refer to it;
+// queued reports whether it was.
+func (gs *PgGoodStorage) DeleteAttachment(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, attachmentId domain.AttachmentId) (*good.Attachment, bool, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.DeleteAttachment", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("DeleteAttachment").ObserveDuration()
+
+	tx, err := gs.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to start transaction while deleting attachment: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err = shareActiveGood(ctx, tx, id, projectId); err != nil {
+		return nil, false, fmt.Errorf("failed to delete attachment: %w", err)
+	}
+
+	query := `
+			DELETE FROM attachments
+			WHERE id=$1 AND good_id=$2 AND project_id=$3
+			RETURNING ` + attachmentColumns
+
+	attachment, err := scanAttachment(tx.QueryRowContext(ctx, query, attachmentId, id, projectId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, fmt.Errorf("failed to delete attachment: %w", storage.ErrPostgresAttachmentNotFound)
+		}
+		return nil, false, fmt.Errorf("failed to delete attachment: %w", err)
+	}
+
+	var queued bool
+
+	err = tx.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM attachment_blob_deletions WHERE storage_key=$1)", attachment.StorageKey).Scan(&queued)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to delete attachment: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, false, fmt.Errorf("failed to commit transaction while deleting attachment: %w", err)
+	}
+
+	return attachment, queued, nil
+}
+
+// QueuedBlobDeletions returns up to limit storage keys of deleted attachments whose blobs may still exist, oldest first.
+func (gs *PgGoodStorage) QueuedBlobDeletions(ctx context.Context, limit int) ([]string, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.QueuedBlobDeletions", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("QueuedBlobDeletions").ObserveDuration()
+
+	rows, err := gs.db.QueryContext(ctx, "SELECT storage_key FROM attachment_blob_deletions ORDER BY created_at, storage_key LIMIT $1", limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get queued blob deletions: %w", err)
+	}
+	defer rows.Close()
+
+	var storageKeys []string
+
+	for rows.Next() {
+		var storageKey string
+		if err = rows.Scan(&storageKey); err != nil {
+			return nil, fmt.Errorf("failed to scan while getting queued blob deletions: %w", err)
+		}
+		storageKeys = append(storageKeys, storageKey)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get queued blob deletions: %w", err)
+	}
+
+	return storageKeys, nil
+}
+
+// CompleteBlobDeletions dequeues the storage keys whose blobs have been deleted.
+func (gs *PgGoodStorage) CompleteBlobDeletions(ctx context.Context, storageKeys []string) error {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.CompleteBlobDeletions", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("CompleteBlobDeletions").ObserveDuration()
+
+	_, err := gs.db.ExecContext(ctx, "DELETE FROM attachment_blob_deletions WHERE storage_key = ANY($1)", pq.Array(storageKeys))
+	if err != nil {
+		return fmt.Errorf("failed to complete blob deletions: %w", err)
+	}
+
+	return nil
+}
+
+func scanAttachment(row *sql.Row) (*good.Attachment, error) {
+	var (
+		attachment good.Attachment
+		createdAt  sql.NullTime
+		createdBy  sql.NullString
+	)
+
+	err := row.Scan(
+		&attachment.Id,
+		&attachment.GoodId,
+		&attachment.ProjectId,
+		&attachment.Name,
+		&attachment.ContentType,
+		&attachment.Size,
+		&attachment.Checksum,
+		&attachment.StorageKey,
+		&createdAt,
+		&createdBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment.CreatedAt = createdAt.Time
+	attachment.CreatedBy = domain.Actor(createdBy.String)
+
+	return &attachment, nil
+}
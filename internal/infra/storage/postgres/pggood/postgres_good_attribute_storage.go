@@ -0,0 +1,122 @@
+package pggood
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"github.com/vaberof/hezzl-backend/pkg/metrics"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func (gs *PgGoodStorage) GetAttributeSchema(ctx context.Context, projectId domain.ProjectId) (*good.AttributeSchema, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.GetAttributeSchema", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("GetAttributeSchema").ObserveDuration()
+
+	query := `
+			SELECT project_id, schema, updated_at, updated_by
+			FROM project_attribute_schemas
+			WHERE project_id=$1
+	`
+
+	attributeSchema, err := scanAttributeSchema(gs.db.QueryRowContext(ctx, query, projectId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to get attribute schema: %w", storage.ErrPostgresAttributeSchemaNotFound)
+		}
+		return nil, fmt.Errorf("failed to get attribute schema: %w", err)
+	}
+
+	return attributeSchema, nil
+}
+
+func (gs *PgGoodStorage) SetAttributeSchema(ctx context.Context, projectId domain.ProjectId, schema json.RawMessage, actor domain.Actor) (*good.AttributeSchema, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.SetAttributeSchema", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("SetAttributeSchema").ObserveDuration()
+
+	query := `
+			INSERT INTO project_attribute_schemas(project_id, schema, updated_by)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (project_id) DO UPDATE
+			SET schema=EXCLUDED.schema,
+			    updated_at=CURRENT_TIMESTAMP,
+			    updated_by=EXCLUDED.updated_by
+			RETURNING project_id, schema, updated_at, updated_by
+	`
+
+	attributeSchema, err := scanAttributeSchema(gs.db.QueryRowContext(ctx, query, projectId, string(schema), actor))
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+			return nil, fmt.Errorf("failed to set attribute schema: %w", storage.ErrPostgresProjectNotFound)
+		}
+		return nil, fmt.Errorf("failed to set attribute schema: %w", err)
+	}
+
+	return attributeSchema, nil
+}
+
+func (gs *PgGoodStorage) DeleteAttributeSchema(ctx context.Context, projectId domain.ProjectId) error {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.DeleteAttributeSchema", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("DeleteAttributeSchema").ObserveDuration()
+
+	result, err := gs.db.ExecContext(ctx, "DELETE FROM project_attribute_schemas WHERE project_id=$1", projectId)
+	if err != nil {
+		return fmt.Errorf("failed to delete attribute schema: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete attribute schema: %w", err)
+	}
+	if deleted == 0 {
+		return fmt.Errorf("failed to delete attribute schema: %w", storage.ErrPostgresAttributeSchemaNotFound)
+	}
+
+	return nil
+}
+
+func scanAttributeSchema(row *sql.Row) (*good.AttributeSchema, error) {
+	var (
+		projectId int64
+		schema    []byte
+		updatedAt sql.NullTime
+		updatedBy sql.NullString
+	)
+
+	if err := row.Scan(&projectId, &schema, &updatedAt, &updatedBy); err != nil {
+		return nil, err
+	}
+
+	return &good.AttributeSchema{
+		ProjectId: domain.ProjectId(projectId),
+		Schema:    schema,
+		UpdatedAt: updatedAt.Time,
+		UpdatedBy: domain.Actor(updatedBy.String),
+	}, nil
+}
+
+// attributesParam encodes the attributes for a JSONB parameter; nil attributes are passed as NULL.
+func attributesParam(attributes domain.GoodAttributes) (sql.NullString, error) {
+	if attributes == nil {
+		return sql.NullString{}, nil
+	}
+
+	attributesJSON, err := json.Marshal(attributes)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("failed to encode attributes: %w", err)
+	}
+
+	return sql.NullString{String: string(attributesJSON), Valid: true}, nil
+}
@@ -0,0 +1,335 @@
+package pggood
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/lib/pq"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/internal/infra/messagebroker/nats/publisher"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"github.com/vaberof/hezzl-backend/pkg/metrics"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// CreateBatch inserts all goods with a single multi-row insert; either all of them are created or none.
+// The goods are returned in the order of items.
+func (gs *PgGoodStorage) CreateBatch(ctx context.Context, items []*good.CreateItem, actor domain.Actor) ([]*good.Good, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.CreateBatch", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute, attribute.Int("db.batch_size", len(items))))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("CreateBatch").ObserveDuration()
+
+	args := make([]any, 0, 3*len(items)+1)
+	args = append(args, actor)
+
+	var values strings.Builder
+	for i, item := range items {
+		if i > 0 {
+			values.WriteString(", ")
+		}
+		attributesJSON, err := attributesParam(item.Attributes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create goods in database: %w", err)
+		}
+		args = append(args, item.ProjectId, item.Name, attributesJSON)
+		values.WriteString("($" + strconv.Itoa(len(args)-2) + ", $" + strconv.Itoa(len(args)-1) + ", $1, $1, COALESCE($" + strconv.Itoa(len(args)) + "::jsonb, '{}'))")
+	}
+
+	query := `
+			INSERT INTO goods(
+			                  project_id,
+			                  name,
+			                  created_by,
+			                  updated_by,
+			                  attributes
+			) VALUES ` + values.String() + `
+			RETURNING ` + goodColumns
+
+	rows, err := gs.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create goods in database: %w", err)
+	}
+
+	postgresGoods, err := scanGoods(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create goods in database: %w", err)
+	}
+
+	gs.publishGoodLogs(ctx, publisher.GoodEventCreated, postgresGoods)
+
+	return toDomainGoods(postgresGoods), nil
+}
+
+func (gs *PgGoodStorage) GetBatch(ctx context.Context, ids []domain.GoodId) ([]*good.Good, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.GetBatch", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute, attribute.Int("db.batch_size", len(ids))))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("GetBatch").ObserveDuration()
+
+	query := `
+			SELECT ` + goodColumns + `
+			FROM goods
+			WHERE id = ANY($1)
+	`
+
+	rows, err := gs.db.QueryContext(ctx, query, pq.Array(toInt64s(ids)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get goods: %w", err)
+	}
+
+	postgresGoods, err := scanGoods(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get goods: %w", err)
+	}
+
+	return toDomainGoods(postgresGoods), nil
+}
+
+// UpdateBatch updates the goods in a single transaction. Items that do not exist or whose version
+// does not match are skipped and reported in their results, the rest are updated.
+func (gs *PgGoodStorage) UpdateBatch(ctx context.Context, items []*good.UpdateItem, actor domain.Actor) ([]*good.BatchResult, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.UpdateBatch", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute, attribute.Int("db.batch_size", len(items))))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("UpdateBatch").ObserveDuration()
+
+	tx, err := gs.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction while updating goods: %w", err)
+	}
+	defer tx.Rollback()
+
+	ids := make([]domain.GoodId, len(items))
+	for i, item := range items {
+		ids[i] = item.Id
+	}
+
+	lockedGoods, err := lockGoods(ctx, tx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update goods: %w", err)
+	}
+
+	results := make([]*good.BatchResult, len(items))
+	itemIndexes := make(map[int64]int, len(items))
+
+	var (
+		updateIds          []int64
+		updateNames        []string
+		updateDescriptions []sql.NullString
+		updateAttributes   []sql.NullString
+	)
+
+	for i, item := range items {
+		if err = checkLockedGood(lockedGoods, item.Id, item.ProjectId, item.Version); err != nil {
+			results[i] = &good.BatchResult{Err: err}
+			continue
+		}
+
+		itemIndexes[item.Id.Int64()] = i
+
+		updateIds = append(updateIds, item.Id.Int64())
+		updateNames = append(updateNames, item.Name.String())
+		updateDescriptions = append(updateDescriptions, sql.NullString{String: item.Description.String(), Valid: item.Description != nil})
+
+		attributesJSON, err := attributesParam(item.Attributes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update goods in database: %w", err)
+		}
+		updateAttributes = append(updateAttributes, attributesJSON)
+	}
+
+	if len(updateIds) == 0 {
+		return results, nil
+	}
+
+	query := `
+		UPDATE goods AS g
+		SET name=v.name, 
+		    description=COALESCE(v.description, g.description),
+		    attributes=COALESCE(v.attributes, g.attributes),
+		    updated_by=$1,
+		    version=g.version+1
+		FROM unnest($2::bigint[], $3::text[], $4::text[], $5::jsonb[]) AS v(id, name, description, attributes)
+		WHERE g.id=v.id
+		RETURNING ` + qualifiedGoodColumns("g")
+
+	rows, err := tx.QueryContext(ctx, query, actor, pq.Array(updateIds), pq.Array(updateNames), pq.Array(updateDescriptions), pq.Array(updateAttributes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to update goods in database: %w", err)
+	}
+
+	postgresGoods, err := scanGoods(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update goods in database: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction while updating goods: %w", err)
+	}
+
+	for _, postgresGood := range postgresGoods {
+		results[itemIndexes[postgresGood.Id]] = &good.BatchResult{Good: toDomainGood(postgresGood)}
+	}
+
+	gs.publishGoodLogs(ctx, publisher.GoodEventUpdated, postgresGoods)
+
+	return results, nil
+}
+
+// DeleteBatch removes the goods in a single transaction. Items that do not exist or whose version
+// does not match are skipped and reported in their results, the rest are removed.
+func (gs *PgGoodStorage) DeleteBatch(ctx context.Context, items []*good.RemoveItem, actor domain.Actor) ([]*good.BatchResult, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.DeleteBatch", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute, attribute.Int("db.batch_size", len(items))))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("DeleteBatch").ObserveDuration()
+
+	tx, err := gs.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction while deleting goods: %w", err)
+	}
+	defer tx.Rollback()
+
+	ids := make([]domain.GoodId, len(items))
+	for i, item := range items {
+		ids[i] = item.Id
+	}
+
+	lockedGoods, err := lockGoods(ctx, tx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete goods: %w", err)
+	}
+
+	results := make([]*good.BatchResult, len(items))
+	itemIndexes := make(map[int64]int, len(items))
+
+	var deleteIds []int64
+
+	for i, item := range items {
+		if err = checkLockedGood(lockedGoods, item.Id, item.ProjectId, item.Version); err != nil {
+			results[i] = &good.BatchResult{Err: err}
+			continue
+		}
+
+		itemIndexes[item.Id.Int64()] = i
+
+		deleteIds = append(deleteIds, item.Id.Int64())
+	}
+
+	if len(deleteIds) == 0 {
+		return results, nil
+	}
+
+	query := `
+		UPDATE goods SET removed=TRUE,
+		                 removed_at=COALESCE(removed_at, CURRENT_TIMESTAMP),
+		                 updated_by=$1,
+		                 version=version+1
+		             WHERE id = ANY($2)
+		RETURNING ` + goodColumns
+
+	rows, err := tx.QueryContext(ctx, query, actor, pq.Array(deleteIds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete goods: %w", err)
+	}
+
+	postgresGoods, err := scanGoods(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete goods: %w", err)
+	}
+
+	// Variants are removed along with their parents.
+	postgresVariants, err := removeVariants(ctx, tx, deleteIds, actor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete goods: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction while deleting goods: %w", err)
+	}
+
+	variantsByParent := make(map[int64][]*Good)
+	for _, postgresVariant := range postgresVariants {
+		variantsByParent[postgresVariant.ParentId.Int64] = append(variantsByParent[postgresVariant.ParentId.Int64], postgresVariant)
+	}
+
+	for _, postgresGood := range postgresGoods {
+		results[itemIndexes[postgresGood.Id]] = &good.BatchResult{Good: withVariants(toDomainGood(postgresGood), variantsByParent[postgresGood.Id])}
+	}
+
+	gs.publishGoodLogs(ctx, publisher.GoodEventRemoved, append(postgresGoods, postgresVariants...))
+
+	return results, nil
+}
+
+func (gs *PgGoodStorage) publishGoodLogs(ctx context.Context, event string, postgresGoods []*Good) {
+	if len(postgresGoods) == 0 {
+		return
+	}
+
+	goodLogs := make([]*publisher.GoodLog, len(postgresGoods))
+	for i, postgresGood := range postgresGoods {
+		goodLogs[i] = newGoodLog(event, postgresGood, postgresGood.UpdatedBy.String, postgresGood.CreatedAt)
+	}
+
+	if err := gs.goodLogPublisher.PublishGoodLogs(ctx, goodLogs); err != nil {
+		slog.ErrorContext(ctx, "failed to publish good logs", "event", event, "count", len(goodLogs), "error", err)
+	}
+}
+
+type lockedGood struct {
+	projectId int64
+	version   int64
+}
+
+// lockGoods locks the rows of the existing goods until the end of tx in the order of their ids to avoid deadlocks.
+func lockGoods(ctx context.Context, tx *sql.Tx, ids []domain.GoodId) (map[int64]lockedGood, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT id, project_id, version FROM goods WHERE id = ANY($1) ORDER BY id FOR UPDATE", pq.Array(toInt64s(ids)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock goods: %w", err)
+	}
+	defer rows.Close()
+
+	lockedGoods := make(map[int64]lockedGood, len(ids))
+
+	for rows.Next() {
+		var (
+			id     int64
+			locked lockedGood
+		)
+		if err = rows.Scan(&id, &locked.projectId, &locked.version); err != nil {
+			return nil, fmt.Errorf("failed to scan while locking goods: %w", err)
+		}
+		lockedGoods[id] = locked
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to lock goods: %w", err)
+	}
+
+	return lockedGoods, nil
+}
+
+func checkLockedGood(lockedGoods map[int64]lockedGood, id domain.GoodId, projectId domain.ProjectId, expectedVersion *domain.GoodVersion) error {
+	locked, ok := lockedGoods[id.Int64()]
+	if !ok || locked.projectId != projectId.Int64() {
+		return storage.ErrPostgresGoodNotFound
+	}
+	if expectedVersion != nil && expectedVersion.Int64() != locked.version {
+		return storage.ErrPostgresGoodVersionMismatch
+	}
+	return nil
+}
+
+func toInt64s(ids []domain.GoodId) []int64 {
+	int64s := make([]int64, len(ids))
+	for i := range ids {
+		int64s[i] = ids[i].Int64()
+	}
+	return int64s
+}
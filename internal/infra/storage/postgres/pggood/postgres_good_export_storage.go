@@ -0,0 +1,117 @@
+package pggood
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/lib/pq"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/pkg/metrics"
+	"go.opentelemetry.io/otel/trace"
+	"strconv"
+	"strings"
+)
+
+const exportFetchSize = 1000
+
+// Export reads the goods through a server-side cursor, so that only exportFetchSize goods are held in memory at a time.
+// The cursor lives in a read-only transaction which is kept open until fn has consumed all goods,
+// so every export sees a single snapshot of the table.
+func (gs *PgGoodStorage) Export(ctx context.Context, filter *good.ListFilter, fn func(*good.Good) error) error {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.Export", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("Export").ObserveDuration()
+
+	tx, err := gs.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("failed to start transaction while exporting goods: %w", err)
+	}
+	defer tx.Rollback()
+
+	filterCondition, args := listFilterCondition(filter)
+
+	query := `
+			DECLARE goods_export NO SCROLL CURSOR FOR
+			SELECT ` + goodColumns + `
+			FROM goods
+			` + filterCondition + `
+			ORDER BY id
+	`
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to declare cursor while exporting goods: %w", err)
+	}
+
+	fetchQuery := "FETCH FORWARD " + strconv.Itoa(exportFetchSize) + " FROM goods_export"
+
+	for {
+		rows, err := tx.QueryContext(ctx, fetchQuery)
+		if err != nil {
+			return fmt.Errorf("failed to fetch goods while exporting goods: %w", err)
+		}
+
+		postgresGoods, err := scanGoods(rows)
+		if err != nil {
+			return fmt.Errorf("failed to scan while exporting goods: %w", err)
+		}
+
+		for _, postgresGood := range postgresGoods {
+			if err = fn(toDomainGood(postgresGood)); err != nil {
+				return err
+			}
+		}
+
+		if len(postgresGoods) < exportFetchSize {
+			return nil
+		}
+	}
+}
+
+// listFilterCondition returns the WHERE clause for filter and its arguments, numbered from $1.
+func listFilterCondition(filter *good.ListFilter) (string, []any) {
+	if filter == nil {
+		return "", nil
+	}
+
+	var conditions []string
+	var args []any
+
+	if filter.ProjectId != nil {
+		args = append(args, filter.ProjectId.Int64())
+		conditions = append(conditions, "project_id = $"+strconv.Itoa(len(args)))
+	}
+	if filter.Removed != nil {
+		args = append(args, *filter.Removed)
+		conditions = append(conditions, "removed = $"+strconv.Itoa(len(args)))
+	}
+	if len(filter.Tags) > 0 {
+		args = append(args, pq.Array(toStrings(filter.Tags)), len(filter.Tags))
+		conditions = append(conditions, `id IN (
+			    SELECT good_tags.good_id
+			    FROM good_tags
+			    JOIN tags ON tags.id = good_tags.tag_id
+			    WHERE tags.name = ANY($`+strconv.Itoa(len(args)-1)+`)
+			    GROUP BY good_tags.good_id
+			    HAVING COUNT(*) = $`+strconv.Itoa(len(args))+`
+			)`)
+	}
+	if len(filter.Attributes) > 0 {
+		// The attributes were decoded from JSON, so they always encode.
+		attributes, _ := json.Marshal(filter.Attributes)
+		args = append(args, string(attributes))
+		conditions = append(conditions, "attributes @> $"+strconv.Itoa(len(args))+"::jsonb")
+	}
+
+	if filter.TopLevel {
+		conditions = append(conditions, "parent_id IS NULL")
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
@@ -0,0 +1,115 @@
+package pggood
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/internal/infra/messagebroker/nats/publisher"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"github.com/vaberof/hezzl-backend/pkg/metrics"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"strconv"
+	"strings"
+)
+
+const foreignKeyViolation = "23503"
+
+// UpsertBatch creates the goods whose external keys are new to the project and updates the others
+// with a single multi-row insert. Removed goods are read-only and are reported as such instead of being updated.
+// The external keys of rows must be unique.
+func (gs *PgGoodStorage) UpsertBatch(ctx context.Context, projectId domain.ProjectId, rows []*good.ImportRow, actor domain.Actor) ([]*good.UpsertResult, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.UpsertBatch", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute, attribute.Int("db.batch_size", len(rows))))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("UpsertBatch").ObserveDuration()
+
+	args := make([]any, 0, 3*len(rows)+2)
+	args = append(args, actor, projectId)
+
+	var values strings.Builder
+	for i, row := range rows {
+		if i > 0 {
+			values.WriteString(", ")
+		}
+
+		description := sql.NullString{String: row.Description.String(), Valid: row.Description != nil}
+		args = append(args, row.ExternalKey, row.Name, description)
+
+		values.WriteString("($2, $" + strconv.Itoa(len(args)-2) + ", $" + strconv.Itoa(len(args)-1) + ", $" + strconv.Itoa(len(args)) + ", $1, $1)")
+	}
+
+	query := `
+			INSERT INTO goods(
+			                  project_id,
+			                  external_key,
+			                  name,
+			                  description,
+			                  created_by,
+			                  updated_by
+			) VALUES ` + values.String() + `
+			ON CONFLICT (project_id, external_key) DO UPDATE
+			SET name=EXCLUDED.name,
+			    description=COALESCE(EXCLUDED.description, goods.description),
+			    updated_by=EXCLUDED.updated_by,
+			    version=goods.version+1
+			WHERE NOT goods.removed
+			RETURNING ` + goodColumns + `,
+			    xmax = 0
+	`
+
+	sqlRows, err := gs.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+			return nil, fmt.Errorf("failed to upsert goods in database: %w", storage.ErrPostgresProjectNotFound)
+		}
+		return nil, fmt.Errorf("failed to upsert goods in database: %w", err)
+	}
+	defer sqlRows.Close()
+
+	var createdGoods, updatedGoods []*Good
+
+	upsertedGoods := make(map[string]*good.UpsertResult, len(rows))
+
+	for sqlRows.Next() {
+		var (
+			postgresGood Good
+			created      bool
+		)
+
+		err = sqlRows.Scan(append(goodScanDest(&postgresGood), &created)...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan while upserting goods: %w", err)
+		}
+
+		if created {
+			createdGoods = append(createdGoods, &postgresGood)
+		} else {
+			updatedGoods = append(updatedGoods, &postgresGood)
+		}
+
+		upsertedGoods[postgresGood.ExternalKey.String] = &good.UpsertResult{Good: toDomainGood(&postgresGood), Created: created}
+	}
+	if err = sqlRows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to upsert goods in database: %w", err)
+	}
+
+	results := make([]*good.UpsertResult, len(rows))
+	for i, row := range rows {
+		result, ok := upsertedGoods[row.ExternalKey.String()]
+		if !ok {
+			result = &good.UpsertResult{Err: storage.ErrPostgresGoodRemoved}
+		}
+		results[i] = result
+	}
+
+	gs.publishGoodLogs(ctx, publisher.GoodEventCreated, createdGoods)
+	gs.publishGoodLogs(ctx, publisher.GoodEventUpdated, updatedGoods)
+
+	return results, nil
+}
@@ -0,0 +1,70 @@
+package pggood
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/internal/infra/messagebroker/nats/publisher"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"github.com/vaberof/hezzl-backend/pkg/metrics"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SetPrice sets the price of the good, or removes it if price is nil.
+func (gs *PgGoodStorage) SetPrice(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, price *good.Price, actor domain.Actor, expectedVersion *domain.GoodVersion) (*good.Good, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.SetPrice", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("SetPrice").ObserveDuration()
+
+	var (
+		amount   sql.NullInt64
+		currency sql.NullString
+	)
+	if price != nil {
+		amount = sql.NullInt64{Int64: price.Amount, Valid: true}
+		currency = sql.NullString{String: price.Currency, Valid: true}
+	}
+
+	tx, err := gs.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction while setting price: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err = lockGood(ctx, tx, id, projectId, expectedVersion); err != nil {
+		return nil, fmt.Errorf("failed to set price in database: %w", err)
+	}
+
+	query := `
+		UPDATE goods
+		SET price_amount=$1,
+		    price_currency=$2,
+		    updated_by=$3,
+		    version=version+1
+		WHERE id=$4 AND project_id=$5
+		RETURNING ` + goodColumns
+
+	rows, err := tx.QueryContext(ctx, query, amount, currency, actor, id, projectId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set price in database: %w", err)
+	}
+
+	postgresGoods, err := scanGoods(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set price in database: %w", err)
+	}
+	if len(postgresGoods) == 0 {
+		return nil, fmt.Errorf("failed to set price in database: %w", storage.ErrPostgresGoodNotFound)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction while setting price: %w", err)
+	}
+
+	gs.publishGoodLog(ctx, publisher.GoodEventRepriced, postgresGoods[0], postgresGoods[0].CreatedAt)
+
+	return toDomainGood(postgresGoods[0]), nil
+}
@@ -0,0 +1,158 @@
+package pggood
+
+import (
+	"context"
+	"fmt"
+	"github.com/lib/pq"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"github.com/vaberof/hezzl-backend/pkg/metrics"
+	"go.opentelemetry.io/otel/trace"
+	"html"
+	"strings"
+	"unicode"
+)
+
+const (
+	searchHighlightOptions        = "StartSel=<mark>, StopSel=</mark>, HighlightAll=true"
+	searchSnippetHighlightOptions = "StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MinWords=5, MaxWords=20"
+)
+
+// Search matches the words of text as prefixes against the full-text search vector of the goods and,
+// to tolerate typos, text as a whole against their names by trigram word similarity.
+// Full-text matches are ranked by ts_rank, fuzzy matches by similarity; the better of both orders the results.
+// Highlights are computed for the returned page only, since ts_headline has to re-parse the text.
+// They are computed over the HTML-escaped name and description, so only the <mark> tags are markup.
+func (gs *PgGoodStorage) Search(ctx context.Context, projectId domain.ProjectId, text string, limit, offset int) ([]*good.SearchResult, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.Search", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("Search").ObserveDuration()
+
+	query := `
+			WITH search AS (
+			    SELECT to_tsquery('simple', $2) AS query
+			)
+			SELECT ` + qualifiedGoodColumns("matched") + `,
+			    matched.rank,
+			    matched.search_vector @@ search.query
+			FROM (
+			    SELECT ` + goodColumns + `,
+			        search_vector,
+			        GREATEST(ts_rank(search_vector, search.query), word_similarity($3, name)) AS rank
+			    FROM goods, search
+			    WHERE project_id = $1
+			      AND NOT removed
+			      AND (search_vector @@ search.query OR $3 <% name)
+			    ORDER BY rank DESC, id
+			    LIMIT $4 OFFSET $5
+			) matched, search
+			ORDER BY matched.rank DESC, matched.id
+	`
+
+	tsQuery := prefixTsQuery(text)
+
+	rows, err := gs.db.QueryContext(ctx, query, projectId, tsQuery, text, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search goods: %w", err)
+	}
+	defer rows.Close()
+
+	var searchResults, fullTextResults []*good.SearchResult
+	var names, descriptions []string
+
+	for rows.Next() {
+		var postgresGood Good
+		var searchResult good.SearchResult
+		var fullTextMatch bool
+
+		err = rows.Scan(append(goodScanDest(&postgresGood), &searchResult.Rank, &fullTextMatch)...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan while searching goods: %w", err)
+		}
+
+		searchResult.Good = toDomainGood(&postgresGood)
+		searchResults = append(searchResults, &searchResult)
+
+		if fullTextMatch {
+			fullTextResults = append(fullTextResults, &searchResult)
+			names = append(names, html.EscapeString(postgresGood.Name))
+			descriptions = append(descriptions, html.EscapeString(postgresGood.Description.String))
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to search goods: %w", err)
+	}
+
+	if len(fullTextResults) == 0 {
+		return searchResults, nil
+	}
+
+	nameHighlights, descriptionHighlights, err := gs.highlight(ctx, tsQuery, names, descriptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search goods: %w", err)
+	}
+
+	for i, searchResult := range fullTextResults {
+		searchResult.NameHighlight = nameHighlights[i]
+		searchResult.DescriptionHighlight = descriptionHighlights[i]
+	}
+
+	return searchResults, nil
+}
+
+// highlight wraps the words of the names and the descriptions that match tsQuery in <mark> tags.
+// The texts must already be HTML-escaped; ts_headline keeps their entities intact.
+func (gs *PgGoodStorage) highlight(ctx context.Context, tsQuery string, names, descriptions []string) ([]string, []string, error) {
+	query := `
+			WITH search AS (
+			    SELECT to_tsquery('simple', $1) AS query
+			)
+			SELECT
+			    ts_headline('simple', texts.name, search.query, '` + searchHighlightOptions + `'),
+			    ts_headline('simple', texts.description, search.query, '` + searchSnippetHighlightOptions + `')
+			FROM unnest($2::text[], $3::text[]) WITH ORDINALITY AS texts(name, description, position), search
+			ORDER BY texts.position
+	`
+
+	rows, err := gs.db.QueryContext(ctx, query, tsQuery, pq.Array(names), pq.Array(descriptions))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to highlight search results: %w", err)
+	}
+	defer rows.Close()
+
+	nameHighlights := make([]string, 0, len(names))
+	descriptionHighlights := make([]string, 0, len(descriptions))
+
+	for rows.Next() {
+		var nameHighlight, descriptionHighlight string
+
+		if err = rows.Scan(&nameHighlight, &descriptionHighlight); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan while highlighting search results: %w", err)
+		}
+
+		nameHighlights = append(nameHighlights, nameHighlight)
+		descriptionHighlights = append(descriptionHighlights, descriptionHighlight)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to highlight search results: %w", err)
+	}
+
+	return nameHighlights, descriptionHighlights, nil
+}
+
+// prefixTsQuery builds a tsquery matching all words of text as prefixes, e.g. "red sho" becomes "red:* & sho:*".
+// Only letters and digits are kept, so the user input cannot inject tsquery operators.
+func prefixTsQuery(text string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for i := range words {
+		words[i] += ":*"
+	}
+
+	return strings.Join(words, " & ")
+}
@@ -0,0 +1,26 @@
+package pggood
+
+import "testing"
+
+func TestPrefixTsQuery(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"red sho", "red:* & sho:*"},
+		{"  red   shoes ", "red:* & shoes:*"},
+		{"Кроссовки 42", "Кроссовки:* & 42:*"},
+		{"red & !blue | (green):*", "red:* & blue:* & green:*"},
+		{"o'reilly", "o:* & reilly:*"},
+		{"<-> '' \\", ""},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			if got := prefixTsQuery(test.text); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
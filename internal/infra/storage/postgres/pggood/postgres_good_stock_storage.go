@@ -0,0 +1,218 @@
+package pggood
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/internal/infra/messagebroker/nats/publisher"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"github.com/vaberof/hezzl-backend/pkg/metrics"
+	"go.opentelemetry.io/otel/trace"
+	"log/slog"
+)
+
+const checkViolation = "23514"
+
+const stockMovementColumns = `
+			    id,
+			    good_id,
+			    project_id,
+			    kind,
+			    quantity,
+			    stock,
+			    reserved,
+			    reason,
+			    created_at,
+			    created_by
+`
+
+// MoveStock applies the movement to the stock of a good that is not removed and records it in the ledger.
+// The constraints of the goods table keep the reserved stock between zero and the stock.
+func (gs *PgGoodStorage) MoveStock(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, kind good.StockMovementKind, quantity int64, reason string, actor domain.Actor) (*good.StockMovement, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.MoveStock", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("MoveStock").ObserveDuration()
+
+	var stockDelta, reservedDelta int64
+
+	switch kind {
+	case good.StockMovementReserve:
+		reservedDelta = quantity
+	case good.StockMovementRelease:
+		reservedDelta = -quantity
+	case good.StockMovementAdjust:
+		stockDelta = quantity
+	default:
+		return nil, fmt.Errorf("unknown stock movement kind %q", kind)
+	}
+
+	tx, err := gs.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction while moving stock: %w", err)
+	}
+	defer tx.Rollback()
+
+	var removed bool
+
+	err = tx.QueryRowContext(ctx, "SELECT removed FROM goods WHERE id=$1 AND project_id=$2 FOR UPDATE", id, projectId).Scan(&removed)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to move stock: %w", storage.ErrPostgresGoodNotFound)
+		}
+		return nil, fmt.Errorf("failed to move stock: %w", err)
+	}
+	if removed {
+		return nil, fmt.Errorf("failed to move stock: %w", storage.ErrPostgresGoodRemoved)
+	}
+
+	query := `
+		UPDATE goods
+		SET stock=stock+$1,
+		    reserved=reserved+$2,
+		    updated_by=$3,
+		    version=version+1
+		WHERE id=$4 AND project_id=$5
+		RETURNING stock, reserved
+	`
+
+	var stock, reserved int64
+
+	err = tx.QueryRowContext(ctx, query, stockDelta, reservedDelta, actor, id, projectId).Scan(&stock, &reserved)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == checkViolation {
+			return nil, fmt.Errorf("failed to move stock: %w", storage.ErrPostgresInsufficientStock)
+		}
+		return nil, fmt.Errorf("failed to move stock: %w", err)
+	}
+
+	query = `
+			INSERT INTO stock_movements(
+			                            good_id,
+			                            project_id,
+			                            kind,
+			                            quantity,
+			                            stock,
+			                            reserved,
+			                            reason,
+			                            created_by
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING ` + stockMovementColumns
+
+	stockMovement, err := scanStockMovement(tx.QueryRowContext(ctx, query, id, projectId, string(kind), quantity, stock, reserved, sql.NullString{String: reason, Valid: reason != ""}, actor))
+	if err != nil {
+		return nil, fmt.Errorf("failed to record stock movement in database: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction while moving stock: %w", err)
+	}
+
+	gs.publishStockMovement(ctx, stockMovement)
+
+	return stockMovement, nil
+}
+
+// ListStockMovements lists the stock movements of the good, newest first.
+func (gs *PgGoodStorage) ListStockMovements(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, limit, offset int) ([]*good.StockMovement, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.ListStockMovements", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("ListStockMovements").ObserveDuration()
+
+	var exists bool
+
+	err := gs.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM goods WHERE id=$1 AND project_id=$2)", id, projectId).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stock movements: %w", err)
+	}
+	if !exists {
+		return nil, storage.ErrPostgresGoodNotFound
+	}
+
+	query := `
+			SELECT ` + stockMovementColumns + `
+			FROM stock_movements
+			WHERE good_id=$1 AND project_id=$2
+			ORDER BY id DESC
+			LIMIT $3 OFFSET $4
+	`
+
+	rows, err := gs.db.QueryContext(ctx, query, id, projectId, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stock movements: %w", err)
+	}
+	defer rows.Close()
+
+	stockMovements := make([]*good.StockMovement, 0)
+
+	for rows.Next() {
+		stockMovement, err := scanStockMovement(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan while listing stock movements: %w", err)
+		}
+		stockMovements = append(stockMovements, stockMovement)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list stock movements: %w", err)
+	}
+
+	return stockMovements, nil
+}
+
+func (gs *PgGoodStorage) publishStockMovement(ctx context.Context, stockMovement *good.StockMovement) {
+	err := gs.goodLogPublisher.PublishStockMovement(ctx, &publisher.StockMovement{
+		Id:        stockMovement.Id.Int64(),
+		GoodId:    stockMovement.GoodId.Int64(),
+		ProjectId: stockMovement.ProjectId.Int64(),
+		Kind:      string(stockMovement.Kind),
+		Quantity:  stockMovement.Quantity,
+		Stock:     stockMovement.Stock,
+		Reserved:  stockMovement.Reserved,
+		Reason:    stockMovement.Reason,
+		Actor:     stockMovement.CreatedBy.String(),
+		EventTime: stockMovement.CreatedAt,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to publish stock movement", "goodId", stockMovement.GoodId.Int64(), "stockMovementId", stockMovement.Id.Int64(), "error", err)
+	}
+}
+
+func scanStockMovement(row interface{ Scan(dest ...any) error }) (*good.StockMovement, error) {
+	var (
+		stockMovement good.StockMovement
+		kind          string
+		reason        sql.NullString
+		createdAt     sql.NullTime
+		createdBy     sql.NullString
+	)
+
+	err := row.Scan(
+		&stockMovement.Id,
+		&stockMovement.GoodId,
+		&stockMovement.ProjectId,
+		&kind,
+		&stockMovement.Quantity,
+		&stockMovement.Stock,
+		&stockMovement.Reserved,
+		&reason,
+		&createdAt,
+		&createdBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	stockMovement.Kind = good.StockMovementKind(kind)
+	stockMovement.Reason = reason.String
+	stockMovement.CreatedAt = createdAt.Time
+	stockMovement.CreatedBy = domain.Actor(createdBy.String)
+
+	return &stockMovement, nil
+}
@@ -1,6 +1,7 @@
 package pggood
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -9,12 +10,25 @@ import (
 	"github.com/vaberof/hezzl-backend/internal/infra/messagebroker/nats/publisher"
 	"github.com/vaberof/hezzl-backend/internal/infra/storage"
 	"github.com/vaberof/hezzl-backend/pkg/domain"
-	"log"
+	"github.com/vaberof/hezzl-backend/pkg/metrics"
+	"github.com/vaberof/hezzl-backend/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+var (
+	tracer            = tracing.Tracer("github.com/vaberof/hezzl-backend/internal/infra/storage/postgres/pggood")
+	dbSystemAttribute = attribute.String("db.system", "postgresql")
 )
 
 type PgGoodStorage struct {
 	db               *sqlx.DB
 	goodLogPublisher publisher.Publisher
+
+	backgroundPublishers sync.WaitGroup
 }
 
 func NewPgGoodStorage(db *sqlx.DB, goodLogPublisher publisher.Publisher) *PgGoodStorage {
@@ -24,60 +38,56 @@ func NewPgGoodStorage(db *sqlx.DB, goodLogPublisher publisher.Publisher) *PgGood
 	}
 }
 
-func (gs *PgGoodStorage) Create(projectId domain.ProjectId, name domain.GoodName) (*good.Good, error) {
+func (gs *PgGoodStorage) Create(ctx context.Context, projectId domain.ProjectId, name domain.GoodName, attributes domain.GoodAttributes, actor domain.Actor) (*good.Good, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.Create", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("Create").ObserveDuration()
+
+	attributesJSON, err := attributesParam(attributes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create good in database: %w", err)
+	}
+
 	var postgresGood Good
 	query := `
 			INSERT INTO goods(
 			                  project_id,
-			                  name
-			) VALUES ($1, $2)
-			RETURNING 
-			    id, 
-			    project_id,
-			    name,
-			    description,
-			    priority,
-			    removed,
-			    created_at	    
-	`
-	row := gs.db.QueryRow(query, projectId, name)
-	if err := row.Scan(
-		&postgresGood.Id,
-		&postgresGood.ProjectId,
-		&postgresGood.Name,
-		&postgresGood.Description,
-		&postgresGood.Priority,
-		&postgresGood.Removed,
-		&postgresGood.CreatedAt,
-	); err != nil {
+			                  name,
+			                  created_by,
+			                  updated_by,
+			                  attributes
+			) VALUES ($1, $2, $3, $3, COALESCE($4::jsonb, '{}'))
+			RETURNING ` + goodColumns
+	row := gs.db.QueryRowContext(ctx, query, projectId, name, actor, attributesJSON)
+	if err = row.Scan(goodScanDest(&postgresGood)...); err != nil {
 		return nil, fmt.Errorf("failed to create good in database: %w", err)
 	}
 
-	if err := gs.goodLogPublisher.PublishGoodLog(
-		postgresGood.Id,
-		postgresGood.ProjectId,
-		postgresGood.Name,
-		postgresGood.Description.String,
-		postgresGood.Priority,
-		postgresGood.Removed,
-		postgresGood.CreatedAt,
-	); err != nil {
-		log.Println("Failed to publish good log:", err)
-	}
+	gs.publishGoodLog(ctx, publisher.GoodEventCreated, &postgresGood, postgresGood.CreatedAt)
 
 	return toDomainGood(&postgresGood), nil
 }
 
-func (gs *PgGoodStorage) Update(id domain.GoodId, projectId domain.ProjectId, name domain.GoodName, description *domain.GoodDescription) (*good.Good, error) {
-	tx, err := gs.db.Begin()
+func (gs *PgGoodStorage) Update(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, name domain.GoodName, description *domain.GoodDescription, attributes domain.GoodAttributes, actor domain.Actor, expectedVersion *domain.GoodVersion) (*good.Good, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.Update", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("Update").ObserveDuration()
+
+	attributesJSON, err := attributesParam(attributes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update good in database: %w", err)
+	}
+
+	tx, err := gs.db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to start transaction while updating good: %w", err)
 	}
 	defer tx.Rollback()
 
-	_, err = tx.Exec("LOCK TABLE goods IN SHARE ROW EXCLUSIVE MODE")
-	if err != nil {
-		return nil, fmt.Errorf("failed to lock table while updating good: %w", err)
+	if err = lockGood(ctx, tx, id, projectId, expectedVersion); err != nil {
+		return nil, fmt.Errorf("failed to update good in database: %w", err)
 	}
 
 	var postgresGood Good
@@ -85,28 +95,15 @@ func (gs *PgGoodStorage) Update(id domain.GoodId, projectId domain.ProjectId, na
 	query := `
 		UPDATE goods 
 		SET name=$1, 
-		    description=COALESCE($2, description)
-		WHERE id=$3 AND project_id=$4
-		RETURNING 
-			    id, 
-			    project_id,
-			    name,
-			    description,
-			    priority,
-			    removed,
-			    created_at
-	`
-
-	row := tx.QueryRow(query, name, description, id, projectId)
-	if err = row.Scan(
-		&postgresGood.Id,
-		&postgresGood.ProjectId,
-		&postgresGood.Name,
-		&postgresGood.Description,
-		&postgresGood.Priority,
-		&postgresGood.Removed,
-		&postgresGood.CreatedAt,
-	); err != nil {
+		    description=COALESCE($2, description),
+		    attributes=COALESCE($6::jsonb, attributes),
+		    updated_by=$3,
+		    version=version+1
+		WHERE id=$4 AND project_id=$5
+		RETURNING ` + goodColumns
+
+	row := tx.QueryRowContext(ctx, query, name, description, actor, id, projectId, attributesJSON)
+	if err = row.Scan(goodScanDest(&postgresGood)...); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("failed to update good in database: %w", storage.ErrPostgresGoodNotFound)
 		}
@@ -117,58 +114,39 @@ func (gs *PgGoodStorage) Update(id domain.GoodId, projectId domain.ProjectId, na
 		return nil, fmt.Errorf("failed to commit transaction while updating good: %w", err)
 	}
 
-	if err = gs.goodLogPublisher.PublishGoodLog(
-		postgresGood.Id,
-		postgresGood.ProjectId,
-		postgresGood.Name,
-		postgresGood.Description.String,
-		postgresGood.Priority,
-		postgresGood.Removed,
-		postgresGood.CreatedAt,
-	); err != nil {
-		log.Println("Failed to publish good log:", err)
-	}
+	gs.publishGoodLog(ctx, publisher.GoodEventUpdated, &postgresGood, postgresGood.CreatedAt)
 
 	return toDomainGood(&postgresGood), nil
 }
 
-func (gs *PgGoodStorage) Delete(id domain.GoodId, projectId domain.ProjectId) (*good.Good, error) {
-	tx, err := gs.db.Begin()
+func (gs *PgGoodStorage) Delete(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, actor domain.Actor, expectedVersion *domain.GoodVersion) (*good.Good, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.Delete", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("Delete").ObserveDuration()
+
+	tx, err := gs.db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to start transaction while deleting good: %w", err)
 	}
 	defer tx.Rollback()
 
-	_, err = tx.Exec("LOCK TABLE goods IN SHARE ROW EXCLUSIVE MODE")
-	if err != nil {
-		return nil, fmt.Errorf("failed to lock table while deleting good: %w", err)
+	if err = lockGood(ctx, tx, id, projectId, expectedVersion); err != nil {
+		return nil, fmt.Errorf("failed to delete good: %w", err)
 	}
 
 	var postgresGood Good
 
 	query := `
-		UPDATE goods SET removed=TRUE 
-		             WHERE id=$1 AND project_id=$2
-		RETURNING 
-			    id, 
-			    project_id,
-			    name,
-			    description,
-			    priority,
-			    removed,
-			    created_at
-	`
-
-	row := tx.QueryRow(query, id, projectId)
-	err = row.Scan(
-		&postgresGood.Id,
-		&postgresGood.ProjectId,
-		&postgresGood.Name,
-		&postgresGood.Description,
-		&postgresGood.Priority,
-		&postgresGood.Removed,
-		&postgresGood.CreatedAt,
-	)
+		UPDATE goods SET removed=TRUE,
+		                 removed_at=COALESCE(removed_at, CURRENT_TIMESTAMP),
+		                 updated_by=$1,
+		                 version=version+1
+		             WHERE id=$2 AND project_id=$3
+		RETURNING ` + goodColumns
+
+	row := tx.QueryRowContext(ctx, query, actor, id, projectId)
+	err = row.Scan(goodScanDest(&postgresGood)...)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("failed to delete good: %w", storage.ErrPostgresGoodNotFound)
@@ -176,158 +154,380 @@ func (gs *PgGoodStorage) Delete(id domain.GoodId, projectId domain.ProjectId) (*
 		return nil, fmt.Errorf("failed to delete good: %w", err)
 	}
 
+	// Variants are removed along with their parent.
+	postgresVariants, err := removeVariants(ctx, tx, []int64{postgresGood.Id}, actor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete good: %w", err)
+	}
+
 	if err = tx.Commit(); err != nil {
 		return nil, fmt.Errorf("failed to commit transaction while deleting good: %w", err)
 	}
 
-	if err = gs.goodLogPublisher.PublishGoodLog(
-		postgresGood.Id,
-		postgresGood.ProjectId,
-		postgresGood.Name,
-		postgresGood.Description.String,
-		postgresGood.Priority,
-		postgresGood.Removed,
-		postgresGood.CreatedAt,
-	); err != nil {
-		log.Println("Failed to publish good log:", err)
-	}
+	gs.publishGoodLog(ctx, publisher.GoodEventRemoved, &postgresGood, postgresGood.CreatedAt)
+	gs.publishGoodLogs(ctx, publisher.GoodEventRemoved, postgresVariants)
 
-	return toDomainGood(&postgresGood), nil
+	return withVariants(toDomainGood(&postgresGood), postgresVariants), nil
 }
 
-func (gs *PgGoodStorage) List(limit, offset int) ([]*good.Good, error) {
+func (gs *PgGoodStorage) List(ctx context.Context, filter *good.ListFilter, limit, offset int) ([]*good.Good, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.List", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("List").ObserveDuration()
+
 	limitOffsetParams := fmt.Sprintf(" LIMIT %d OFFSET %d ", limit, offset)
 
+	filterCondition, args := listFilterCondition(filter)
+
 	query := `
-			SELECT 
-				id, 
-				project_id,
-				name,
-				description,
-				priority,
-				removed,
-				created_at
+			SELECT ` + goodColumns + `
 			FROM goods
+			` + filterCondition + `
 			ORDER BY id
 			` + limitOffsetParams
 
-	rows, err := gs.db.Query(query)
+	rows, err := gs.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list goods: %w", err)
 	}
-	defer rows.Close()
-
-	var postgresGoods []*Good
-
-	for rows.Next() {
-		var postgresGood Good
 
-		err = rows.Scan(
-			&postgresGood.Id,
-			&postgresGood.ProjectId,
-			&postgresGood.Name,
-			&postgresGood.Description,
-			&postgresGood.Priority,
-			&postgresGood.Removed,
-			&postgresGood.CreatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan while listing goods: %w", err)
-		}
-
-		postgresGoods = append(postgresGoods, &postgresGood)
+	postgresGoods, err := scanGoods(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan while listing goods: %w", err)
 	}
 
 	return toDomainGoods(postgresGoods), nil
 }
 
-func (gs *PgGoodStorage) ChangePriority(id domain.GoodId, projectId domain.ProjectId, newPriority domain.GoodPriority) ([]*good.Good, error) {
-	tx, err := gs.db.Begin()
+func (gs *PgGoodStorage) ChangePriority(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, newPriority domain.GoodPriority, actor domain.Actor, expectedVersion *domain.GoodVersion) ([]*good.Good, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.ChangePriority", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("ChangePriority").ObserveDuration()
+
+	tx, err := gs.db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to start transaction while changing good priorities: %w", err)
 	}
 	defer tx.Rollback()
 
-	_, err = tx.Exec("LOCK TABLE goods IN SHARE ROW EXCLUSIVE MODE")
-	if err != nil {
-		return nil, fmt.Errorf("failed to lock table while changing good priorities: %w", err)
+	if err = lockGood(ctx, tx, id, projectId, expectedVersion); err != nil {
+		return nil, fmt.Errorf("failed to change good priorities: %w", err)
 	}
 
-	query := `
-		UPDATE goods SET priority=$1
-		WHERE (id=$2 AND project_id=$3) OR id>$2
-		RETURNING 
-			    id, 
-			    project_id,
-			    name,
-			    description,
-			    priority,
-			    removed,
-			    created_at
-	`
+	// Variants are prioritized among the variants of their parent, other goods among the goods that are not variants.
+	var parentId sql.NullInt64
 
-	rows, err := tx.Query(query, newPriority, id, projectId)
+	err = tx.QueryRowContext(ctx, "SELECT parent_id FROM goods WHERE id=$1", id).Scan(&parentId)
 	if err != nil {
 		return nil, fmt.Errorf("failed to change good priorities: %w", err)
 	}
-	defer rows.Close()
 
-	var postgresGoods []*Good
+	// The following goods of the project are locked in the same order by every transaction to avoid deadlocks.
+	// Removed goods are read-only and keep their priorities.
+	_, err = tx.ExecContext(ctx, "SELECT id FROM goods WHERE id>$1 AND project_id=$2 AND NOT removed AND parent_id IS NOT DISTINCT FROM $3 ORDER BY id FOR UPDATE", id, projectId, parentId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock goods while changing good priorities: %w", err)
+	}
 
-	for rows.Next() {
-		var postgresGood Good
+	query := `
+		UPDATE goods SET priority=$1,
+		                 updated_by=$2,
+		                 version=version+1
+		WHERE project_id=$4 AND (id=$3 OR (id>$3 AND NOT removed AND parent_id IS NOT DISTINCT FROM $5))
+		RETURNING ` + goodColumns
 
-		err = rows.Scan(
-			&postgresGood.Id,
-			&postgresGood.ProjectId,
-			&postgresGood.Name,
-			&postgresGood.Description,
-			&postgresGood.Priority,
-			&postgresGood.Removed,
-			&postgresGood.CreatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan changing good priorities: %w", err)
-		}
+	rows, err := tx.QueryContext(ctx, query, newPriority, actor, id, projectId, parentId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to change good priorities: %w", err)
+	}
 
-		postgresGoods = append(postgresGoods, &postgresGood)
+	postgresGoods, err := scanGoods(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan changing good priorities: %w", err)
 	}
 
 	if err = tx.Commit(); err != nil {
 		return nil, fmt.Errorf("failed to commit transaction while changing good priorities: %w", err)
 	}
 
+	publishCtx := context.WithoutCancel(ctx)
+
+	gs.backgroundPublishers.Add(1)
 	go func() {
+		defer gs.backgroundPublishers.Done()
+
 		for _, postgresGood := range postgresGoods {
-			if err = gs.goodLogPublisher.PublishGoodLog(
-				postgresGood.Id,
-				postgresGood.ProjectId,
-				postgresGood.Name,
-				postgresGood.Description.String,
-				postgresGood.Priority,
-				postgresGood.Removed,
-				postgresGood.CreatedAt,
-			); err != nil {
-				log.Println("Failed to publish good log:", err)
-			}
+			gs.publishGoodLog(publishCtx, publisher.GoodEventReprioritized, postgresGood, postgresGood.CreatedAt)
 		}
 	}()
 
 	return toDomainGoods(postgresGoods), nil
 }
 
-func (gs *PgGoodStorage) IsExists(id domain.GoodId, projectId domain.ProjectId) (bool, error) {
+func (gs *PgGoodStorage) Get(ctx context.Context, id domain.GoodId, projectId domain.ProjectId) (*good.Good, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.Get", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("Get").ObserveDuration()
+
+	var postgresGood Good
+
 	query := `
-			SELECT id FROM goods
+			SELECT ` + goodColumns + `
+			FROM goods
 			WHERE id=$1 AND project_id=$2
 	`
-	var goodId int64
-	err := gs.db.QueryRow(query, id, projectId).Scan(&goodId)
+
+	row := gs.db.QueryRowContext(ctx, query, id, projectId)
+	if err := row.Scan(goodScanDest(&postgresGood)...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to get good: %w", storage.ErrPostgresGoodNotFound)
+		}
+		return nil, fmt.Errorf("failed to get good: %w", err)
+	}
+
+	return toDomainGood(&postgresGood), nil
+}
+
+func (gs *PgGoodStorage) Restore(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, actor domain.Actor, expectedVersion *domain.GoodVersion) (*good.Good, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.Restore", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("Restore").ObserveDuration()
+
+	tx, err := gs.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction while restoring good: %w", err)
+	}
+	defer tx.Rollback()
+
+	// A variant cannot be restored while its parent is removed.
+	parent, err := shareParent(ctx, tx, id, projectId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore good: %w", err)
+	}
+	if parent != nil && parent.Removed {
+		return nil, fmt.Errorf("failed to restore good: %w", storage.ErrPostgresParentRemoved)
+	}
+
+	if err = lockGood(ctx, tx, id, projectId, expectedVersion); err != nil {
+		return nil, fmt.Errorf("failed to restore good: %w", err)
+	}
+
+	var removedAt sql.NullTime
+
+	err = tx.QueryRowContext(ctx, "SELECT removed_at FROM goods WHERE id=$1", id).Scan(&removedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore good: %w", err)
+	}
+
+	var postgresGood Good
+
+	query := `
+		UPDATE goods SET removed=FALSE,
+		                 removed_at=NULL,
+		                 updated_by=$1,
+		                 version=version+1
+		             WHERE id=$2 AND project_id=$3 AND removed
+		RETURNING ` + goodColumns
+
+	row := tx.QueryRowContext(ctx, query, actor, id, projectId)
+	err = row.Scan(goodScanDest(&postgresGood)...)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return false, nil
+			return nil, fmt.Errorf("failed to restore good: %w", storage.ErrPostgresGoodNotRemoved)
+		}
+		return nil, fmt.Errorf("failed to restore good: %w", err)
+	}
+
+	var postgresVariants []*Good
+
+	if !postgresGood.ParentId.Valid && removedAt.Valid {
+		postgresVariants, err = restoreVariants(ctx, tx, id, removedAt.Time, actor)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore good: %w", err)
 		}
-		return false, fmt.Errorf("failed to check whether the good exists or not: %w", err)
 	}
-	return true, nil
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction while restoring good: %w", err)
+	}
+
+	restoredAt := time.Now()
+
+	gs.publishGoodLog(ctx, publisher.GoodEventRestored, &postgresGood, restoredAt)
+	for _, postgresVariant := range postgresVariants {
+		gs.publishGoodLog(ctx, publisher.GoodEventRestored, postgresVariant, restoredAt)
+	}
+
+	return withVariants(toDomainGood(&postgresGood), postgresVariants), nil
+}
+
+func (gs *PgGoodStorage) Purge(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, actor domain.Actor, expectedVersion *domain.GoodVersion) (*good.Good, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.Purge", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("Purge").ObserveDuration()
+
+	tx, err := gs.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction while purging good: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err = lockGood(ctx, tx, id, projectId, expectedVersion); err != nil {
+		return nil, fmt.Errorf("failed to purge good: %w", err)
+	}
+
+	// Variants are purged along with their parent.
+	query := `
+		DELETE FROM goods
+		WHERE (id=$1 AND project_id=$2) OR parent_id=$1
+		RETURNING ` + goodColumns
+
+	rows, err := tx.QueryContext(ctx, query, id, projectId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge good: %w", err)
+	}
+
+	postgresGoods, err := scanGoods(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge good: %w", err)
+	}
+
+	var (
+		postgresGood     *Good
+		postgresVariants []*Good
+	)
+	for _, purgedGood := range postgresGoods {
+		if purgedGood.Id == id.Int64() {
+			postgresGood = purgedGood
+		} else {
+			postgresVariants = append(postgresVariants, purgedGood)
+		}
+	}
+	if postgresGood == nil {
+		return nil, fmt.Errorf("failed to purge good: %w", storage.ErrPostgresGoodNotFound)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction while purging good: %w", err)
+	}
+
+	purgedAt := time.Now()
+	for _, purgedGood := range postgresGoods {
+		purgedGood.UpdatedBy = sql.NullString{String: actor.String(), Valid: true}
+
+		gs.publishGoodLog(ctx, publisher.GoodEventPurged, purgedGood, purgedAt)
+	}
+
+	return withVariants(toDomainGood(postgresGood), postgresVariants), nil
+}
+
+func (gs *PgGoodStorage) PurgeRemoved(ctx context.Context, retention time.Duration, limit int, actor domain.Actor) ([]*good.Good, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.PurgeRemoved", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("PurgeRemoved").ObserveDuration()
+
+	// The variants of purged goods are purged along with them, even if they were removed later.
+	query := `
+		WITH expired AS (
+		    SELECT id FROM goods
+		    WHERE removed AND removed_at < CURRENT_TIMESTAMP - make_interval(secs => $1)
+		    ORDER BY id
+		    LIMIT $2
+		    FOR UPDATE SKIP LOCKED
+		)
+		DELETE FROM goods
+		WHERE id IN (SELECT id FROM expired) OR parent_id IN (SELECT id FROM expired)
+		RETURNING ` + goodColumns
+
+	rows, err := gs.db.QueryContext(ctx, query, retention.Seconds(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge removed goods: %w", err)
+	}
+
+	postgresGoods, err := scanGoods(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan while purging removed goods: %w", err)
+	}
+
+	purgedAt := time.Now()
+	for _, postgresGood := range postgresGoods {
+		postgresGood.UpdatedBy = sql.NullString{String: actor.String(), Valid: true}
+
+		gs.publishGoodLog(ctx, publisher.GoodEventPurged, postgresGood, purgedAt)
+	}
+
+	return toDomainGoods(postgresGoods), nil
+}
+
+// Wait blocks until all good logs published in the background are sent or ctx is done.
+func (gs *PgGoodStorage) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		gs.backgroundPublishers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("background good log publishers did not finish: %w", ctx.Err())
+	}
+}
+
+func (gs *PgGoodStorage) publishGoodLog(ctx context.Context, event string, postgresGood *Good, eventTime time.Time) {
+	if err := gs.goodLogPublisher.PublishGoodLog(ctx, newGoodLog(event, postgresGood, postgresGood.UpdatedBy.String, eventTime)); err != nil {
+		slog.ErrorContext(ctx, "failed to publish good log", "goodId", postgresGood.Id, "event", event, "error", err)
+	}
+}
+
+// lockGood locks the row of the good until the end of tx and checks its version if expectedVersion is set.
+func lockGood(ctx context.Context, tx *sql.Tx, id domain.GoodId, projectId domain.ProjectId, expectedVersion *domain.GoodVersion) error {
+	var version int64
+
+	err := tx.QueryRowContext(ctx, "SELECT version FROM goods WHERE id=$1 AND project_id=$2 FOR UPDATE", id, projectId).Scan(&version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return storage.ErrPostgresGoodNotFound
+		}
+		return fmt.Errorf("failed to lock good: %w", err)
+	}
+
+	if expectedVersion != nil && expectedVersion.Int64() != version {
+		return storage.ErrPostgresGoodVersionMismatch
+	}
+
+	return nil
+}
+
+// newGoodLog describes the state of the good after the event.
+func newGoodLog(event string, postgresGood *Good, actor string, eventTime time.Time) *publisher.GoodLog {
+	goodLog := &publisher.GoodLog{
+		Event:       event,
+		Id:          postgresGood.Id,
+		ProjectId:   postgresGood.ProjectId,
+		Name:        postgresGood.Name,
+		Description: postgresGood.Description.String,
+		Priority:    postgresGood.Priority,
+		Removed:     postgresGood.Removed,
+		Actor:       actor,
+		EventTime:   eventTime,
+		Attributes:  postgresGood.Attributes,
+	}
+
+	if postgresGood.PriceAmount.Valid {
+		goodLog.PriceAmount = &postgresGood.PriceAmount.Int64
+		goodLog.PriceCurrency = postgresGood.PriceCurrency.String
+	}
+	if postgresGood.ParentId.Valid {
+		goodLog.ParentId = &postgresGood.ParentId.Int64
+	}
+
+	return goodLog
 }
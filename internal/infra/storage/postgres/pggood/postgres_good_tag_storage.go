@@ -0,0 +1,292 @@
+package pggood
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/lib/pq"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/internal/infra/messagebroker/nats/publisher"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"github.com/vaberof/hezzl-backend/pkg/metrics"
+	"go.opentelemetry.io/otel/trace"
+	"log/slog"
+	"time"
+)
+
+func (gs *PgGoodStorage) AddTags(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, tags []domain.TagName, actor domain.Actor) ([]domain.TagName, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.AddTags", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("AddTags").ObserveDuration()
+
+	tx, err := gs.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction while adding good tags: %w", err)
+	}
+	defer tx.Rollback()
+
+	postgresGood, err := shareActiveGood(ctx, tx, id, projectId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add good tags: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+			INSERT INTO tags (project_id, name)
+			SELECT $1, unnest($2::text[])
+			ON CONFLICT (project_id, name) DO NOTHING
+	`, projectId, pq.Array(toStrings(tags)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tags: %w", err)
+	}
+
+	query := `
+			WITH added AS (
+			    INSERT INTO good_tags (good_id, tag_id, created_by)
+			    SELECT $1, id, $4
+			    FROM tags
+			    WHERE project_id = $2 AND name = ANY($3)
+			    ON CONFLICT (good_id, tag_id) DO NOTHING
+			    RETURNING tag_id
+			)
+			SELECT tags.name
+			FROM added
+			JOIN tags ON tags.id = added.tag_id
+			ORDER BY tags.name
+	`
+
+	addedTags, err := queryTagNames(ctx, tx, query, id, projectId, pq.Array(toStrings(tags)), actor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add good tags: %w", err)
+	}
+
+	goodTags, err := getGoodTags(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction while adding good tags: %w", err)
+	}
+
+	gs.publishTagGoodLog(ctx, publisher.GoodEventTagged, postgresGood, addedTags, actor)
+
+	return goodTags, nil
+}
+
+func (gs *PgGoodStorage) RemoveTags(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, tags []domain.TagName, actor domain.Actor) ([]domain.TagName, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.RemoveTags", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("RemoveTags").ObserveDuration()
+
+	tx, err := gs.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction while removing good tags: %w", err)
+	}
+	defer tx.Rollback()
+
+	postgresGood, err := shareActiveGood(ctx, tx, id, projectId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove good tags: %w", err)
+	}
+
+	query := `
+			DELETE FROM good_tags
+			USING tags
+			WHERE good_tags.tag_id = tags.id
+			  AND good_tags.good_id = $1
+			  AND tags.project_id = $2
+			  AND tags.name = ANY($3)
+			RETURNING tags.name
+	`
+
+	removedTags, err := queryTagNames(ctx, tx, query, id, projectId, pq.Array(toStrings(tags)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove good tags: %w", err)
+	}
+
+	goodTags, err := getGoodTags(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction while removing good tags: %w", err)
+	}
+
+	gs.publishTagGoodLog(ctx, publisher.GoodEventUntagged, postgresGood, removedTags, actor)
+
+	return goodTags, nil
+}
+
+func (gs *PgGoodStorage) GetTags(ctx context.Context, id domain.GoodId, projectId domain.ProjectId) ([]domain.TagName, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.GetTags", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("GetTags").ObserveDuration()
+
+	query := `
+			SELECT tags.name
+			FROM goods
+			LEFT JOIN good_tags ON good_tags.good_id = goods.id
+			LEFT JOIN tags ON tags.id = good_tags.tag_id
+			WHERE goods.id = $1 AND goods.project_id = $2
+			ORDER BY tags.name
+	`
+
+	rows, err := gs.db.QueryContext(ctx, query, id, projectId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get good tags: %w", err)
+	}
+	defer rows.Close()
+
+	found := false
+	goodTags := make([]domain.TagName, 0)
+
+	for rows.Next() {
+		found = true
+
+		var tag sql.NullString
+		if err = rows.Scan(&tag); err != nil {
+			return nil, fmt.Errorf("failed to scan while getting good tags: %w", err)
+		}
+
+		if tag.Valid {
+			goodTags = append(goodTags, domain.TagName(tag.String))
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get good tags: %w", err)
+	}
+
+	if !found {
+		return nil, storage.ErrPostgresGoodNotFound
+	}
+
+	return goodTags, nil
+}
+
+// ListTags lists the tags of the project that are attached to at least one good, by name.
+func (gs *PgGoodStorage) ListTags(ctx context.Context, projectId domain.ProjectId) ([]*good.Tag, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.ListTags", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("ListTags").ObserveDuration()
+
+	query := `
+			SELECT tags.name, COUNT(*) FILTER (WHERE NOT goods.removed)
+			FROM tags
+			JOIN good_tags ON good_tags.tag_id = tags.id
+			JOIN goods ON goods.id = good_tags.good_id
+			WHERE tags.project_id = $1
+			GROUP BY tags.name
+			ORDER BY tags.name
+	`
+
+	rows, err := gs.db.QueryContext(ctx, query, projectId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := make([]*good.Tag, 0)
+
+	for rows.Next() {
+		var tag good.Tag
+		if err = rows.Scan(&tag.Name, &tag.GoodsCount); err != nil {
+			return nil, fmt.Errorf("failed to scan while listing tags: %w", err)
+		}
+		tags = append(tags, &tag)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// shareActiveGood locks the good against concurrent updates and removal until the end of tx.
+// A removed good is read-only, so ErrPostgresGoodRemoved is returned for it.
+func shareActiveGood(ctx context.Context, tx *sql.Tx, id domain.GoodId, projectId domain.ProjectId) (*Good, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT "+goodColumns+" FROM goods WHERE id=$1 AND project_id=$2 FOR SHARE", id, projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	postgresGoods, err := scanGoods(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(postgresGoods) == 0 {
+		return nil, storage.ErrPostgresGoodNotFound
+	}
+	if postgresGoods[0].Removed {
+		return nil, storage.ErrPostgresGoodRemoved
+	}
+
+	return postgresGoods[0], nil
+}
+
+func getGoodTags(ctx context.Context, tx *sql.Tx, id domain.GoodId) ([]domain.TagName, error) {
+	query := `
+			SELECT tags.name
+			FROM good_tags
+			JOIN tags ON tags.id = good_tags.tag_id
+			WHERE good_tags.good_id = $1
+			ORDER BY tags.name
+	`
+
+	goodTags, err := queryTagNames(ctx, tx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get good tags: %w", err)
+	}
+
+	return goodTags, nil
+}
+
+func queryTagNames(ctx context.Context, tx *sql.Tx, query string, args ...any) ([]domain.TagName, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := make([]domain.TagName, 0)
+
+	for rows.Next() {
+		var tag domain.TagName
+		if err = rows.Scan(&tag); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+// publishTagGoodLog publishes the tags that were actually added or removed; nothing is published if the tags did not change.
+func (gs *PgGoodStorage) publishTagGoodLog(ctx context.Context, event string, postgresGood *Good, tags []domain.TagName, actor domain.Actor) {
+	if len(tags) == 0 {
+		return
+	}
+
+	goodLog := newGoodLog(event, postgresGood, actor.String(), time.Now())
+	goodLog.Tags = toStrings(tags)
+
+	if err := gs.goodLogPublisher.PublishGoodLog(ctx, goodLog); err != nil {
+		slog.ErrorContext(ctx, "failed to publish good log", "goodId", postgresGood.Id, "event", event, "error", err)
+	}
+}
+
+func toStrings(tags []domain.TagName) []string {
+	strs := make([]string, len(tags))
+	for i := range tags {
+		strs[i] = tags[i].String()
+	}
+	return strs
+}
@@ -0,0 +1,342 @@
+package pggood
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/internal/infra/messagebroker/nats/publisher"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"github.com/vaberof/hezzl-backend/pkg/metrics"
+	"go.opentelemetry.io/otel/trace"
+	"log/slog"
+	"time"
+)
+
+const uniqueViolation = "23505"
+
+// Move reassigns the good and all of its variants to the target project. The good is prioritized after
+// the other goods of the target project, the variants keep their priorities among each other.
+// Translations and the stock ledger follow the good; tags and attachments follow it only if options ask for them,
+// otherwise they are dropped, and the blobs of the dropped attachments are queued for deletion.
+func (gs *PgGoodStorage) Move(ctx context.Context, id domain.GoodId, projectId, targetProjectId domain.ProjectId, options *good.TransferOptions, actor domain.Actor, expectedVersion *domain.GoodVersion) (*good.Good, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.Move", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("Move").ObserveDuration()
+
+	tx, err := gs.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction while moving good: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err = lockGood(ctx, tx, id, projectId, expectedVersion); err != nil {
+		return nil, fmt.Errorf("failed to move good: %w", err)
+	}
+
+	// The parent is locked first, so the variants are locked in the same order as by the other writes.
+	query := "SELECT " + goodColumns + " FROM goods WHERE id=$1 OR parent_id=$1 ORDER BY parent_id NULLS FIRST, priority, id FOR UPDATE"
+
+	rows, err := tx.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock variants while moving good: %w", err)
+	}
+
+	sourceGoods, err := scanGoods(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock variants while moving good: %w", err)
+	}
+	if sourceGoods[0].Removed {
+		return nil, fmt.Errorf("failed to move good: %w", storage.ErrPostgresGoodRemoved)
+	}
+	if sourceGoods[0].ParentId.Valid {
+		return nil, fmt.Errorf("failed to move good: %w", storage.ErrPostgresVariantTransfer)
+	}
+
+	if err = lockProject(ctx, tx, targetProjectId); err != nil {
+		return nil, fmt.Errorf("failed to move good: %w", err)
+	}
+
+	query = `
+		WITH moved AS (
+		    UPDATE goods SET project_id=$2,
+		                     priority=CASE
+		                         WHEN id=$1 THEN (SELECT COALESCE(MAX(priority), 0) + 1 FROM goods WHERE project_id=$2 AND parent_id IS NULL)
+		                         ELSE priority
+		                     END,
+		                     updated_by=$3,
+		                     version=version+1
+		    WHERE id=$1 OR parent_id=$1
+		    RETURNING ` + goodColumns + `
+		)
+		SELECT ` + goodColumns + ` FROM moved ORDER BY parent_id NULLS FIRST, priority, id
+	`
+
+	rows, err = tx.QueryContext(ctx, query, id, targetProjectId, actor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to move good: %w", mapExternalKeyConflict(err))
+	}
+
+	movedGoods, err := scanGoods(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to move good: %w", mapExternalKeyConflict(err))
+	}
+
+	movedIds := make([]int64, len(movedGoods))
+	for i := range movedGoods {
+		movedIds[i] = movedGoods[i].Id
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE stock_movements SET project_id=$2 WHERE good_id = ANY($1)", pq.Array(movedIds), targetProjectId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to move stock movements: %w", err)
+	}
+
+	if options.Tags {
+		if err = createTargetTags(ctx, tx, movedIds, targetProjectId); err != nil {
+			return nil, err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+				UPDATE good_tags SET tag_id = target.id
+				FROM tags AS source, tags AS target
+				WHERE good_tags.good_id = ANY($1)
+				  AND source.id = good_tags.tag_id
+				  AND target.project_id = $2 AND target.name = source.name
+		`, pq.Array(movedIds), targetProjectId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to move good tags: %w", err)
+		}
+	} else {
+		_, err = tx.ExecContext(ctx, "DELETE FROM good_tags WHERE good_id = ANY($1)", pq.Array(movedIds))
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete good tags: %w", err)
+		}
+	}
+
+	if options.Attachments {
+		_, err = tx.ExecContext(ctx, "UPDATE attachments SET project_id=$2 WHERE good_id = ANY($1)", pq.Array(movedIds), targetProjectId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to move attachments: %w", err)
+		}
+	} else {
+		_, err = tx.ExecContext(ctx, "DELETE FROM attachments WHERE good_id = ANY($1)", pq.Array(movedIds))
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete attachments: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction while moving good: %w", err)
+	}
+
+	eventTime := time.Now()
+
+	gs.publishTransferGoodLogs(ctx, publisher.GoodEventMovedOut, sourceGoods, actor, eventTime)
+	gs.publishTransferGoodLogs(ctx, publisher.GoodEventMovedIn, movedGoods, actor, eventTime)
+
+	return withVariants(toDomainGood(movedGoods[0]), movedGoods[1:]), nil
+}
+
+// Copy creates a copy of the good and of its variants that are not removed in the target project.
+// The copy is prioritized after the other goods of the target project, the copied variants keep their order.
+// The copies have no stock and no external key; their translations are copied, as are their tags and
+// attachments if options ask for them. Copied attachments refer to the blobs of the original attachments.
+func (gs *PgGoodStorage) Copy(ctx context.Context, id domain.GoodId, projectId, targetProjectId domain.ProjectId, options *good.TransferOptions, actor domain.Actor) (*good.Good, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.Copy", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("Copy").ObserveDuration()
+
+	tx, err := gs.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction while copying good: %w", err)
+	}
+	defer tx.Rollback()
+
+	parent, err := shareActiveGood(ctx, tx, id, projectId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy good: %w", err)
+	}
+	if parent.ParentId.Valid {
+		return nil, fmt.Errorf("failed to copy good: %w", storage.ErrPostgresVariantTransfer)
+	}
+
+	query := "SELECT " + goodColumns + " FROM goods WHERE parent_id=$1 AND NOT removed ORDER BY priority, id FOR SHARE"
+
+	rows, err := tx.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock variants while copying good: %w", err)
+	}
+
+	sourceVariants, err := scanGoods(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock variants while copying good: %w", err)
+	}
+
+	if err = lockProject(ctx, tx, targetProjectId); err != nil {
+		return nil, fmt.Errorf("failed to copy good: %w", err)
+	}
+
+	// The goods are copied one by one, so that the copied variants are prioritized in the order of the originals.
+	query = `
+			INSERT INTO goods(
+			                  project_id,
+			                  parent_id,
+			                  name,
+			                  description,
+			                  attributes,
+			                  price_amount,
+			                  price_currency,
+			                  created_by,
+			                  updated_by
+			)
+			SELECT $2::int, $3::int, name, description, attributes, price_amount, price_currency, $4::text, $4::text
+			FROM goods
+			WHERE id=$1
+			RETURNING ` + goodColumns
+
+	sourceGoods := append([]*Good{parent}, sourceVariants...)
+	sourceIds := make([]int64, len(sourceGoods))
+	copiedGoods := make([]*Good, len(sourceGoods))
+	copiedIds := make([]int64, len(sourceGoods))
+
+	for i, sourceGood := range sourceGoods {
+		var parentId sql.NullInt64
+		if i > 0 {
+			parentId = sql.NullInt64{Int64: copiedIds[0], Valid: true}
+		}
+
+		rows, err = tx.QueryContext(ctx, query, sourceGood.Id, targetProjectId, parentId, actor)
+		if err != nil {
+			return nil, fmt.Errorf("failed to copy good in database: %w", err)
+		}
+
+		postgresGoods, err := scanGoods(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to copy good in database: %w", err)
+		}
+
+		sourceIds[i] = sourceGood.Id
+		copiedGoods[i] = postgresGoods[0]
+		copiedIds[i] = postgresGoods[0].Id
+	}
+
+	_, err = tx.ExecContext(ctx, `
+			INSERT INTO good_translations(good_id, locale, name, description, updated_by)
+			SELECT copies.copy_id, good_translations.locale, good_translations.name, good_translations.description, $3::text
+			FROM unnest($1::int[], $2::int[]) AS copies(source_id, copy_id)
+			JOIN good_translations ON good_translations.good_id = copies.source_id
+	`, pq.Array(sourceIds), pq.Array(copiedIds), actor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy translations: %w", err)
+	}
+
+	if options.Tags {
+		if err = createTargetTags(ctx, tx, sourceIds, targetProjectId); err != nil {
+			return nil, err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+				INSERT INTO good_tags(good_id, tag_id, created_by)
+				SELECT copies.copy_id, target.id, $4::text
+				FROM unnest($1::int[], $2::int[]) AS copies(source_id, copy_id)
+				JOIN good_tags ON good_tags.good_id = copies.source_id
+				JOIN tags AS source ON source.id = good_tags.tag_id
+				JOIN tags AS target ON target.project_id = $3 AND target.name = source.name
+		`, pq.Array(sourceIds), pq.Array(copiedIds), targetProjectId, actor)
+		if err != nil {
+			return nil, fmt.Errorf("failed to copy good tags: %w", err)
+		}
+	}
+
+	if options.Attachments {
+		// The original attachments cannot be deleted, and their blobs queued for deletion, until the copies refer to them.
+		_, err = tx.ExecContext(ctx, "SELECT id FROM attachments WHERE good_id = ANY($1) ORDER BY id FOR SHARE", pq.Array(sourceIds))
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock attachments while copying good: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+				INSERT INTO attachments(good_id, project_id, name, content_type, size, checksum, storage_key, created_by)
+				SELECT copies.copy_id, $3::int, attachments.name, attachments.content_type, attachments.size, attachments.checksum, attachments.storage_key, $4::text
+				FROM unnest($1::int[], $2::int[]) AS copies(source_id, copy_id)
+				JOIN attachments ON attachments.good_id = copies.source_id
+				ORDER BY attachments.id
+		`, pq.Array(sourceIds), pq.Array(copiedIds), targetProjectId, actor)
+		if err != nil {
+			return nil, fmt.Errorf("failed to copy attachments: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction while copying good: %w", err)
+	}
+
+	eventTime := time.Now()
+
+	gs.publishTransferGoodLogs(ctx, publisher.GoodEventCopiedOut, sourceGoods, actor, eventTime)
+	gs.publishTransferGoodLogs(ctx, publisher.GoodEventCopiedIn, copiedGoods, actor, eventTime)
+
+	return withVariants(toDomainGood(copiedGoods[0]), copiedGoods[1:]), nil
+}
+
+// lockProject locks the project until the end of tx. Transfers into the project wait for each other,
+// so that each of them computes the priority of its good from the goods committed by the previous one.
+// The lock also conflicts with the key share locks taken by inserts of goods into the project and by its deletion.
+func lockProject(ctx context.Context, tx *sql.Tx, projectId domain.ProjectId) error {
+	var id int64
+
+	err := tx.QueryRowContext(ctx, "SELECT id FROM projects WHERE id=$1 FOR UPDATE", projectId).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return storage.ErrPostgresProjectNotFound
+		}
+		return fmt.Errorf("failed to lock project: %w", err)
+	}
+
+	return nil
+}
+
+// createTargetTags creates the tags of the goods in the target project, unless it has tags of the same names.
+func createTargetTags(ctx context.Context, tx *sql.Tx, ids []int64, targetProjectId domain.ProjectId) error {
+	_, err := tx.ExecContext(ctx, `
+			INSERT INTO tags (project_id, name)
+			SELECT DISTINCT $2::int, tags.name
+			FROM good_tags
+			JOIN tags ON tags.id = good_tags.tag_id
+			WHERE good_tags.good_id = ANY($1)
+			ON CONFLICT (project_id, name) DO NOTHING
+	`, pq.Array(ids), targetProjectId)
+	if err != nil {
+		return fmt.Errorf("failed to create tags: %w", err)
+	}
+
+	return nil
+}
+
+// mapExternalKeyConflict reports that a good of the target project already has the external key of a moved good.
+func mapExternalKeyConflict(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return storage.ErrPostgresExternalKeyConflict
+	}
+	return err
+}
+
+// publishTransferGoodLogs logs the goods as of the event in the project they were in at that point,
+// so the source project sees the goods leave and the target project sees them arrive.
+func (gs *PgGoodStorage) publishTransferGoodLogs(ctx context.Context, event string, postgresGoods []*Good, actor domain.Actor, eventTime time.Time) {
+	goodLogs := make([]*publisher.GoodLog, len(postgresGoods))
+	for i, postgresGood := range postgresGoods {
+		goodLogs[i] = newGoodLog(event, postgresGood, actor.String(), eventTime)
+	}
+
+	if err := gs.goodLogPublisher.PublishGoodLogs(ctx, goodLogs); err != nil {
+		slog.ErrorContext(ctx, "failed to publish good logs", "event", event, "count", len(goodLogs), "error", err)
+	}
+}
@@ -0,0 +1,269 @@
+package pggood
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/internal/infra/messagebroker/nats/publisher"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"github.com/vaberof/hezzl-backend/pkg/metrics"
+	"go.opentelemetry.io/otel/trace"
+	"log/slog"
+	"time"
+)
+
+const translationColumns = "good_id, locale, name, description, updated_at, updated_by"
+
+func (gs *PgGoodStorage) GetTranslation(ctx context.Context, id domain.GoodId, locale domain.Locale) (*good.Translation, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.GetTranslation", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("GetTranslation").ObserveDuration()
+
+	query := "SELECT " + translationColumns + " FROM good_translations WHERE good_id=$1 AND locale=$2"
+
+	translation, err := scanTranslation(gs.db.QueryRowContext(ctx, query, id, locale))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to get translation: %w", storage.ErrPostgresTranslationNotFound)
+		}
+		return nil, fmt.Errorf("failed to get translation: %w", err)
+	}
+
+	return translation, nil
+}
+
+// FindTranslations returns the translations of the goods into any of the locales.
+func (gs *PgGoodStorage) FindTranslations(ctx context.Context, ids []domain.GoodId, locales []domain.Locale) ([]*good.Translation, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.FindTranslations", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("FindTranslations").ObserveDuration()
+
+	localeStrs := make([]string, len(locales))
+	for i := range locales {
+		localeStrs[i] = locales[i].String()
+	}
+
+	query := "SELECT " + translationColumns + " FROM good_translations WHERE good_id = ANY($1) AND locale = ANY($2)"
+
+	translations, err := gs.queryTranslations(ctx, query, pq.Array(toInt64s(ids)), pq.Array(localeStrs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find translations: %w", err)
+	}
+
+	return translations, nil
+}
+
+// ListTranslations lists the translations of the good by locale.
+func (gs *PgGoodStorage) ListTranslations(ctx context.Context, id domain.GoodId, projectId domain.ProjectId) ([]*good.Translation, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.ListTranslations", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("ListTranslations").ObserveDuration()
+
+	var exists bool
+
+	err := gs.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM goods WHERE id=$1 AND project_id=$2)", id, projectId).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list translations: %w", err)
+	}
+	if !exists {
+		return nil, storage.ErrPostgresGoodNotFound
+	}
+
+	query := "SELECT " + translationColumns + " FROM good_translations WHERE good_id=$1 ORDER BY locale"
+
+	translations, err := gs.queryTranslations(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list translations: %w", err)
+	}
+
+	return translations, nil
+}
+
+// SetTranslation creates or replaces the translation of the good into translation.Locale.
+// Translations are part of the good's data, so a removed good cannot be translated.
+func (gs *PgGoodStorage) SetTranslation(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, translation *good.Translation, actor domain.Actor) (*good.Translation, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.SetTranslation", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("SetTranslation").ObserveDuration()
+
+	tx, err := gs.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction while setting translation: %w", err)
+	}
+	defer tx.Rollback()
+
+	postgresGood, err := shareActiveGood(ctx, tx, id, projectId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set translation: %w", err)
+	}
+
+	query := `
+			INSERT INTO good_translations(good_id, locale, name, description, updated_by)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (good_id, locale) DO UPDATE
+			SET name=EXCLUDED.name,
+			    description=EXCLUDED.description,
+			    updated_at=CURRENT_TIMESTAMP,
+			    updated_by=EXCLUDED.updated_by
+			RETURNING ` + translationColumns
+
+	description := sql.NullString{String: translation.Description.String(), Valid: translation.Description != ""}
+
+	translation, err = scanTranslation(tx.QueryRowContext(ctx, query, id, translation.Locale, translation.Name, description, actor))
+	if err != nil {
+		return nil, fmt.Errorf("failed to set translation: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction while setting translation: %w", err)
+	}
+
+	gs.publishTranslationGoodLog(ctx, publisher.GoodEventTranslated, postgresGood, translation, actor)
+
+	return translation, nil
+}
+
+func (gs *PgGoodStorage) RemoveTranslation(ctx context.Context, id domain.GoodId, projectId domain.ProjectId, locale domain.Locale, actor domain.Actor) (*good.Translation, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.RemoveTranslation", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("RemoveTranslation").ObserveDuration()
+
+	tx, err := gs.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction while removing translation: %w", err)
+	}
+	defer tx.Rollback()
+
+	postgresGood, err := shareActiveGood(ctx, tx, id, projectId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove translation: %w", err)
+	}
+
+	query := "DELETE FROM good_translations WHERE good_id=$1 AND locale=$2 RETURNING " + translationColumns
+
+	translation, err := scanTranslation(tx.QueryRowContext(ctx, query, id, locale))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to remove translation: %w", storage.ErrPostgresTranslationNotFound)
+		}
+		return nil, fmt.Errorf("failed to remove translation: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction while removing translation: %w", err)
+	}
+
+	gs.publishTranslationGoodLog(ctx, publisher.GoodEventUntranslated, postgresGood, translation, actor)
+
+	return translation, nil
+}
+
+func (gs *PgGoodStorage) GetDefaultLocale(ctx context.Context, projectId domain.ProjectId) (domain.Locale, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.GetDefaultLocale", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("GetDefaultLocale").ObserveDuration()
+
+	var defaultLocale domain.Locale
+
+	err := gs.db.QueryRowContext(ctx, "SELECT default_locale FROM projects WHERE id=$1", projectId).Scan(&defaultLocale)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("failed to get default locale: %w", storage.ErrPostgresProjectNotFound)
+		}
+		return "", fmt.Errorf("failed to get default locale: %w", err)
+	}
+
+	return defaultLocale, nil
+}
+
+func (gs *PgGoodStorage) SetDefaultLocale(ctx context.Context, projectId domain.ProjectId, locale domain.Locale) (domain.Locale, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.SetDefaultLocale", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("SetDefaultLocale").ObserveDuration()
+
+	var defaultLocale domain.Locale
+
+	err := gs.db.QueryRowContext(ctx, "UPDATE projects SET default_locale=$2 WHERE id=$1 RETURNING default_locale", projectId, locale).Scan(&defaultLocale)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("failed to set default locale: %w", storage.ErrPostgresProjectNotFound)
+		}
+		return "", fmt.Errorf("failed to set default locale: %w", err)
+	}
+
+	return defaultLocale, nil
+}
+
+// publishTranslationGoodLog logs the translated name and description of the good in place of its own.
+func (gs *PgGoodStorage) publishTranslationGoodLog(ctx context.Context, event string, postgresGood *Good, translation *good.Translation, actor domain.Actor) {
+	goodLog := newGoodLog(event, postgresGood, actor.String(), time.Now())
+	goodLog.Locale = translation.Locale.String()
+	goodLog.Name = translation.Name.String()
+	goodLog.Description = translation.Description.String()
+
+	if err := gs.goodLogPublisher.PublishGoodLog(ctx, goodLog); err != nil {
+		slog.ErrorContext(ctx, "failed to publish good log", "goodId", postgresGood.Id, "event", event, "error", err)
+	}
+}
+
+func (gs *PgGoodStorage) queryTranslations(ctx context.Context, query string, args ...any) ([]*good.Translation, error) {
+	rows, err := gs.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	translations := make([]*good.Translation, 0)
+
+	for rows.Next() {
+		translation, err := scanTranslation(rows)
+		if err != nil {
+			return nil, err
+		}
+		translations = append(translations, translation)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return translations, nil
+}
+
+func scanTranslation(row interface{ Scan(dest ...any) error }) (*good.Translation, error) {
+	var (
+		translation good.Translation
+		description sql.NullString
+		updatedAt   sql.NullTime
+		updatedBy   sql.NullString
+	)
+
+	err := row.Scan(
+		&translation.GoodId,
+		&translation.Locale,
+		&translation.Name,
+		&description,
+		&updatedAt,
+		&updatedBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	translation.Description = domain.GoodDescription(description.String)
+	translation.UpdatedAt = updatedAt.Time
+	translation.UpdatedBy = domain.Actor(updatedBy.String)
+
+	return &translation, nil
+}
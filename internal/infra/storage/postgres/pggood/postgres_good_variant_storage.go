@@ -0,0 +1,189 @@
+package pggood
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"github.com/vaberof/hezzl-backend/internal/domain/good"
+	"github.com/vaberof/hezzl-backend/internal/infra/messagebroker/nats/publisher"
+	"github.com/vaberof/hezzl-backend/internal/infra/storage"
+	"github.com/vaberof/hezzl-backend/pkg/domain"
+	"github.com/vaberof/hezzl-backend/pkg/metrics"
+	"go.opentelemetry.io/otel/trace"
+	"time"
+)
+
+// CreateVariant creates a variant of a parent good that is not removed and not a variant itself.
+// The variant belongs to the project of its parent and is prioritized among the other variants of the parent.
+func (gs *PgGoodStorage) CreateVariant(ctx context.Context, parentId domain.GoodId, projectId domain.ProjectId, name domain.GoodName, attributes domain.GoodAttributes, actor domain.Actor) (*good.Good, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.CreateVariant", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("CreateVariant").ObserveDuration()
+
+	attributesJSON, err := attributesParam(attributes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create variant in database: %w", err)
+	}
+
+	tx, err := gs.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction while creating variant: %w", err)
+	}
+	defer tx.Rollback()
+
+	parent, err := shareActiveGood(ctx, tx, parentId, projectId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create variant: %w", err)
+	}
+	if parent.ParentId.Valid {
+		return nil, fmt.Errorf("failed to create variant: %w", storage.ErrPostgresNestedVariant)
+	}
+
+	query := `
+			INSERT INTO goods(
+			                  project_id,
+			                  parent_id,
+			                  name,
+			                  created_by,
+			                  updated_by,
+			                  attributes
+			) VALUES ($1, $2, $3, $4, $4, COALESCE($5::jsonb, '{}'))
+			RETURNING ` + goodColumns
+
+	rows, err := tx.QueryContext(ctx, query, projectId, parentId, name, actor, attributesJSON)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create variant in database: %w", err)
+	}
+
+	postgresGoods, err := scanGoods(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create variant in database: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction while creating variant: %w", err)
+	}
+
+	gs.publishGoodLog(ctx, publisher.GoodEventCreated, postgresGoods[0], postgresGoods[0].CreatedAt)
+
+	return toDomainGood(postgresGoods[0]), nil
+}
+
+// ListVariants lists the variants of the parent goods, removed ones included, grouped by parent in priority order.
+func (gs *PgGoodStorage) ListVariants(ctx context.Context, parentIds []domain.GoodId) ([]*good.Good, error) {
+	ctx, span := tracer.Start(ctx, "PgGoodStorage.ListVariants", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	defer metrics.PostgresQueryTimer("ListVariants").ObserveDuration()
+
+	query := `
+			SELECT ` + goodColumns + `
+			FROM goods
+			WHERE parent_id = ANY($1)
+			ORDER BY parent_id, priority, id
+	`
+
+	rows, err := gs.db.QueryContext(ctx, query, pq.Array(toInt64s(parentIds)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list variants: %w", err)
+	}
+
+	postgresGoods, err := scanGoods(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan while listing variants: %w", err)
+	}
+
+	return toDomainGoods(postgresGoods), nil
+}
+
+// removeVariants removes the variants of the parents that are not removed yet, as of the removal time of their parent.
+func removeVariants(ctx context.Context, tx *sql.Tx, parentIds []int64, actor domain.Actor) ([]*Good, error) {
+	query := `
+		UPDATE goods SET removed=TRUE,
+		                 removed_at=(SELECT parents.removed_at FROM goods AS parents WHERE parents.id = goods.parent_id),
+		                 updated_by=$1,
+		                 version=version+1
+		WHERE parent_id = ANY($2) AND NOT removed
+		RETURNING ` + goodColumns
+
+	rows, err := tx.QueryContext(ctx, query, actor, pq.Array(parentIds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove variants: %w", err)
+	}
+
+	postgresGoods, err := scanGoods(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove variants: %w", err)
+	}
+
+	return postgresGoods, nil
+}
+
+// restoreVariants restores the variants that were removed along with their parent.
+// Variants removed on their own before the parent stay removed.
+func restoreVariants(ctx context.Context, tx *sql.Tx, parentId domain.GoodId, removedAt time.Time, actor domain.Actor) ([]*Good, error) {
+	query := `
+		UPDATE goods SET removed=FALSE,
+		                 removed_at=NULL,
+		                 updated_by=$1,
+		                 version=version+1
+		WHERE parent_id=$2 AND removed AND removed_at=$3
+		RETURNING ` + goodColumns
+
+	rows, err := tx.QueryContext(ctx, query, actor, parentId, removedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore variants: %w", err)
+	}
+
+	postgresGoods, err := scanGoods(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore variants: %w", err)
+	}
+
+	return postgresGoods, nil
+}
+
+// shareParent locks the parent of the good, if the good is a variant, so that the parent is not removed
+// concurrently. Parents are always locked before their variants to avoid deadlocks.
+func shareParent(ctx context.Context, tx *sql.Tx, id domain.GoodId, projectId domain.ProjectId) (*Good, error) {
+	var parentId sql.NullInt64
+
+	// The parent of a good never changes, so it can be read before the good is locked.
+	err := tx.QueryRowContext(ctx, "SELECT parent_id FROM goods WHERE id=$1 AND project_id=$2", id, projectId).Scan(&parentId)
+	if err != nil {
+		// A missing good is reported when the good itself is locked.
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !parentId.Valid {
+		return nil, nil
+	}
+
+	rows, err := tx.QueryContext(ctx, "SELECT "+goodColumns+" FROM goods WHERE id=$1 FOR SHARE", parentId.Int64)
+	if err != nil {
+		return nil, err
+	}
+
+	postgresGoods, err := scanGoods(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(postgresGoods) == 0 {
+		return nil, nil
+	}
+
+	return postgresGoods[0], nil
+}
+
+// withVariants attaches the variants written along with the good.
+func withVariants(domainGood *good.Good, postgresVariants []*Good) *good.Good {
+	if len(postgresVariants) > 0 {
+		domainGood.Variants = toDomainGoods(postgresVariants)
+	}
+	return domainGood
+}
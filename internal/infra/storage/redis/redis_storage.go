@@ -5,9 +5,17 @@ import (
 	"errors"
 	"github.com/redis/go-redis/v9"
 	"github.com/vaberof/hezzl-backend/internal/infra/storage"
+	"github.com/vaberof/hezzl-backend/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"time"
 )
 
+var (
+	tracer            = tracing.Tracer("github.com/vaberof/hezzl-backend/internal/infra/storage/redis")
+	dbSystemAttribute = attribute.String("db.system", "redis")
+)
+
 type RedisStorage struct {
 	client *redis.Client
 }
@@ -16,31 +24,68 @@ func NewRedisStorage(client *redis.Client) *RedisStorage {
 	return &RedisStorage{client: client}
 }
 
-func (rs *RedisStorage) Set(key, value string, exp time.Duration) error {
-	err := rs.client.Set(context.Background(), key, value, exp).Err()
+func (rs *RedisStorage) Set(ctx context.Context, key, value string, exp time.Duration) error {
+	ctx, span := tracer.Start(ctx, "RedisStorage.Set", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	err := rs.client.Set(ctx, key, value, exp).Err()
 	if err != nil {
+		tracing.RecordError(span, err)
 		return err
 	}
 	return nil
 }
 
-func (rs *RedisStorage) Get(key string) (string, error) {
-	val, err := rs.client.Get(context.Background(), key).Result()
+func (rs *RedisStorage) SetIfNotExists(ctx context.Context, key, value string, exp time.Duration) (bool, error) {
+	ctx, span := tracer.Start(ctx, "RedisStorage.SetIfNotExists", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	isSet, err := rs.client.SetNX(ctx, key, value, exp).Result()
+	if err != nil {
+		tracing.RecordError(span, err)
+		return false, err
+	}
+	return isSet, nil
+}
+
+// Increment increments the integer stored at key, starting from 0 if key is missing, and returns the new value.
+func (rs *RedisStorage) Increment(ctx context.Context, key string) (int64, error) {
+	ctx, span := tracer.Start(ctx, "RedisStorage.Increment", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	val, err := rs.client.Incr(ctx, key).Result()
+	if err != nil {
+		tracing.RecordError(span, err)
+		return 0, err
+	}
+	return val, nil
+}
+
+func (rs *RedisStorage) Get(ctx context.Context, key string) (string, error) {
+	ctx, span := tracer.Start(ctx, "RedisStorage.Get", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	val, err := rs.client.Get(ctx, key).Result()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
 			return "", storage.ErrRedisKeyNotFound
 		}
+		tracing.RecordError(span, err)
 		return "", err
 	}
 	return val, nil
 }
 
-func (rs *RedisStorage) Delete(keys ...string) error {
-	_, err := rs.client.Del(context.Background(), keys...).Result()
+func (rs *RedisStorage) Delete(ctx context.Context, keys ...string) error {
+	ctx, span := tracer.Start(ctx, "RedisStorage.Delete", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystemAttribute))
+	defer span.End()
+
+	_, err := rs.client.Del(ctx, keys...).Result()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
 			return storage.ErrRedisKeyNotFound
 		}
+		tracing.RecordError(span, err)
 		return err
 	}
 	return nil
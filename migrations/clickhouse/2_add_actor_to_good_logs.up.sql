@@ -0,0 +1,2 @@
+ALTER TABLE good_logs
+    ADD COLUMN IF NOT EXISTS Actor String;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE good_logs
+    DROP COLUMN IF EXISTS Event;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE good_logs
+    DROP COLUMN IF EXISTS PriceCurrency,
+    DROP COLUMN IF EXISTS PriceAmount;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE good_logs
+    ADD COLUMN IF NOT EXISTS PriceAmount Nullable(Int64),
+    ADD COLUMN IF NOT EXISTS PriceCurrency String;
\ No newline at end of file
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS stock_movements
+(
+    Id        Int64,
+    GoodId    Int64,
+    ProjectId Int64,
+    Kind      String,
+    Quantity  Int64,
+    Stock     Int64,
+    Reserved  Int64,
+    Reason    String,
+    Actor     String,
+    EventTime DateTime
+) ENGINE = MergeTree()
+      ORDER BY (GoodId, EventTime);
\ No newline at end of file
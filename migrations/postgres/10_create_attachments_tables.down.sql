@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS queue_attachment_blob_deletion_trigger ON attachments;
+DROP FUNCTION IF EXISTS queue_attachment_blob_deletion();
+DROP TABLE IF EXISTS attachment_blob_deletions;
+DROP TABLE IF EXISTS attachments;
\ No newline at end of file
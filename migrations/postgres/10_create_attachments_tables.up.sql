@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS attachments
+(
+    id           SERIAL PRIMARY KEY,
+    good_id      INT    NOT NULL REFERENCES goods (id) ON DELETE CASCADE,
+    project_id   INT    NOT NULL REFERENCES projects (id),
+    name         TEXT   NOT NULL,
+    content_type TEXT   NOT NULL,
+    size         BIGINT NOT NULL,
+    checksum     TEXT   NOT NULL,
+    storage_key  TEXT   NOT NULL UNIQUE,
+    created_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    created_by   TEXT
+);
+CREATE INDEX IF NOT EXISTS attachments_good_id_idx ON attachments (good_id);
+
+-- Blobs are deleted outside of the database transactions, so the keys of deleted attachments,
+-- including those deleted along with purged goods, are queued until their blobs are gone.
+CREATE TABLE IF NOT EXISTS attachment_blob_deletions
+(
+    storage_key TEXT PRIMARY KEY,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE OR REPLACE FUNCTION queue_attachment_blob_deletion()
+    RETURNS TRIGGER AS
+$queue_attachment_blob_deletion$
+BEGIN
+    INSERT INTO attachment_blob_deletions (storage_key) VALUES (OLD.storage_key) ON CONFLICT DO NOTHING;
+    RETURN OLD;
+END;
+$queue_attachment_blob_deletion$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER queue_attachment_blob_deletion_trigger
+    AFTER DELETE
+    ON attachments
+    FOR EACH ROW
+EXECUTE FUNCTION queue_attachment_blob_deletion();
\ No newline at end of file
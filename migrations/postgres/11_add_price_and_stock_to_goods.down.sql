@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS stock_movements;
+ALTER TABLE goods
+    DROP CONSTRAINT IF EXISTS reserved_within_stock,
+    DROP CONSTRAINT IF EXISTS non_negative_reserved,
+    DROP CONSTRAINT IF EXISTS non_negative_stock,
+    DROP CONSTRAINT IF EXISTS price_with_currency,
+    DROP CONSTRAINT IF EXISTS non_negative_price,
+    DROP COLUMN IF EXISTS reserved,
+    DROP COLUMN IF EXISTS stock,
+    DROP COLUMN IF EXISTS price_currency,
+    DROP COLUMN IF EXISTS price_amount;
\ No newline at end of file
@@ -0,0 +1,27 @@
+ALTER TABLE goods
+    ADD COLUMN IF NOT EXISTS price_amount   BIGINT,
+    ADD COLUMN IF NOT EXISTS price_currency CHAR(3),
+    ADD COLUMN IF NOT EXISTS stock          BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS reserved       BIGINT NOT NULL DEFAULT 0,
+    ADD CONSTRAINT non_negative_price CHECK (price_amount >= 0),
+    ADD CONSTRAINT price_with_currency CHECK ((price_amount IS NULL) = (price_currency IS NULL)),
+    ADD CONSTRAINT non_negative_stock CHECK (stock >= 0),
+    ADD CONSTRAINT non_negative_reserved CHECK (reserved >= 0),
+    ADD CONSTRAINT reserved_within_stock CHECK (reserved <= stock);
+
+-- Every change of the stock or the reserved stock of a good is recorded along with the resulting quantities.
+CREATE TABLE IF NOT EXISTS stock_movements
+(
+    id         BIGSERIAL PRIMARY KEY,
+    good_id    INT    NOT NULL REFERENCES goods (id) ON DELETE CASCADE,
+    project_id INT    NOT NULL REFERENCES projects (id),
+    kind       TEXT   NOT NULL,
+    quantity   BIGINT NOT NULL,
+    stock      BIGINT NOT NULL,
+    reserved   BIGINT NOT NULL,
+    reason     TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    created_by TEXT,
+    CONSTRAINT stock_movement_kind CHECK (kind IN ('reserve', 'release', 'adjust'))
+);
+CREATE INDEX IF NOT EXISTS stock_movements_good_id_idx ON stock_movements (good_id, id);
\ No newline at end of file
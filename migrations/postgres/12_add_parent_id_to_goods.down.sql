@@ -0,0 +1,13 @@
+CREATE OR REPLACE FUNCTION set_priority_on_insert()
+    RETURNS TRIGGER AS
+$set_priority_on_insert$
+BEGIN
+    NEW.priority = (SELECT COALESCE(MAX(priority), 0) + 1 FROM goods);
+    RETURN NEW;
+END;
+$set_priority_on_insert$ LANGUAGE plpgsql;
+
+DROP INDEX IF EXISTS parent_id_idx;
+ALTER TABLE goods
+    DROP CONSTRAINT IF EXISTS not_own_parent,
+    DROP COLUMN IF EXISTS parent_id;
\ No newline at end of file
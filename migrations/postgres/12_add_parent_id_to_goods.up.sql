@@ -0,0 +1,18 @@
+ALTER TABLE goods
+    ADD COLUMN IF NOT EXISTS parent_id INT REFERENCES goods (id) ON DELETE CASCADE,
+    ADD CONSTRAINT not_own_parent CHECK (parent_id <> id);
+CREATE INDEX IF NOT EXISTS parent_id_idx ON goods (parent_id);
+
+-- Goods are prioritized among the goods that are not variants, variants among the variants of their parent.
+CREATE OR REPLACE FUNCTION set_priority_on_insert()
+    RETURNS TRIGGER AS
+$set_priority_on_insert$
+BEGIN
+    IF NEW.parent_id IS NULL THEN
+        NEW.priority = (SELECT COALESCE(MAX(priority), 0) + 1 FROM goods WHERE parent_id IS NULL);
+    ELSE
+        NEW.priority = (SELECT COALESCE(MAX(priority), 0) + 1 FROM goods WHERE parent_id = NEW.parent_id);
+    END IF;
+    RETURN NEW;
+END;
+$set_priority_on_insert$ LANGUAGE plpgsql;
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS good_translations;
+ALTER TABLE projects
+    DROP COLUMN IF EXISTS default_locale;
\ No newline at end of file
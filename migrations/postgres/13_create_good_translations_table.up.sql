@@ -0,0 +1,13 @@
+ALTER TABLE projects
+    ADD COLUMN IF NOT EXISTS default_locale TEXT NOT NULL DEFAULT 'ru';
+
+CREATE TABLE IF NOT EXISTS good_translations
+(
+    good_id     INT  NOT NULL REFERENCES goods (id) ON DELETE CASCADE,
+    locale      TEXT NOT NULL,
+    name        TEXT NOT NULL,
+    description TEXT,
+    updated_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_by  TEXT,
+    PRIMARY KEY (good_id, locale)
+);
\ No newline at end of file
@@ -0,0 +1,12 @@
+CREATE OR REPLACE FUNCTION queue_attachment_blob_deletion()
+    RETURNS TRIGGER AS
+$queue_attachment_blob_deletion$
+BEGIN
+    INSERT INTO attachment_blob_deletions (storage_key) VALUES (OLD.storage_key) ON CONFLICT DO NOTHING;
+    RETURN OLD;
+END;
+$queue_attachment_blob_deletion$ LANGUAGE plpgsql;
+
+DROP INDEX IF EXISTS attachments_storage_key_idx;
+ALTER TABLE attachments
+    ADD CONSTRAINT attachments_storage_key_key UNIQUE (storage_key);
\ No newline at end of file
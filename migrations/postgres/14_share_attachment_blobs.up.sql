@@ -0,0 +1,16 @@
+-- Copied attachments share the blob of their original, so a blob is only queued for deletion
+-- once the last attachment referring to it is deleted.
+ALTER TABLE attachments
+    DROP CONSTRAINT IF EXISTS attachments_storage_key_key;
+CREATE INDEX IF NOT EXISTS attachments_storage_key_idx ON attachments (storage_key);
+
+CREATE OR REPLACE FUNCTION queue_attachment_blob_deletion()
+    RETURNS TRIGGER AS
+$queue_attachment_blob_deletion$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM attachments WHERE storage_key = OLD.storage_key) THEN
+        INSERT INTO attachment_blob_deletions (storage_key) VALUES (OLD.storage_key) ON CONFLICT DO NOTHING;
+    END IF;
+    RETURN OLD;
+END;
+$queue_attachment_blob_deletion$ LANGUAGE plpgsql;
\ No newline at end of file
@@ -0,0 +1,12 @@
+CREATE OR REPLACE FUNCTION set_priority_on_insert()
+    RETURNS TRIGGER AS
+$set_priority_on_insert$
+BEGIN
+    IF NEW.parent_id IS NULL THEN
+        NEW.priority = (SELECT COALESCE(MAX(priority), 0) + 1 FROM goods WHERE parent_id IS NULL);
+    ELSE
+        NEW.priority = (SELECT COALESCE(MAX(priority), 0) + 1 FROM goods WHERE parent_id = NEW.parent_id);
+    END IF;
+    RETURN NEW;
+END;
+$set_priority_on_insert$ LANGUAGE plpgsql;
\ No newline at end of file
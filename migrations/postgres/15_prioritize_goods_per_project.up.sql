@@ -0,0 +1,13 @@
+-- Goods are prioritized within their project, as they are when moved into another project.
+CREATE OR REPLACE FUNCTION set_priority_on_insert()
+    RETURNS TRIGGER AS
+$set_priority_on_insert$
+BEGIN
+    IF NEW.parent_id IS NULL THEN
+        NEW.priority = (SELECT COALESCE(MAX(priority), 0) + 1 FROM goods WHERE project_id = NEW.project_id AND parent_id IS NULL);
+    ELSE
+        NEW.priority = (SELECT COALESCE(MAX(priority), 0) + 1 FROM goods WHERE parent_id = NEW.parent_id);
+    END IF;
+    RETURN NEW;
+END;
+$set_priority_on_insert$ LANGUAGE plpgsql;
\ No newline at end of file
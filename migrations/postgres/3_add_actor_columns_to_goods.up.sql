@@ -0,0 +1,3 @@
+ALTER TABLE goods
+    ADD COLUMN IF NOT EXISTS created_by TEXT,
+    ADD COLUMN IF NOT EXISTS updated_by TEXT;
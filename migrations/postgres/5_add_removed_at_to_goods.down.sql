@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS removed_at_idx;
+ALTER TABLE goods
+    DROP COLUMN IF EXISTS removed_at;
\ No newline at end of file
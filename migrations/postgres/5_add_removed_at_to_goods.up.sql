@@ -0,0 +1,4 @@
+ALTER TABLE goods
+    ADD COLUMN IF NOT EXISTS removed_at TIMESTAMP;
+UPDATE goods SET removed_at = CURRENT_TIMESTAMP WHERE removed AND removed_at IS NULL;
+CREATE INDEX IF NOT EXISTS removed_at_idx ON goods (removed_at) WHERE removed;
\ No newline at end of file
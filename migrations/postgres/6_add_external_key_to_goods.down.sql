@@ -0,0 +1,4 @@
+ALTER TABLE goods
+    DROP CONSTRAINT IF EXISTS goods_project_id_external_key_key;
+ALTER TABLE goods
+    DROP COLUMN IF EXISTS external_key;
\ No newline at end of file
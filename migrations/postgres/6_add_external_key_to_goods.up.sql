@@ -0,0 +1,4 @@
+ALTER TABLE goods
+    ADD COLUMN IF NOT EXISTS external_key TEXT;
+ALTER TABLE goods
+    ADD CONSTRAINT goods_project_id_external_key_key UNIQUE (project_id, external_key);
\ No newline at end of file
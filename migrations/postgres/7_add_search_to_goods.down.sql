@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS name_trgm_idx;
+DROP INDEX IF EXISTS search_vector_idx;
+DROP TRIGGER IF EXISTS set_search_vector_trigger ON goods;
+DROP FUNCTION IF EXISTS set_search_vector();
+ALTER TABLE goods
+    DROP COLUMN IF EXISTS search_vector;
\ No newline at end of file
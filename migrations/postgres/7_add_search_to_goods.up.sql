@@ -0,0 +1,28 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE goods
+    ADD COLUMN IF NOT EXISTS search_vector TSVECTOR;
+
+-- The 'simple' configuration does not stem, so that names in any language are matched the same way.
+CREATE OR REPLACE FUNCTION set_search_vector()
+    RETURNS TRIGGER AS
+$set_search_vector$
+BEGIN
+    NEW.search_vector = setweight(to_tsvector('simple', COALESCE(NEW.name, '')), 'A') ||
+                        setweight(to_tsvector('simple', COALESCE(NEW.description, '')), 'B');
+    RETURN NEW;
+END;
+$set_search_vector$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER set_search_vector_trigger
+    BEFORE INSERT OR UPDATE OF name, description
+    ON goods
+    FOR EACH ROW
+EXECUTE FUNCTION set_search_vector();
+
+UPDATE goods
+SET search_vector = setweight(to_tsvector('simple', COALESCE(name, '')), 'A') ||
+                    setweight(to_tsvector('simple', COALESCE(description, '')), 'B');
+
+CREATE INDEX IF NOT EXISTS search_vector_idx ON goods USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS name_trgm_idx ON goods USING GIN (name gin_trgm_ops);
\ No newline at end of file
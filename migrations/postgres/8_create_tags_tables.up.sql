@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS tags
+(
+    id         SERIAL PRIMARY KEY,
+    project_id INT  NOT NULL REFERENCES projects (id),
+    name       TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT tags_project_id_name_key UNIQUE (project_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS good_tags
+(
+    good_id    INT NOT NULL REFERENCES goods (id) ON DELETE CASCADE,
+    tag_id     INT NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    created_by TEXT,
+    PRIMARY KEY (good_id, tag_id)
+);
+CREATE INDEX IF NOT EXISTS good_tags_tag_id_idx ON good_tags (tag_id);
\ No newline at end of file
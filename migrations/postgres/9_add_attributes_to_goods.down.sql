@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS project_attribute_schemas;
+DROP INDEX IF EXISTS attributes_idx;
+ALTER TABLE goods
+    DROP COLUMN IF EXISTS attributes;
\ No newline at end of file
@@ -0,0 +1,11 @@
+ALTER TABLE goods
+    ADD COLUMN IF NOT EXISTS attributes JSONB NOT NULL DEFAULT '{}';
+CREATE INDEX IF NOT EXISTS attributes_idx ON goods USING GIN (attributes jsonb_path_ops);
+
+CREATE TABLE IF NOT EXISTS project_attribute_schemas
+(
+    project_id INT   NOT NULL PRIMARY KEY REFERENCES projects (id),
+    schema     JSONB NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_by TEXT
+);
\ No newline at end of file
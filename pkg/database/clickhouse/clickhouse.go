@@ -1,24 +1,51 @@
 package clickhouse
 
 import (
+	"context"
 	"fmt"
 	"github.com/ClickHouse/clickhouse-go/v2"
 	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/vaberof/hezzl-backend/pkg/logging"
+	"github.com/vaberof/hezzl-backend/pkg/retry"
+	"log/slog"
+	"time"
 )
 
 type Config struct {
-	Host     string
-	Port     int
-	Database string
-	User     string
-	Password string
+	Host            string        `yaml:"host"`
+	Port            int           `yaml:"port"`
+	Database        string        `yaml:"database"`
+	User            string        `yaml:"user"`
+	Password        string        `yaml:"password"`
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	DialTimeout     time.Duration `yaml:"dial_timeout"`
+	Retry           retry.Config  `yaml:"retry"`
+}
+
+// LogValue hides the password when the config is logged.
+func (config Config) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("host", config.Host),
+		slog.Int("port", config.Port),
+		slog.String("database", config.Database),
+		slog.String("user", config.User),
+		slog.String("password", logging.RedactedString(config.Password)),
+		slog.Int("maxOpenConns", config.MaxOpenConns),
+		slog.Int("maxIdleConns", config.MaxIdleConns),
+		slog.Duration("connMaxLifetime", config.ConnMaxLifetime),
+		slog.Duration("dialTimeout", config.DialTimeout),
+		slog.Any("retry", config.Retry),
+	)
 }
 
 type ManagedDatabase struct {
 	ClickHouseDb driver.Conn
 }
 
-func New(config *Config) (*ManagedDatabase, error) {
+// New opens a ClickHouse connection pool; zero pool settings keep the driver defaults.
+func New(ctx context.Context, config *Config) (*ManagedDatabase, error) {
 	conn, err := clickhouse.Open(&clickhouse.Options{
 		Addr: []string{fmt.Sprintf("%s:%d", config.Host, config.Port)},
 		Auth: clickhouse.Auth{
@@ -26,11 +53,21 @@ func New(config *Config) (*ManagedDatabase, error) {
 			Username: config.User,
 			Password: config.Password,
 		},
+		MaxOpenConns:    config.MaxOpenConns,
+		MaxIdleConns:    config.MaxIdleConns,
+		ConnMaxLifetime: config.ConnMaxLifetime,
+		DialTimeout:     config.DialTimeout,
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	err = retry.Do(ctx, &config.Retry, "connect to clickhouse", conn.Ping)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
 	managedDatabase := &ManagedDatabase{
 		ClickHouseDb: conn,
 	}
@@ -38,6 +75,10 @@ func New(config *Config) (*ManagedDatabase, error) {
 	return managedDatabase, nil
 }
 
+func (db *ManagedDatabase) Ping(ctx context.Context) error {
+	return db.ClickHouseDb.Ping(ctx)
+}
+
 func (db *ManagedDatabase) Disconnect() error {
 	return db.ClickHouseDb.Close()
 }
@@ -1,32 +1,74 @@
 package postgres
 
 import (
+	"context"
 	"fmt"
 	"github.com/jmoiron/sqlx"
+	"github.com/vaberof/hezzl-backend/pkg/logging"
+	"github.com/vaberof/hezzl-backend/pkg/retry"
+	"log/slog"
+	"time"
 
 	_ "github.com/lib/pq"
 )
 
 type Config struct {
-	Host     string
-	Port     int
-	Database string
-	User     string
-	Password string
+	Host            string        `yaml:"host"`
+	Port            int           `yaml:"port"`
+	Database        string        `yaml:"database"`
+	User            string        `yaml:"user"`
+	Password        string        `yaml:"password"`
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time"`
+	Retry           retry.Config  `yaml:"retry"`
+}
+
+// LogValue hides the password when the config is logged.
+func (config Config) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("host", config.Host),
+		slog.Int("port", config.Port),
+		slog.String("database", config.Database),
+		slog.String("user", config.User),
+		slog.String("password", logging.RedactedString(config.Password)),
+		slog.Int("maxOpenConns", config.MaxOpenConns),
+		slog.Int("maxIdleConns", config.MaxIdleConns),
+		slog.Duration("connMaxLifetime", config.ConnMaxLifetime),
+		slog.Duration("connMaxIdleTime", config.ConnMaxIdleTime),
+		slog.Any("retry", config.Retry),
+	)
 }
 
 type ManagedDatabase struct {
 	PostgresDb *sqlx.DB
 }
 
-func New(config *Config) (*ManagedDatabase, error) {
+func New(ctx context.Context, config *Config) (*ManagedDatabase, error) {
 	psqlUrl := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable", config.Host, config.Port, config.User, config.Password, config.Database)
 
-	psqlDb, err := sqlx.Connect("postgres", psqlUrl)
+	psqlDb, err := sqlx.Open("postgres", psqlUrl)
 	if err != nil {
 		return nil, err
 	}
 
+	// Zero values keep the database/sql defaults.
+	if config.MaxOpenConns > 0 {
+		psqlDb.SetMaxOpenConns(config.MaxOpenConns)
+	}
+	if config.MaxIdleConns > 0 {
+		psqlDb.SetMaxIdleConns(config.MaxIdleConns)
+	}
+	psqlDb.SetConnMaxLifetime(config.ConnMaxLifetime)
+	psqlDb.SetConnMaxIdleTime(config.ConnMaxIdleTime)
+
+	err = retry.Do(ctx, &config.Retry, "connect to postgres", psqlDb.PingContext)
+	if err != nil {
+		psqlDb.Close()
+		return nil, err
+	}
+
 	managedDatabase := &ManagedDatabase{
 		PostgresDb: psqlDb,
 	}
@@ -34,6 +76,10 @@ func New(config *Config) (*ManagedDatabase, error) {
 	return managedDatabase, nil
 }
 
+func (db *ManagedDatabase) Ping(ctx context.Context) error {
+	return db.PostgresDb.PingContext(ctx)
+}
+
 func (db *ManagedDatabase) Disconnect() error {
 	return db.PostgresDb.Close()
 }
@@ -1,23 +1,51 @@
 package redis
 
 import (
+	"context"
 	"fmt"
 	"github.com/redis/go-redis/v9"
+	"github.com/vaberof/hezzl-backend/pkg/logging"
+	"github.com/vaberof/hezzl-backend/pkg/retry"
+	"log/slog"
+	"time"
 )
 
 type Config struct {
-	Host     string
-	Port     int
-	Database int
-	User     string
-	Password string
+	Host            string        `yaml:"host"`
+	Port            int           `yaml:"port"`
+	Database        int           `yaml:"database"`
+	User            string        `yaml:"user"`
+	Password        string        `yaml:"password"`
+	PoolSize        int           `yaml:"pool_size"`
+	MinIdleConns    int           `yaml:"min_idle_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time"`
+	Retry           retry.Config  `yaml:"retry"`
+}
+
+// LogValue hides the password when the config is logged.
+func (config Config) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("host", config.Host),
+		slog.Int("port", config.Port),
+		slog.Int("database", config.Database),
+		slog.String("user", config.User),
+		slog.String("password", logging.RedactedString(config.Password)),
+		slog.Int("poolSize", config.PoolSize),
+		slog.Int("minIdleConns", config.MinIdleConns),
+		slog.Int("maxIdleConns", config.MaxIdleConns),
+		slog.Duration("connMaxLifetime", config.ConnMaxLifetime),
+		slog.Duration("connMaxIdleTime", config.ConnMaxIdleTime),
+		slog.Any("retry", config.Retry),
+	)
 }
 
 type ManagedDatabase struct {
 	RedisDb *redis.Client
 }
 
-func New(config *Config) (*ManagedDatabase, error) {
+func New(ctx context.Context, config *Config) (*ManagedDatabase, error) {
 	redisUrl := fmt.Sprintf("redis://%s:%s@%s:%d/%d?protocol=3", config.User, config.Password, config.Host, config.Port, config.Database)
 
 	opts, err := redis.ParseURL(redisUrl)
@@ -25,8 +53,29 @@ func New(config *Config) (*ManagedDatabase, error) {
 		return nil, err
 	}
 
+	// Zero values keep the go-redis defaults.
+	if config.PoolSize > 0 {
+		opts.PoolSize = config.PoolSize
+	}
+	opts.MinIdleConns = config.MinIdleConns
+	opts.MaxIdleConns = config.MaxIdleConns
+	if config.ConnMaxLifetime > 0 {
+		opts.ConnMaxLifetime = config.ConnMaxLifetime
+	}
+	if config.ConnMaxIdleTime > 0 {
+		opts.ConnMaxIdleTime = config.ConnMaxIdleTime
+	}
+
 	redisDb := redis.NewClient(opts)
 
+	err = retry.Do(ctx, &config.Retry, "connect to redis", func(ctx context.Context) error {
+		return redisDb.Ping(ctx).Err()
+	})
+	if err != nil {
+		redisDb.Close()
+		return nil, err
+	}
+
 	managedDatabase := &ManagedDatabase{
 		RedisDb: redisDb,
 	}
@@ -34,6 +83,10 @@ func New(config *Config) (*ManagedDatabase, error) {
 	return managedDatabase, nil
 }
 
+func (db *ManagedDatabase) Ping(ctx context.Context) error {
+	return db.RedisDb.Ping(ctx).Err()
+}
+
 func (db *ManagedDatabase) Disconnect() error {
 	return db.RedisDb.Close()
 }
@@ -46,3 +46,55 @@ type ProjectId int64
 func (projectId *ProjectId) Int64() int64 {
 	return int64(*projectId)
 }
+
+type Actor string
+
+func (actor *Actor) String() string {
+	if actor == nil {
+		return ""
+	}
+	return string(*actor)
+}
+
+type GoodVersion int64
+
+func (goodVersion *GoodVersion) Int64() int64 {
+	return int64(*goodVersion)
+}
+
+type GoodExternalKey string
+
+func (externalKey *GoodExternalKey) String() string {
+	if externalKey == nil {
+		return ""
+	}
+	return string(*externalKey)
+}
+
+type TagName string
+
+func (tagName *TagName) String() string {
+	return string(*tagName)
+}
+
+// GoodAttributes are the project specific fields of a good, a JSON object.
+type GoodAttributes map[string]any
+
+type AttachmentId int64
+
+func (attachmentId *AttachmentId) Int64() int64 {
+	return int64(*attachmentId)
+}
+
+type StockMovementId int64
+
+func (stockMovementId *StockMovementId) Int64() int64 {
+	return int64(*stockMovementId)
+}
+
+// Locale is a BCP 47 language tag in lower case, such as "en" or "pt-br".
+type Locale string
+
+func (locale *Locale) String() string {
+	return string(*locale)
+}
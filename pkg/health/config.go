@@ -0,0 +1,7 @@
+package health
+
+import "time"
+
+type Config struct {
+	Timeout time.Duration `yaml:"timeout"`
+}
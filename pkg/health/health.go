@@ -0,0 +1,106 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusUp       Status = "up"
+	StatusDown     Status = "down"
+	StatusDegraded Status = "degraded"
+)
+
+const defaultTimeout = 2 * time.Second
+
+// Check describes a dependency probe. A failing critical dependency makes the service down,
+// a failing non-critical one only degrades it.
+type Check struct {
+	Name     string
+	Critical bool
+	Ping     func(ctx context.Context) error
+}
+
+type DependencyReport struct {
+	Status   Status
+	Critical bool
+	Latency  time.Duration
+	Error    string
+}
+
+type Report struct {
+	Status       Status
+	Dependencies map[string]*DependencyReport
+}
+
+type Checker struct {
+	checks  []Check
+	timeout time.Duration
+}
+
+func NewChecker(config *Config, checks ...Check) *Checker {
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &Checker{checks: checks, timeout: timeout}
+}
+
+// Check pings all dependencies concurrently, each bounded by the configured timeout.
+func (c *Checker) Check(ctx context.Context) *Report {
+	dependencyReports := make([]*DependencyReport, len(c.checks))
+
+	var wg sync.WaitGroup
+	for i := range c.checks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			dependencyReports[i] = c.runCheck(ctx, &c.checks[i])
+		}(i)
+	}
+	wg.Wait()
+
+	report := &Report{
+		Status:       StatusUp,
+		Dependencies: make(map[string]*DependencyReport, len(c.checks)),
+	}
+
+	for i := range c.checks {
+		dependencyReport := dependencyReports[i]
+		report.Dependencies[c.checks[i].Name] = dependencyReport
+
+		if dependencyReport.Status == StatusUp {
+			continue
+		}
+		if dependencyReport.Critical {
+			report.Status = StatusDown
+		} else if report.Status == StatusUp {
+			report.Status = StatusDegraded
+		}
+	}
+
+	return report
+}
+
+func (c *Checker) runCheck(ctx context.Context, check *Check) *DependencyReport {
+	checkCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	startedAt := time.Now()
+	err := check.Ping(checkCtx)
+
+	dependencyReport := &DependencyReport{
+		Status:   StatusUp,
+		Critical: check.Critical,
+		Latency:  time.Since(startedAt),
+	}
+	if err != nil {
+		dependencyReport.Status = StatusDown
+		dependencyReport.Error = err.Error()
+	}
+
+	return dependencyReport
+}
@@ -1,10 +1,15 @@
 package httpserver
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
 	"net/http"
+	"os"
 )
 
 type AppServer struct {
@@ -13,25 +18,74 @@ type AppServer struct {
 	config    *ServerConfig
 }
 
-func New(config *ServerConfig) *AppServer {
+func New(config *ServerConfig) (*AppServer, error) {
 	chiRouter := chi.NewRouter()
+	if config.RequestTimeout > 0 {
+		chiRouter.Use(middleware.Timeout(config.RequestTimeout))
+	}
 
 	httpServer := &http.Server{
-		Addr:    fmt.Sprintf("%s:%d", config.Host, config.Port),
-		Handler: chiRouter}
+		Addr:              fmt.Sprintf("%s:%d", config.Host, config.Port),
+		Handler:           chiRouter,
+		ReadTimeout:       config.ReadTimeout,
+		ReadHeaderTimeout: config.ReadHeaderTimeout,
+		WriteTimeout:      config.WriteTimeout,
+		IdleTimeout:       config.IdleTimeout,
+		MaxHeaderBytes:    config.MaxHeaderBytes,
+	}
+
+	if config.TLS.Enabled {
+		tlsConfig, err := newTLSConfig(&config.TLS)
+		if err != nil {
+			return nil, err
+		}
+		httpServer.TLSConfig = tlsConfig
+	}
 
 	return &AppServer{
 		Server:    httpServer,
 		ChiRouter: chiRouter,
 		config:    config,
+	}, nil
+}
+
+func newTLSConfig(config *TLSConfig) (*tls.Config, error) {
+	if config.CertFile == "" || config.KeyFile == "" {
+		return nil, errors.New("tls is enabled but cert_file or key_file is not set")
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if config.ClientCAFile != "" {
+		clientCAs, err := os.ReadFile(config.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA file: %w", err)
+		}
+
+		clientCAPool := x509.NewCertPool()
+		if !clientCAPool.AppendCertsFromPEM(clientCAs) {
+			return nil, fmt.Errorf("no certificates found in client CA file %s", config.ClientCAFile)
+		}
+
+		tlsConfig.ClientCAs = clientCAPool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
 	}
+
+	return tlsConfig, nil
 }
 
 func (server *AppServer) StartAsync() <-chan error {
-	exitChannel := make(chan error)
+	exitChannel := make(chan error, 1)
 
 	go func() {
-		err := server.Server.ListenAndServe()
+		var err error
+		if server.config.TLS.Enabled {
+			err = server.Server.ListenAndServeTLS(server.config.TLS.CertFile, server.config.TLS.KeyFile)
+		} else {
+			err = server.Server.ListenAndServe()
+		}
 		if !errors.Is(err, http.ErrServerClosed) {
 			exitChannel <- err
 			return
@@ -42,3 +96,8 @@ func (server *AppServer) StartAsync() <-chan error {
 
 	return exitChannel
 }
+
+// Shutdown stops accepting connections and waits for in-flight requests until ctx is done.
+func (server *AppServer) Shutdown(ctx context.Context) error {
+	return server.Server.Shutdown(ctx)
+}
@@ -0,0 +1,7 @@
+package lifecycle
+
+import "time"
+
+type Config struct {
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+}
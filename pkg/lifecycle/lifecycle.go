@@ -0,0 +1,76 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+const defaultShutdownTimeout = 15 * time.Second
+
+// Component is a unit managed by the Manager. Both hooks are optional.
+type Component struct {
+	Name  string
+	Start func(ctx context.Context) error
+	Stop  func(ctx context.Context) error
+}
+
+// Manager starts components in the order they are given and stops the started ones in reverse order,
+// so that every component is stopped before the components it depends on.
+type Manager struct {
+	started         []Component
+	shutdownTimeout time.Duration
+}
+
+func NewManager(config *Config) *Manager {
+	shutdownTimeout := config.ShutdownTimeout
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = defaultShutdownTimeout
+	}
+	return &Manager{shutdownTimeout: shutdownTimeout}
+}
+
+// Start runs the component start hook and registers the component for shutdown once it succeeds.
+func (m *Manager) Start(ctx context.Context, component Component) error {
+	if component.Start != nil {
+		if err := component.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start %s: %w", component.Name, err)
+		}
+	}
+
+	slog.InfoContext(ctx, "component started", "component", component.Name)
+
+	m.started = append(m.started, component)
+
+	return nil
+}
+
+// Shutdown stops all started components in reverse order within the configured timeout.
+// Every component gets a chance to stop even if a previous one failed; all failures are returned joined.
+func (m *Manager) Shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), m.shutdownTimeout)
+	defer cancel()
+
+	var errs []error
+
+	for i := len(m.started) - 1; i >= 0; i-- {
+		component := m.started[i]
+		if component.Stop == nil {
+			continue
+		}
+
+		if err := component.Stop(ctx); err != nil {
+			slog.Error("component shutdown failed", "component", component.Name, "error", err)
+			errs = append(errs, fmt.Errorf("failed to stop %s: %w", component.Name, err))
+			continue
+		}
+
+		slog.Info("component stopped", "component", component.Name)
+	}
+
+	m.started = nil
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,6 @@
+package logging
+
+type Config struct {
+	Level  string `yaml:"level"`
+	Format string `yaml:"format"`
+}
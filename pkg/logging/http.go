@@ -0,0 +1,46 @@
+package logging
+
+import (
+	"github.com/go-chi/chi/v5/middleware"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// RequestIdMiddleware assigns every request an id (reusing an incoming X-Request-Id) and echoes it in the response.
+func RequestIdMiddleware(next http.Handler) http.Handler {
+	return middleware.RequestID(http.HandlerFunc(func(rw http.ResponseWriter, request *http.Request) {
+		rw.Header().Set(middleware.RequestIDHeader, middleware.GetReqID(request.Context()))
+		next.ServeHTTP(rw, request)
+	}))
+}
+
+// HttpMiddleware writes an access log line per request. It must run inside the tracing middleware,
+// so that the line is logged with the context of the server span and carries its trace and span ids.
+func HttpMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, request *http.Request) {
+		startedAt := time.Now()
+		wrappedWriter := middleware.NewWrapResponseWriter(rw, request.ProtoMajor)
+
+		next.ServeHTTP(wrappedWriter, request)
+
+		status := wrappedWriter.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		level := slog.LevelInfo
+		if status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+
+		slog.Log(request.Context(), level, "http request",
+			"method", request.Method,
+			"path", request.URL.Path,
+			"status", status,
+			"bytes", wrappedWriter.BytesWritten(),
+			"duration", time.Since(startedAt),
+			"remote_addr", request.RemoteAddr,
+		)
+	})
+}
@@ -0,0 +1,83 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+const (
+	FormatJson = "json"
+	FormatText = "text"
+)
+
+const (
+	requestIdKey = "request_id"
+	traceIdKey   = "trace_id"
+	spanIdKey    = "span_id"
+)
+
+// Redacted replaces secret values in log output.
+const Redacted = "[REDACTED]"
+
+// New builds a leveled logger writing to w in the configured format.
+// Records logged with a request context are enriched with the request, trace and span ids.
+func New(config *Config, w io.Writer) (*slog.Logger, error) {
+	var level slog.Level
+	if config.Level != "" {
+		if err := level.UnmarshalText([]byte(config.Level)); err != nil {
+			return nil, fmt.Errorf("invalid log level %q: %w", config.Level, err)
+		}
+	}
+
+	handlerOptions := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	switch strings.ToLower(config.Format) {
+	case "", FormatJson:
+		handler = slog.NewJSONHandler(w, handlerOptions)
+	case FormatText:
+		handler = slog.NewTextHandler(w, handlerOptions)
+	default:
+		return nil, fmt.Errorf("unknown log format %q", config.Format)
+	}
+
+	return slog.New(&contextHandler{Handler: handler}), nil
+}
+
+// RedactedString returns Redacted for non-empty secrets, so that it is still visible whether a secret was set.
+func RedactedString(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return Redacted
+}
+
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestId := middleware.GetReqID(ctx); requestId != "" {
+		record.AddAttrs(slog.String(requestIdKey, requestId))
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+		record.AddAttrs(slog.String(traceIdKey, spanContext.TraceID().String()))
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasSpanID() {
+		record.AddAttrs(slog.String(spanIdKey, spanContext.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
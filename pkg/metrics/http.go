@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const unmatchedRoute = "unmatched"
+
+// HttpMiddleware records request count and latency labeled by the chi route pattern,
+// so that path parameters do not blow up the label cardinality.
+func HttpMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, request *http.Request) {
+		startedAt := time.Now()
+		wrappedWriter := middleware.NewWrapResponseWriter(rw, request.ProtoMajor)
+
+		next.ServeHTTP(wrappedWriter, request)
+
+		route := unmatchedRoute
+		if routeContext := chi.RouteContext(request.Context()); routeContext != nil {
+			if pattern := routeContext.RoutePattern(); pattern != "" {
+				route = pattern
+			}
+		}
+
+		status := wrappedWriter.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		statusStr := strconv.Itoa(status)
+
+		HttpRequestsTotal.WithLabelValues(request.Method, route, statusStr).Inc()
+		HttpRequestDuration.WithLabelValues(request.Method, route, statusStr).Observe(time.Since(startedAt).Seconds())
+	})
+}
@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+)
+
+const namespace = "hezzl"
+
+const (
+	CacheResultHit  = "hit"
+	CacheResultMiss = "miss"
+
+	StatusOk    = "ok"
+	StatusError = "error"
+)
+
+var (
+	HttpRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Number of handled HTTP requests by route and status.",
+	}, []string{"method", "route", "status"})
+
+	HttpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	PostgresQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "postgres",
+		Name:      "query_duration_seconds",
+		Help:      "Postgres query latency by storage method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+
+	CacheRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "redis",
+		Name:      "cache_requests_total",
+		Help:      "Number of cache lookups by cache name and result (hit or miss).",
+	}, []string{"cache", "result"})
+
+	NatsPublishFailuresTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "nats",
+		Name:      "publish_failures_total",
+		Help:      "Number of failed NATS publishes by subject.",
+	}, []string{"subject"})
+
+	ClickHouseBatchSize = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "clickhouse",
+		Name:      "batch_size",
+		Help:      "Number of rows sent to ClickHouse per batch.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 10),
+	})
+
+	ClickHouseFlushDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "clickhouse",
+		Name:      "flush_duration_seconds",
+		Help:      "ClickHouse batch flush latency by status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"status"})
+)
+
+// Handler returns the HTTP handler serving metrics in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// PostgresQueryTimer starts a timer observing the duration of the given storage method.
+func PostgresQueryTimer(method string) *prometheus.Timer {
+	return prometheus.NewTimer(PostgresQueryDuration.WithLabelValues(method))
+}
+
+// Status converts an operation error to a status label value.
+func Status(err error) string {
+	if err != nil {
+		return StatusError
+	}
+	return StatusOk
+}
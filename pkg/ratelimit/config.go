@@ -0,0 +1,14 @@
+package ratelimit
+
+type Config struct {
+	Enabled bool `yaml:"enabled"`
+	// Routes maps route names to their limits; routes without an entry are not limited.
+	Routes map[string]RouteConfig `yaml:"routes"`
+}
+
+type RouteConfig struct {
+	RequestsPerSecond float64 `yaml:"requests_per_second"`
+	Burst             int     `yaml:"burst"`
+	// KeyBy lists the request attributes the bucket is keyed by: client, apiKey, ip, projectId.
+	KeyBy []string `yaml:"key_by"`
+}
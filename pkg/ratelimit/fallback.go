@@ -0,0 +1,28 @@
+package ratelimit
+
+import (
+	"context"
+	"log/slog"
+)
+
+// FallbackLimiter uses the primary limiter and switches to the fallback one for the requests
+// the primary fails to serve, e.g. while Redis is unavailable.
+type FallbackLimiter struct {
+	primary  Limiter
+	fallback Limiter
+}
+
+func NewFallbackLimiter(primary, fallback Limiter) *FallbackLimiter {
+	return &FallbackLimiter{primary: primary, fallback: fallback}
+}
+
+func (f *FallbackLimiter) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	result, err := f.primary.Allow(ctx, key, limit)
+	if err == nil {
+		return result, nil
+	}
+
+	slog.WarnContext(ctx, "primary rate limiter failed, using fallback", "error", err)
+
+	return f.fallback.Allow(ctx, key, limit)
+}
@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const memorySweepThreshold = 10000
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+	// refillTime is taken from the limit of the last request, since routes limit their buckets differently.
+	refillTime time.Duration
+}
+
+// MemoryLimiter keeps token buckets in process memory. Limits are not shared between replicas.
+type MemoryLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	now     func() time.Time
+}
+
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+func (m *MemoryLimiter) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	if !limit.valid() {
+		return &Result{Allowed: true}, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+
+	if len(m.buckets) >= memorySweepThreshold {
+		m.sweep(now)
+	}
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updatedAt: now}
+		m.buckets[key] = b
+	}
+
+	elapsed := now.Sub(b.updatedAt).Seconds()
+	b.tokens = min(float64(limit.Burst), b.tokens+elapsed*limit.Rate)
+	b.updatedAt = now
+	b.refillTime = limit.refillTime()
+
+	if b.tokens < 1 {
+		return &Result{Allowed: false, RetryAfter: limit.retryAfter(b.tokens)}, nil
+	}
+
+	b.tokens--
+
+	return &Result{Allowed: true, Remaining: int(b.tokens)}, nil
+}
+
+// sweep drops buckets that have been idle long enough to be full again.
+func (m *MemoryLimiter) sweep(now time.Time) {
+	for key, b := range m.buckets {
+		if now.Sub(b.updatedAt) > b.refillTime {
+			delete(m.buckets, key)
+		}
+	}
+}
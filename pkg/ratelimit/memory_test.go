@@ -0,0 +1,138 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestMemoryLimiter(clock *fakeClock) *MemoryLimiter {
+	limiter := NewMemoryLimiter()
+	limiter.now = func() time.Time { return clock.now }
+	return limiter
+}
+
+func TestMemoryLimiterTokenBucket(t *testing.T) {
+	ctx := context.Background()
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	limiter := newTestMemoryLimiter(clock)
+	limit := Limit{Rate: 2, Burst: 3}
+
+	for i := 0; i < limit.Burst; i++ {
+		result, err := limiter.Allow(ctx, "key", limit)
+		if err != nil {
+			t.Fatalf("allow: %v", err)
+		}
+		if !result.Allowed {
+			t.Fatalf("request %d within the burst is rejected", i+1)
+		}
+		if want := limit.Burst - i - 1; result.Remaining != want {
+			t.Errorf("request %d: got %d remaining, want %d", i+1, result.Remaining, want)
+		}
+	}
+
+	result, _ := limiter.Allow(ctx, "key", limit)
+	if result.Allowed {
+		t.Fatal("request over the burst is allowed")
+	}
+	if want := 500 * time.Millisecond; result.RetryAfter != want {
+		t.Errorf("got retry after %v, want %v", result.RetryAfter, want)
+	}
+
+	if result, _ = limiter.Allow(ctx, "other", limit); !result.Allowed {
+		t.Error("exhausted bucket limits another key")
+	}
+
+	clock.advance(500 * time.Millisecond)
+
+	if result, _ = limiter.Allow(ctx, "key", limit); !result.Allowed {
+		t.Error("request after the refill of a token is rejected")
+	}
+	if result, _ = limiter.Allow(ctx, "key", limit); result.Allowed {
+		t.Error("refill grants more than one token")
+	}
+}
+
+func TestMemoryLimiterIgnoresInvalidLimits(t *testing.T) {
+	limiter := newTestMemoryLimiter(&fakeClock{})
+
+	for _, limit := range []Limit{{}, {Rate: 1}, {Burst: 1}} {
+		result, err := limiter.Allow(context.Background(), "key", limit)
+		if err != nil || !result.Allowed {
+			t.Errorf("limit %+v: got %+v, %v, want allowed", limit, result, err)
+		}
+	}
+	if len(limiter.buckets) != 0 {
+		t.Errorf("got %d buckets for invalid limits, want none", len(limiter.buckets))
+	}
+}
+
+func TestMemoryLimiterSweepsBucketsByTheirOwnLimit(t *testing.T) {
+	ctx := context.Background()
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	limiter := newTestMemoryLimiter(clock)
+
+	slow := Limit{Rate: 1.0 / 60, Burst: 1}
+	fast := Limit{Rate: 100, Burst: 100}
+
+	if result, _ := limiter.Allow(ctx, "slow", slow); !result.Allowed {
+		t.Fatal("first request of the slow route is rejected")
+	}
+
+	// The fast route refills in a second. Sweeping the slow bucket by that time would forget its empty bucket.
+	clock.advance(10 * time.Second)
+	for i := 0; len(limiter.buckets) < memorySweepThreshold; i++ {
+		limiter.buckets["idle"+strconv.Itoa(i)] = &bucket{updatedAt: clock.now}
+	}
+	if _, err := limiter.Allow(ctx, "fast", fast); err != nil {
+		t.Fatalf("allow: %v", err)
+	}
+
+	if result, _ := limiter.Allow(ctx, "slow", slow); result.Allowed {
+		t.Error("slow bucket was swept before it refilled")
+	}
+}
+
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	return nil, errors.New("redis is unavailable")
+}
+
+func TestFallbackLimiter(t *testing.T) {
+	ctx := context.Background()
+	limit := Limit{Rate: 1, Burst: 1}
+
+	primary := newTestMemoryLimiter(&fakeClock{})
+	fallback := newTestMemoryLimiter(&fakeClock{})
+
+	if result, _ := NewFallbackLimiter(primary, fallback).Allow(ctx, "key", limit); !result.Allowed {
+		t.Fatal("request is rejected")
+	}
+	if len(fallback.buckets) != 0 {
+		t.Error("fallback is used while the primary limiter works")
+	}
+
+	limiter := NewFallbackLimiter(failingLimiter{}, fallback)
+
+	result, err := limiter.Allow(ctx, "key", limit)
+	if err != nil {
+		t.Fatalf("got error %v while the fallback works", err)
+	}
+	if !result.Allowed {
+		t.Fatal("first request over the fallback is rejected")
+	}
+	if result, _ = limiter.Allow(ctx, "key", limit); result.Allowed {
+		t.Error("fallback does not limit requests")
+	}
+}
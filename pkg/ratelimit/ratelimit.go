@@ -0,0 +1,46 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	KeyByClient    = "client"
+	KeyByApiKey    = "apiKey"
+	KeyByIp        = "ip"
+	KeyByProjectId = "projectId"
+)
+
+// Limit describes a token bucket refilled at Rate tokens per second and holding at most Burst tokens.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+type Result struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit Limit) (*Result, error)
+}
+
+func (l Limit) valid() bool {
+	return l.Rate > 0 && l.Burst > 0
+}
+
+// refillTime returns how long it takes to refill an empty bucket.
+func (l Limit) refillTime() time.Duration {
+	return time.Duration(float64(l.Burst) / l.Rate * float64(time.Second))
+}
+
+// retryAfter returns how long it takes to refill the missing fraction of a token.
+func (l Limit) retryAfter(tokens float64) time.Duration {
+	if tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - tokens) / l.Rate * float64(time.Second))
+}
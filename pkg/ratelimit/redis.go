@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"context"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+)
+
+const redisKeyPrefix = "rate_limit_"
+
+// tokenBucketScript refills and takes a token atomically using the Redis server clock,
+// so that replicas with skewed clocks share the same buckets consistently.
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(bucket[1])
+local ts = tonumber(bucket[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate / 1000)
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate * 1000) + 1000)
+
+return {allowed, tostring(tokens)}
+`)
+
+// RedisLimiter keeps token buckets in Redis so that limits are shared between replicas.
+type RedisLimiter struct {
+	client redis.Scripter
+}
+
+func NewRedisLimiter(client redis.Scripter) *RedisLimiter {
+	return &RedisLimiter{client: client}
+}
+
+func (r *RedisLimiter) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	if !limit.valid() {
+		return &Result{Allowed: true}, nil
+	}
+
+	values, err := tokenBucketScript.Run(ctx, r.client, []string{redisKeyPrefix + key}, limit.Rate, limit.Burst).Slice()
+	if err != nil {
+		return nil, err
+	}
+
+	allowed, _ := values[0].(int64)
+	tokensStr, _ := values[1].(string)
+
+	tokens, err := parseTokens(tokensStr)
+	if err != nil {
+		return nil, err
+	}
+
+	if allowed != 1 {
+		return &Result{Allowed: false, RetryAfter: max(limit.retryAfter(tokens), time.Millisecond)}, nil
+	}
+
+	return &Result{Allowed: true, Remaining: int(tokens)}, nil
+}
+
+func parseTokens(tokens string) (float64, error) {
+	return strconv.ParseFloat(tokens, 64)
+}
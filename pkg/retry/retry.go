@@ -0,0 +1,64 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+)
+
+const (
+	defaultInitialBackoff = 500 * time.Millisecond
+	defaultMaxBackoff     = 30 * time.Second
+)
+
+// Do calls fn until it succeeds, the configured number of attempts is exhausted or ctx is done.
+// Delays between attempts grow exponentially from InitialBackoff up to MaxBackoff with full jitter.
+// A non-positive Attempts means a single attempt.
+func Do(ctx context.Context, config *Config, operation string, fn func(ctx context.Context) error) error {
+	attempts := config.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	initialBackoff := config.InitialBackoff
+	if initialBackoff <= 0 {
+		initialBackoff = defaultInitialBackoff
+	}
+
+	maxBackoff := config.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultMaxBackoff
+	}
+
+	backoff := initialBackoff
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = fn(ctx)
+		if err == nil {
+			return nil
+		}
+
+		if attempt >= attempts {
+			break
+		}
+
+		delay := time.Duration(rand.Int64N(int64(backoff)) + 1)
+
+		slog.WarnContext(ctx, "operation failed, retrying", "operation", operation, "attempt", attempt, "attempts", attempts, "delay", delay, "error", err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%s: %w (last error: %v)", operation, ctx.Err(), err)
+		case <-timer.C:
+		}
+
+		backoff = min(backoff*2, maxBackoff)
+	}
+
+	return fmt.Errorf("%s failed after %d attempts: %w", operation, attempts, err)
+}
@@ -0,0 +1,53 @@
+package tracing
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+)
+
+const httpTracerName = "github.com/vaberof/hezzl-backend/pkg/tracing/http"
+
+// HttpMiddleware starts a server span per request, continuing the trace from the incoming headers.
+// The span is renamed after the chi route pattern once routing has happened.
+func HttpMiddleware(next http.Handler) http.Handler {
+	tracer := Tracer(httpTracerName)
+
+	return http.HandlerFunc(func(rw http.ResponseWriter, request *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(request.Context(), propagation.HeaderCarrier(request.Header))
+
+		ctx, span := tracer.Start(ctx, "HTTP "+request.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", request.Method),
+				attribute.String("url.path", request.URL.Path),
+			),
+		)
+		defer span.End()
+
+		wrappedWriter := middleware.NewWrapResponseWriter(rw, request.ProtoMajor)
+
+		next.ServeHTTP(wrappedWriter, request.WithContext(ctx))
+
+		if routeContext := chi.RouteContext(request.Context()); routeContext != nil {
+			if pattern := routeContext.RoutePattern(); pattern != "" {
+				span.SetName("HTTP " + request.Method + " " + pattern)
+				span.SetAttributes(attribute.String("http.route", pattern))
+			}
+		}
+
+		status := wrappedWriter.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	})
+}